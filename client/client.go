@@ -0,0 +1,199 @@
+// Package client is a Go client for the todo API, so programs can work
+// with todos without writing the HTTP calls themselves.
+//
+//	c := client.NewClient("https://todo.example.com", token)
+//	t, err := c.CreateTodo(ctx, &client.Todo{Title: "Pay rent", Due: "friday"})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls the API on behalf of one token. Its fields may be changed
+// before first use.
+type Client struct {
+	// HTTP sends the requests.
+	HTTP *http.Client
+	// Tenant, if set, is sent in X-Tenant-ID to servers that host several
+	// tenants by header.
+	Tenant string
+	// Retries is how many times a request that can safely be repeated is
+	// retried after a network error, 429 or 5xx other than 500.
+	Retries int
+	// Backoff is the wait before the first retry; it doubles for each one
+	// after, unless the server sends Retry-After, which is honoured up to
+	// Backoff doubled Retries times.
+	Backoff time.Duration
+
+	base  string
+	token string
+}
+
+// NewClient returns a client for the server at baseURL, such as
+// "https://todo.example.com", sending token as a bearer token if it isn't
+// empty.
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		HTTP:    &http.Client{Timeout: 30 * time.Second},
+		Retries: 3,
+		Backoff: 200 * time.Millisecond,
+		base:    strings.TrimRight(baseURL, "/"),
+		token:   token,
+	}
+}
+
+// apiPath is where the server serves the version of the API this client
+// speaks; the paths of requests are relative to it.
+const apiPath = "/api/v1"
+
+// Error is the problem the server answered a request with.
+type Error struct {
+	Status int    `json:"status"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+func (e *Error) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("todo api: %d %s", e.Status, e.Detail)
+	}
+	return fmt.Sprintf("todo api: %d %s", e.Status, e.Title)
+}
+
+// IsNotFound reports whether err is a 404 from the server.
+func IsNotFound(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Status == http.StatusNotFound
+}
+
+// IsConflict reports whether err is a 409 from the server.
+func IsConflict(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Status == http.StatusConflict
+}
+
+// idempotent methods are retried; a POST may have been applied before the
+// failure.
+var idempotent = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodHead:   true,
+	http.MethodPut:    true,
+	http.MethodDelete: true,
+}
+
+// do sends body, if not nil, as JSON and decodes the "data" of the
+// response into out, if not nil, or copies the body to out if it is an
+// io.Writer. It returns the response with its body closed.
+func (c *Client) do(ctx context.Context, method, path string, q url.Values, body, out interface{}) (*http.Response, error) {
+	u := c.base + apiPath + path
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+	wait := c.Backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, u, payload)
+		retry := idempotent[method] && attempt < c.Retries
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err != nil && retry:
+		case err != nil:
+			return nil, err
+		case retry && retryable(resp.StatusCode):
+			if d, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && d >= 0 {
+				// A server asking for longer than the backoff would ever
+				// wait gets no more than that.
+				wait = time.Duration(d) * time.Second
+				if max := c.Backoff << uint(c.Retries); wait > max {
+					wait = max
+				}
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		default:
+			defer resp.Body.Close()
+			return resp, decode(resp, out)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, u string, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	// Ask for the "data" envelope decode reads, whatever the server's
+	// default.
+	req.Header.Set("Accept", `application/json; profile="data"`)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.Tenant != "" {
+		req.Header.Set("X-Tenant-ID", c.Tenant)
+	}
+	return c.HTTP.Do(req)
+}
+
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// decode reads a successful response's data into out, or its body if out
+// is an io.Writer, or the problem of a failed one into an *Error.
+func decode(resp *http.Response, out interface{}) error {
+	if resp.StatusCode >= 400 {
+		e := &Error{Status: resp.StatusCode, Title: http.StatusText(resp.StatusCode)}
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		json.Unmarshal(b, e)
+		e.Status = resp.StatusCode
+		return e
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent || resp.Request.Method == http.MethodHead {
+		return nil
+	}
+	if w, ok := out.(io.Writer); ok {
+		_, err := io.Copy(w, resp.Body)
+		return err
+	}
+	env := struct {
+		Data interface{} `json:"data"`
+	}{Data: out}
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return fmt.Errorf("todo api: decode %s: %w", resp.Request.URL.Path, err)
+	}
+	return nil
+}
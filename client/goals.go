@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Goal groups todos towards an outcome. Its progress is read-only.
+type Goal struct {
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	DueAt       *time.Time `json:"due_at"`
+	Todos       int        `json:"todos"`
+	Done        int        `json:"done"`
+	Percent     int        `json:"percent"`
+	Overdue     bool       `json:"overdue"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ListGoals returns every goal with its progress.
+func (c *Client) ListGoals(ctx context.Context) ([]Goal, error) {
+	var out []Goal
+	_, err := c.do(ctx, http.MethodGet, "/goals", nil, nil, &out)
+	return out, err
+}
+
+// GetGoal returns the goal with id and its progress.
+func (c *Client) GetGoal(ctx context.Context, id string) (*Goal, error) {
+	var out Goal
+	if _, err := c.do(ctx, http.MethodGet, "/goals/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateGoal creates g and returns it as saved.
+func (c *Client) CreateGoal(ctx context.Context, g *Goal) (*Goal, error) {
+	var out Goal
+	if _, err := c.do(ctx, http.MethodPost, "/goals", nil, g, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateGoal replaces the goal g.ID with g and returns it as saved.
+func (c *Client) UpdateGoal(ctx context.Context, g *Goal) (*Goal, error) {
+	var out Goal
+	if _, err := c.do(ctx, http.MethodPut, "/goals/"+url.PathEscape(g.ID), nil, g, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteGoal deletes the goal with id; its todos stay.
+func (c *Client) DeleteGoal(ctx context.Context, id string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/goals/"+url.PathEscape(id), nil, nil, nil)
+	return err
+}
+
+// AttachTodo puts the todo with todoID towards the goal with id.
+func (c *Client) AttachTodo(ctx context.Context, id, todoID string) error {
+	_, err := c.do(ctx, http.MethodPut, "/goals/"+url.PathEscape(id)+"/todos/"+url.PathEscape(todoID), nil, nil, nil)
+	return err
+}
+
+// DetachTodo undoes AttachTodo.
+func (c *Client) DetachTodo(ctx context.Context, id, todoID string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/goals/"+url.PathEscape(id)+"/todos/"+url.PathEscape(todoID), nil, nil, nil)
+	return err
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SmartList is a saved filter. The zero value of each criterion leaves it
+// out.
+type SmartList struct {
+	ID      string   `json:"id,omitempty"`
+	Name    string   `json:"name"`
+	Tags    []string `json:"tags,omitempty"`
+	AnyTags []string `json:"tags_any,omitempty"`
+	NotTags []string `json:"tags_not,omitempty"`
+	List    string   `json:"list,omitempty"`
+	// Completed, if set, keeps only done or only open todos.
+	Completed *bool `json:"completed,omitempty"`
+	// DueWithinDays, if set, keeps todos due in that many days.
+	DueWithinDays *int      `json:"due_within_days,omitempty"`
+	Sort          string    `json:"sort,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ListSmartLists returns every smart list.
+func (c *Client) ListSmartLists(ctx context.Context) ([]SmartList, error) {
+	var out []SmartList
+	_, err := c.do(ctx, http.MethodGet, "/smartlists", nil, nil, &out)
+	return out, err
+}
+
+// GetSmartList returns the smart list with id.
+func (c *Client) GetSmartList(ctx context.Context, id string) (*SmartList, error) {
+	var out SmartList
+	if _, err := c.do(ctx, http.MethodGet, "/smartlists/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateSmartList creates l and returns it as saved.
+func (c *Client) CreateSmartList(ctx context.Context, l *SmartList) (*SmartList, error) {
+	var out SmartList
+	if _, err := c.do(ctx, http.MethodPost, "/smartlists", nil, l, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateSmartList replaces the smart list l.ID with l and returns it as
+// saved.
+func (c *Client) UpdateSmartList(ctx context.Context, l *SmartList) (*SmartList, error) {
+	var out SmartList
+	if _, err := c.do(ctx, http.MethodPut, "/smartlists/"+url.PathEscape(l.ID), nil, l, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteSmartList deletes the smart list with id; its todos stay.
+func (c *Client) DeleteSmartList(ctx context.Context, id string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/smartlists/"+url.PathEscape(id), nil, nil, nil)
+	return err
+}
+
+// SmartListTodos returns the todos the smart list with id selects, in its
+// order, from offset and up to limit of them; 0 means from the start and
+// all.
+func (c *Client) SmartListTodos(ctx context.Context, id string, offset, limit int) ([]Todo, error) {
+	f := Filter{Offset: offset, Limit: limit}
+	var out []Todo
+	_, err := c.do(ctx, http.MethodGet, "/smartlists/"+url.PathEscape(id)+"/todos", f.query(), nil, &out)
+	return out, err
+}
+
+// Field defines a custom field of a list.
+type Field struct {
+	ID   string `json:"id,omitempty"`
+	List string `json:"list"`
+	Name string `json:"name"`
+	// Type is "text", "number", "date" or "select".
+	Type string `json:"type"`
+	// Options are the choices of a select.
+	Options   []string  `json:"options,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListFields returns the custom fields of list, the default list if empty.
+func (c *Client) ListFields(ctx context.Context, list string) ([]Field, error) {
+	q := url.Values{}
+	if list != "" {
+		q.Set("list", list)
+	}
+	var out []Field
+	_, err := c.do(ctx, http.MethodGet, "/fields", q, nil, &out)
+	return out, err
+}
+
+// DefineField adds the custom field f to its list and returns it as saved.
+func (c *Client) DefineField(ctx context.Context, f *Field) (*Field, error) {
+	var out Field
+	if _, err := c.do(ctx, http.MethodPost, "/fields", nil, f, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteField removes the custom field with id along with the values todos
+// hold for it.
+func (c *Client) DeleteField(ctx context.Context, id string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/fields/"+url.PathEscape(id), nil, nil, nil)
+	return err
+}
+
+// Paper sizes for ListPDF.
+const (
+	A4     = "a4"
+	Letter = "letter"
+)
+
+// ListPDF writes list, the default list if empty, to w as a printable PDF
+// checklist on paper ("" for A4); completed adds the done todos, ticked.
+func (c *Client) ListPDF(ctx context.Context, w io.Writer, list, paper string, completed bool) error {
+	if list == "" {
+		list = "_default"
+	}
+	q := url.Values{}
+	if paper != "" {
+		q.Set("paper", paper)
+	}
+	if completed {
+		q.Set("completed", strconv.FormatBool(completed))
+	}
+	_, err := c.do(ctx, http.MethodGet, "/lists/"+url.PathEscape(list)+"/export.pdf", q, nil, w)
+	return err
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Pomodoro is a focus session on a todo.
+type Pomodoro struct {
+	ID           string `json:"id"`
+	TodoID       string `json:"todo_id"`
+	Minutes      int    `json:"minutes"`
+	BreakMinutes int    `json:"break_minutes"`
+	// State is "running", "completed" or "interrupted".
+	State string     `json:"state"`
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end"`
+}
+
+// StartPomodoro starts a session of minutes followed by a break of
+// breakMinutes on the todo with id; 0 leaves either to the server (25 and
+// 5).
+func (c *Client) StartPomodoro(ctx context.Context, id string, minutes, breakMinutes int) (*Pomodoro, error) {
+	body := Pomodoro{Minutes: minutes, BreakMinutes: breakMinutes}
+	var out Pomodoro
+	if _, err := c.do(ctx, http.MethodPost, "/todo/"+url.PathEscape(id)+"/pomodoros", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CompletePomodoro ends the session pid on the todo with id as done, which
+// starts its break.
+func (c *Client) CompletePomodoro(ctx context.Context, id, pid string) (*Pomodoro, error) {
+	return c.endPomodoro(ctx, id, pid, "complete")
+}
+
+// InterruptPomodoro ends the session pid on the todo with id early.
+func (c *Client) InterruptPomodoro(ctx context.Context, id, pid string) (*Pomodoro, error) {
+	return c.endPomodoro(ctx, id, pid, "interrupt")
+}
+
+func (c *Client) endPomodoro(ctx context.Context, id, pid, how string) (*Pomodoro, error) {
+	var out Pomodoro
+	if _, err := c.do(ctx, http.MethodPost, "/todo/"+url.PathEscape(id)+"/pomodoros/"+url.PathEscape(pid)+"/"+how, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PomodoroDay counts the sessions of one day.
+type PomodoroDay struct {
+	Day          string `json:"day"`
+	Completed    int    `json:"completed"`
+	Interrupted  int    `json:"interrupted"`
+	FocusMinutes int    `json:"focus_minutes"`
+}
+
+// PomodoroStats counts sessions per day from the day of from to the day of
+// to, both included, in the tenant's time zone.
+func (c *Client) PomodoroStats(ctx context.Context, from, to time.Time) ([]PomodoroDay, error) {
+	var out []PomodoroDay
+	_, err := c.do(ctx, http.MethodGet, "/todo/pomodoros/stats", period(from, to), nil, &out)
+	return out, err
+}
+
+// period is the query of the reports covering whole days.
+func period(from, to time.Time) url.Values {
+	return url.Values{"from": {from.Format("2006-01-02")}, "to": {to.Format("2006-01-02")}}
+}
@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Activity is one entry of the feed.
+type Activity struct {
+	ID string `json:"id"`
+	// Type is the event, such as "todo.created".
+	Type   string    `json:"type"`
+	TodoID string    `json:"todo_id"`
+	Title  string    `json:"title,omitempty"`
+	List   string    `json:"list,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// Feed returns up to limit entries (0 leaves it to the server) of what
+// happened to the todos, newest first, from cursor or the newest if it is
+// empty. next is the cursor of the following page, empty on the last.
+func (c *Client) Feed(ctx context.Context, cursor string, limit int) (entries []Activity, next string, err error) {
+	q := url.Values{}
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	resp, err := c.do(ctx, http.MethodGet, "/feed", q, nil, &entries)
+	if err != nil {
+		return nil, "", err
+	}
+	return entries, resp.Header.Get("X-Next-Cursor"), nil
+}
+
+// ReportWeek is one week, Monday to Sunday, of the weekly report.
+type ReportWeek struct {
+	// Start is the Monday, YYYY-MM-DD.
+	Start       string `json:"start"`
+	Created     int    `json:"created"`
+	Completed   int    `json:"completed"`
+	Due         int    `json:"due"`
+	CarriedOver int    `json:"carried_over"`
+	// CompletionRate is nil for a week with nothing due.
+	CompletionRate *float64 `json:"completion_rate"`
+}
+
+// TagCount is how many todos with a tag were completed.
+type TagCount struct {
+	Tag       string `json:"tag"`
+	Completed int    `json:"completed"`
+}
+
+// WeeklyReport summarizes a run of weeks.
+type WeeklyReport struct {
+	Weeks       []ReportWeek `json:"weeks"`
+	BusiestTags []TagCount   `json:"busiest_tags"`
+}
+
+// WeeklyReport summarizes the weeks weeks up to the one holding end; 0 and
+// the zero time leave them to the server (4 weeks, up to this one).
+func (c *Client) WeeklyReport(ctx context.Context, weeks int, end time.Time) (*WeeklyReport, error) {
+	q := url.Values{}
+	if weeks > 0 {
+		q.Set("weeks", strconv.Itoa(weeks))
+	}
+	if !end.IsZero() {
+		q.Set("end", end.Format("2006-01-02"))
+	}
+	var out WeeklyReport
+	if _, err := c.do(ctx, http.MethodGet, "/reports/weekly", q, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Ways to total a TimeReport.
+const (
+	ByDay = "day"
+	ByTag = "tag"
+)
+
+// TimeTotal is the time tracked on one day or tag.
+type TimeTotal struct {
+	// Key is the day, YYYY-MM-DD, or the tag.
+	Key     string `json:"key"`
+	Seconds int64  `json:"seconds"`
+}
+
+// TimeReport is the time tracked over a period.
+type TimeReport struct {
+	By     string      `json:"by"`
+	Totals []TimeTotal `json:"totals"`
+	// TotalSeconds is only given by day; by tag, time on several tags
+	// would count more than once.
+	TotalSeconds int64 `json:"total_seconds,omitempty"`
+}
+
+// TimeReport totals the time tracked from the day of from to the day of to,
+// both included, by ByDay ("" too) or ByTag.
+func (c *Client) TimeReport(ctx context.Context, from, to time.Time, by string) (*TimeReport, error) {
+	q := period(from, to)
+	if by != "" {
+		q.Set("by", by)
+	}
+	var out TimeReport
+	if _, err := c.do(ctx, http.MethodGet, "/todo/time", q, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Badge is a milestone reached.
+type Badge struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+// Streaks are the days in a row with a completion.
+type Streaks struct {
+	Current   int     `json:"current"`
+	Longest   int     `json:"longest"`
+	Completed int     `json:"completed"`
+	Today     int     `json:"today"`
+	Badges    []Badge `json:"badges"`
+}
+
+// Streaks returns the completion streaks and the badges earned.
+func (c *Client) Streaks(ctx context.Context) (*Streaks, error) {
+	var out Streaks
+	if _, err := c.do(ctx, http.MethodGet, "/me/streaks", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// WorkloadDay sums the estimates of the open todos due on one day.
+type WorkloadDay struct {
+	Day         string `json:"day"`
+	Minutes     int    `json:"minutes"`
+	Todos       int    `json:"todos"`
+	Unestimated int    `json:"unestimated"`
+	// Over is set when Minutes exceeds the capacity asked for.
+	Over bool `json:"over"`
+}
+
+// Workload sums estimates per due day from the day of from to the day of
+// to, both included, flagging days over capacityMinutes (0 for the
+// server's 480).
+func (c *Client) Workload(ctx context.Context, from, to time.Time, capacityMinutes int) ([]WorkloadDay, error) {
+	q := period(from, to)
+	if capacityMinutes > 0 {
+		q.Set("capacity_minutes", strconv.Itoa(capacityMinutes))
+	}
+	var out []WorkloadDay
+	_, err := c.do(ctx, http.MethodGet, "/todo/workload", q, nil, &out)
+	return out, err
+}
+
+// Column is the todos of one status on the board.
+type Column struct {
+	Status string `json:"status"`
+	Todos  []Todo `json:"todos"`
+}
+
+// Board returns the todos f matches grouped by status, one column per
+// status in workflow order, each oldest first. f's Offset and Limit are
+// ignored.
+func (c *Client) Board(ctx context.Context, f *Filter) ([]Column, error) {
+	q := f.query()
+	q.Del("offset")
+	q.Del("limit")
+	var out []Column
+	_, err := c.do(ctx, http.MethodGet, "/todo/board", q, nil, &out)
+	return out, err
+}
+
+// Slot is a todo on the schedule, ending when it is due and starting its
+// estimate earlier.
+type Slot struct {
+	Todo     Todo      `json:"todo"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Overlaps bool      `json:"overlaps"`
+}
+
+// ScheduleDay is the slots of the todos due on one day.
+type ScheduleDay struct {
+	Day   string `json:"day"`
+	Slots []Slot `json:"slots"`
+}
+
+// Schedule lays out the open todos due from the day of from to the day of
+// to, both included, as a timeline.
+func (c *Client) Schedule(ctx context.Context, from, to time.Time) ([]ScheduleDay, error) {
+	var out []ScheduleDay
+	_, err := c.do(ctx, http.MethodGet, "/todo/schedule", period(from, to), nil, &out)
+	return out, err
+}
+
+// NearbyTodo is a todo whose geofence holds a place.
+type NearbyTodo struct {
+	Todo           Todo `json:"todo"`
+	DistanceMeters int  `json:"distance_m"`
+}
+
+// Nearby returns the open todos whose geofence holds lat, lng, nearest
+// first.
+func (c *Client) Nearby(ctx context.Context, lat, lng float64) ([]NearbyTodo, error) {
+	q := url.Values{
+		"lat": {strconv.FormatFloat(lat, 'f', -1, 64)},
+		"lng": {strconv.FormatFloat(lng, 'f', -1, 64)},
+	}
+	var out []NearbyTodo
+	_, err := c.do(ctx, http.MethodGet, "/todo/nearby", q, nil, &out)
+	return out, err
+}
+
+// SearchArchive finds up to limit archived todos (0 leaves it to the
+// server) whose title matches query, in list if it isn't empty.
+func (c *Client) SearchArchive(ctx context.Context, query, list string, limit int) ([]Todo, error) {
+	q := url.Values{"q": {query}}
+	if list != "" {
+		q.Set("list", list)
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var out []Todo
+	_, err := c.do(ctx, http.MethodGet, "/todo/archive", q, nil, &out)
+	return out, err
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Settings are the tenant's preferences.
+type Settings struct {
+	// Locale is empty to follow each request's Accept-Language.
+	Locale string `json:"locale"`
+	// Locales, read-only, lists the locales the server has messages in.
+	Locales []string `json:"locales,omitempty"`
+	// TimeZone is an IANA zone name; empty for the server's zone.
+	TimeZone  string    `json:"time_zone"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GetSettings returns the tenant's settings.
+func (c *Client) GetSettings(ctx context.Context) (*Settings, error) {
+	var out Settings
+	if _, err := c.do(ctx, http.MethodGet, "/settings", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateSettings replaces the tenant's settings with s and returns them as
+// saved.
+func (c *Client) UpdateSettings(ctx context.Context, s *Settings) (*Settings, error) {
+	var out Settings
+	if _, err := c.do(ctx, http.MethodPut, "/settings", nil, s, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Share is a read-only link to one todo or, when TodoID is empty, a list.
+type Share struct {
+	ID     string `json:"id"`
+	Token  string `json:"token"`
+	TodoID string `json:"todo_id,omitempty"`
+	List   string `json:"list,omitempty"`
+	// URL is the path of the read-only view on the server.
+	URL string `json:"url"`
+	// ExpiresAt, if set, is when the link stops working.
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ListShares returns every share link, oldest first.
+func (c *Client) ListShares(ctx context.Context) ([]Share, error) {
+	var out []Share
+	_, err := c.do(ctx, http.MethodGet, "/shares", nil, nil, &out)
+	return out, err
+}
+
+// ShareTodo makes a link to the todo with id, working until expiresAt if
+// it isn't nil.
+func (c *Client) ShareTodo(ctx context.Context, id string, expiresAt *time.Time) (*Share, error) {
+	return c.share(ctx, &Share{TodoID: id, ExpiresAt: expiresAt})
+}
+
+// ShareList makes a link to list, working until expiresAt if it isn't nil.
+func (c *Client) ShareList(ctx context.Context, list string, expiresAt *time.Time) (*Share, error) {
+	return c.share(ctx, &Share{List: list, ExpiresAt: expiresAt})
+}
+
+func (c *Client) share(ctx context.Context, sh *Share) (*Share, error) {
+	var out Share
+	if _, err := c.do(ctx, http.MethodPost, "/shares", nil, sh, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeShare stops the share with id working.
+func (c *Client) RevokeShare(ctx context.Context, id string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/shares/"+url.PathEscape(id), nil, nil, nil)
+	return err
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Changes is one page of what changed since a cursor.
+type Changes struct {
+	Todos []Todo `json:"todos"`
+	// Deleted are the ids of todos deleted or archived.
+	Deleted []string `json:"deleted"`
+	// Cursor is where the next pull starts.
+	Cursor string `json:"cursor"`
+	// More is set if there are more changes to pull straight away.
+	More bool `json:"more"`
+}
+
+// Pull returns the changes since cursor, or every todo if cursor is empty.
+// A cursor too old to catch up from fails with a 410 *Error; pull again
+// without one.
+func (c *Client) Pull(ctx context.Context, cursor string) (*Changes, error) {
+	q := url.Values{}
+	if cursor != "" {
+		q.Set("since", cursor)
+	}
+	var out Changes
+	if _, err := c.do(ctx, http.MethodGet, "/sync", q, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Change is a change made offline, for Push.
+type Change struct {
+	// Op is "upsert", the default, or "delete".
+	Op string `json:"op,omitempty"`
+	// ClientID names a todo created offline until the server's id for it
+	// is known; pushing its creation twice creates it once.
+	ClientID string `json:"client_id,omitempty"`
+	Edit
+}
+
+// Push applies changes in order, settling conflicts by onConflict ("" for
+// LastWriteWins), and reports on each; a deletion has no Todo.
+func (c *Client) Push(ctx context.Context, changes []Change, onConflict string) ([]Result, error) {
+	q := url.Values{}
+	if onConflict != "" {
+		q.Set("on_conflict", onConflict)
+	}
+	body := struct {
+		Changes []Change `json:"changes"`
+	}{changes}
+	var out []Result
+	_, err := c.do(ctx, http.MethodPost, "/sync", q, body, &out)
+	return out, err
+}
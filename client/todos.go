@@ -0,0 +1,311 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Todo is a todo as the API shows it. Fields marked read-only are ignored
+// on writes.
+type Todo struct {
+	ID        string `json:"id,omitempty"`
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+	// CompletedAt is read-only.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Status, when set, takes precedence over Completed.
+	Status string     `json:"status,omitempty"`
+	List   string     `json:"list"`
+	DueAt  *time.Time `json:"due_at"`
+	// Due is write-only: a due date in words, such as "tomorrow 5pm",
+	// which replaces DueAt.
+	Due             string                 `json:"due,omitempty"`
+	Tags            []string               `json:"tags,omitempty"`
+	Notes           string                 `json:"notes,omitempty"`
+	EstimateMinutes int                    `json:"estimate_minutes,omitempty"`
+	Fields          map[string]interface{} `json:"fields,omitempty"`
+	Geofence        *Geofence              `json:"geofence,omitempty"`
+	// The rest is read-only.
+	GoalID         string            `json:"goal_id,omitempty"`
+	Links          []Link            `json:"links,omitempty"`
+	TrackedSeconds int64             `json:"tracked_seconds,omitempty"`
+	BlockedBy      []string          `json:"blocked_by,omitempty"`
+	Snoozes        []Snooze          `json:"snoozes,omitempty"`
+	Slug           string            `json:"slug,omitempty"`
+	External       map[string]string `json:"external,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// Todo statuses.
+const (
+	StatusBacklog    = "backlog"
+	StatusTodo       = "todo"
+	StatusInProgress = "in_progress"
+	StatusDone       = "done"
+	StatusCancelled  = "cancelled"
+)
+
+// Geofence ties a todo to a place.
+type Geofence struct {
+	Lat          float64 `json:"lat"`
+	Lng          float64 `json:"lng"`
+	RadiusMeters int     `json:"radius_m"`
+}
+
+// Link relates a todo to another.
+type Link struct {
+	Type   string `json:"type"`
+	TodoID string `json:"todo_id"`
+}
+
+// Snooze is one time a todo was put off.
+type Snooze struct {
+	At    time.Time  `json:"at"`
+	From  *time.Time `json:"from"`
+	Until time.Time  `json:"until"`
+}
+
+// Filter narrows ListTodos. The zero Filter matches every todo.
+type Filter struct {
+	// Tags must all be on a todo, AnyTags at least one, NotTags none.
+	Tags, AnyTags, NotTags []string
+	List                   string
+	// Blocked, if set, keeps only blocked or only unblocked todos.
+	Blocked *bool
+	// Fields match custom field values by name.
+	Fields map[string]string
+	// Offset and Limit page the results; 0 means from the start and all.
+	Offset, Limit int
+}
+
+func (f *Filter) query() url.Values {
+	q := url.Values{}
+	if f == nil {
+		return q
+	}
+	set := func(name string, tags []string) {
+		if len(tags) > 0 {
+			q.Set(name, strings.Join(tags, ","))
+		}
+	}
+	set("tags", f.Tags)
+	set("tags_any", f.AnyTags)
+	set("tags_not", f.NotTags)
+	if f.List != "" {
+		q.Set("list", f.List)
+	}
+	if f.Blocked != nil {
+		q.Set("blocked", strconv.FormatBool(*f.Blocked))
+	}
+	for name, v := range f.Fields {
+		q.Set("field."+name, v)
+	}
+	if f.Offset > 0 {
+		q.Set("offset", strconv.Itoa(f.Offset))
+	}
+	if f.Limit > 0 {
+		q.Set("limit", strconv.Itoa(f.Limit))
+	}
+	return q
+}
+
+// ListTodos returns the todos f matches, oldest first.
+func (c *Client) ListTodos(ctx context.Context, f *Filter) ([]Todo, error) {
+	var out []Todo
+	_, err := c.do(ctx, http.MethodGet, "/todo", f.query(), nil, &out)
+	return out, err
+}
+
+// CountTodos returns how many todos f matches; its Offset and Limit are
+// ignored.
+func (c *Client) CountTodos(ctx context.Context, f *Filter) (int64, error) {
+	q := f.query()
+	q.Del("offset")
+	q.Del("limit")
+	resp, err := c.do(ctx, http.MethodHead, "/todo", q, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(resp.Header.Get("X-Total-Count"), 10, 64)
+}
+
+// GetTodo returns the todo with id and its links.
+func (c *Client) GetTodo(ctx context.Context, id string) (*Todo, error) {
+	var out Todo
+	if _, err := c.do(ctx, http.MethodGet, "/todo/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateTodo creates t and returns it as saved.
+func (c *Client) CreateTodo(ctx context.Context, t *Todo) (*Todo, error) {
+	var out Todo
+	if _, err := c.do(ctx, http.MethodPost, "/todo", nil, t, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateTodo replaces the todo t.ID with t and returns it as saved.
+func (c *Client) UpdateTodo(ctx context.Context, t *Todo) (*Todo, error) {
+	var out Todo
+	if _, err := c.do(ctx, http.MethodPut, "/todo/"+url.PathEscape(t.ID), nil, t, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteTodo deletes the todo with id.
+func (c *Client) DeleteTodo(ctx context.Context, id string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/todo/"+url.PathEscape(id), nil, nil, nil)
+	return err
+}
+
+// SearchTodos finds up to limit todos by title, forgiving typos; limit 0
+// leaves it to the server.
+func (c *Client) SearchTodos(ctx context.Context, query string, limit int) ([]Todo, error) {
+	q := url.Values{"q": {query}}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var out []Todo
+	_, err := c.do(ctx, http.MethodGet, "/todo/search", q, nil, &out)
+	return out, err
+}
+
+// Strategies for an update made against an out-of-date todo.
+const (
+	LastWriteWins = "last_write_wins"
+	Merge         = "merge"
+	Reject        = "reject"
+)
+
+// Edit is a todo to write in bulk or push: a new one if it has no ID.
+// An update whose todo changed since Base, or since its own UpdatedAt
+// without one, is settled by the strategy of the call.
+type Edit struct {
+	Todo
+	// Base is the todo as last read, which Merge needs.
+	Base *Todo `json:"base,omitempty"`
+}
+
+// Result reports on one entry of a bulk write or push, with the status a
+// request for it alone would have had.
+type Result struct {
+	Index  int    `json:"index"`
+	Status int    `json:"status"`
+	Todo   *Todo  `json:"todo,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Conflict is set for an update refused by the conflict strategy.
+	Conflict *Conflict `json:"conflict,omitempty"`
+}
+
+// Conflict shows both versions of a todo an update conflicted over.
+type Conflict struct {
+	// Fields both sides changed, when merging.
+	Fields []string `json:"fields,omitempty"`
+	Server Todo     `json:"server"`
+	Client Todo     `json:"client"`
+}
+
+// WriteTodos creates or updates up to 1000 todos in one request, settling
+// conflicts by onConflict ("" for LastWriteWins). Entries fail one by one;
+// see each Result.
+func (c *Client) WriteTodos(ctx context.Context, todos []Edit, onConflict string) ([]Result, error) {
+	q := url.Values{}
+	if onConflict != "" {
+		q.Set("on_conflict", onConflict)
+	}
+	var out []Result
+	_, err := c.do(ctx, http.MethodPost, "/todo/bulk", q, todos, &out)
+	return out, err
+}
+
+// TimeEntry is a tracked stretch of time on a todo.
+type TimeEntry struct {
+	ID     string     `json:"id"`
+	TodoID string     `json:"todo_id"`
+	Start  time.Time  `json:"start"`
+	End    *time.Time `json:"end"`
+	// Seconds is 0 while the timer runs.
+	Seconds int64 `json:"seconds"`
+}
+
+// StartTimer starts tracking time on the todo with id.
+func (c *Client) StartTimer(ctx context.Context, id string) (*TimeEntry, error) {
+	var out TimeEntry
+	if _, err := c.do(ctx, http.MethodPost, "/todo/"+url.PathEscape(id)+"/timer/start", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// StopTimer stops the running timer of the todo with id.
+func (c *Client) StopTimer(ctx context.Context, id string) (*TimeEntry, error) {
+	var out TimeEntry
+	if _, err := c.do(ctx, http.MethodPost, "/todo/"+url.PathEscape(id)+"/timer/stop", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Snooze puts the todo with id off by d, counted from its due date, or
+// from now if it is overdue or has none.
+func (c *Client) Snooze(ctx context.Context, id string, d time.Duration) (*Todo, error) {
+	return c.snooze(ctx, id, map[string]interface{}{"for": d.String()})
+}
+
+// SnoozeUntil makes until the new due date of the todo with id.
+func (c *Client) SnoozeUntil(ctx context.Context, id string, until time.Time) (*Todo, error) {
+	return c.snooze(ctx, id, map[string]interface{}{"until": until})
+}
+
+func (c *Client) snooze(ctx context.Context, id string, body interface{}) (*Todo, error) {
+	var out Todo
+	if _, err := c.do(ctx, http.MethodPost, "/todo/"+url.PathEscape(id)+"/snooze", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddBlocker makes the todo with id wait for blocker.
+func (c *Client) AddBlocker(ctx context.Context, id, blocker string) error {
+	_, err := c.do(ctx, http.MethodPut, "/todo/"+url.PathEscape(id)+"/blocked_by/"+url.PathEscape(blocker), nil, nil, nil)
+	return err
+}
+
+// RemoveBlocker stops the todo with id waiting for blocker.
+func (c *Client) RemoveBlocker(ctx context.Context, id, blocker string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/todo/"+url.PathEscape(id)+"/blocked_by/"+url.PathEscape(blocker), nil, nil, nil)
+	return err
+}
+
+// AddLink relates the todo with id to other by typ, such as "relates_to".
+func (c *Client) AddLink(ctx context.Context, id, typ, other string) error {
+	_, err := c.do(ctx, http.MethodPut, "/todo/"+url.PathEscape(id)+"/links/"+url.PathEscape(typ)+"/"+url.PathEscape(other), nil, nil, nil)
+	return err
+}
+
+// RemoveLink undoes AddLink.
+func (c *Client) RemoveLink(ctx context.Context, id, typ, other string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/todo/"+url.PathEscape(id)+"/links/"+url.PathEscape(typ)+"/"+url.PathEscape(other), nil, nil, nil)
+	return err
+}
+
+// Slug returns the short slug of the todo with id, giving it one if it has
+// none; /t/{slug} leads to the todo.
+func (c *Client) Slug(ctx context.Context, id string) (string, error) {
+	var out struct {
+		Slug string `json:"slug"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, "/todo/"+url.PathEscape(id)+"/slug", nil, nil, &out); err != nil {
+		return "", err
+	}
+	return out.Slug, nil
+}
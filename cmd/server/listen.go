@@ -0,0 +1,28 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+)
+
+// listen opens the listener described by addr. Plain addresses such as
+// ":9000" listen on TCP; "unix:///path/to.sock" listens on a unix socket,
+// replacing a stale socket file left behind by an earlier run.
+func listen(addr string) (net.Listener, error) {
+	if !strings.HasPrefix(addr, "unix://") {
+		return net.Listen("tcp", addr)
+	}
+	path := strings.TrimPrefix(addr, "unix://")
+	if fi, err := os.Stat(path); err == nil {
+		if fi.Mode()&fs.ModeSocket == 0 {
+			return nil, errors.New(path + " exists and is not a socket")
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time" // to implement time functions
+	// Tenants pick their time zone by name, which the host may lack.
+	_ "time/tzdata"
+
+	"dhruvarora9/personal-todo-golang/server"
+)
+
+func checkErr(err error) {
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func main() {
+	cfg, err := server.LoadConfig(configPath())
+	checkErr(err)
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+
+	connectCtx, cancelConnect := context.WithTimeout(context.Background(), 10*time.Second)
+	s, closeStore, err := server.ConnectMongo(connectCtx, cfg.Database)
+	cancelConnect()
+	checkErr(err)
+	h, err := server.NewServer(cfg, s, server.WithLogger(logger))
+	checkErr(err)
+
+	stopChan := make(chan os.Signal, 1)
+	signal.Notify(stopChan, os.Interrupt)
+	ln, err := listen(cfg.Listen)
+	checkErr(err)
+	srv := &http.Server{
+		Handler:      h,
+		ReadTimeout:  60 * time.Second,
+		WriteTimeout: 60 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+	go func() {
+		logger.Println("Listening on", cfg.Listen)
+		if err := srv.Serve(ln); err != nil {
+			logger.Printf("listen:%s\n", err)
+		}
+	}()
+
+	<-stopChan
+	logger.Println("shutting down server...")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	srv.Shutdown(ctx)
+	h.Close(ctx)
+	closeStore(ctx)
+	defer func() {
+		cancel()
+		logger.Println("Server gracefully shut down")
+	}()
+}
+
+// configPath returns the config file location, $TODO_CONFIG or config.yaml.
+func configPath() string {
+	if p := os.Getenv("TODO_CONFIG"); p != "" {
+		return p
+	}
+	return "config.yaml"
+}
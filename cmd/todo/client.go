@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// todo mirrors the API's JSON representation of a todo.
+type todo struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Completed bool     `json:"completed"`
+	List      string   `json:"list"`
+	Tags      []string `json:"tags,omitempty"`
+	// EstimateMinutes and Fields are carried so that updates keep them.
+	EstimateMinutes int                    `json:"estimate_minutes,omitempty"`
+	Fields          map[string]interface{} `json:"fields,omitempty"`
+	DueAt           *time.Time             `json:"due_at"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
+
+// apiPath is where the server serves the API version the CLI speaks.
+const apiPath = "/api/v1"
+
+// apiClient talks to a todo server over HTTP.
+type apiClient struct {
+	base  string
+	token string
+	http  *http.Client
+}
+
+func newClient(base, token string) *apiClient {
+	return &apiClient{
+		base:  strings.TrimRight(base, "/"),
+		token: token,
+		http:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *apiClient) list(ctx context.Context) ([]todo, error) {
+	var todos []todo
+	err := c.do(ctx, http.MethodGet, "/todo", nil, &todos)
+	return todos, err
+}
+
+// search asks the server for todos matching q, typos forgiven.
+func (c *apiClient) search(ctx context.Context, q string) ([]todo, error) {
+	var todos []todo
+	err := c.do(ctx, http.MethodGet, "/todo/search?q="+url.QueryEscape(q), nil, &todos)
+	return todos, err
+}
+
+func (c *apiClient) add(ctx context.Context, title, list string) (*todo, error) {
+	var t todo
+	if err := c.do(ctx, http.MethodPost, "/todo", todo{Title: title, List: list}, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *apiClient) update(ctx context.Context, t todo) (*todo, error) {
+	var out todo
+	if err := c.do(ctx, http.MethodPut, "/todo/"+t.ID, t, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *apiClient) remove(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/todo/"+id, nil, nil)
+}
+
+// do sends body as JSON and decodes the "data" member of the response into
+// out. Problem responses become errors carrying the server's detail.
+func (c *apiClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+apiPath+path, rd)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var p struct {
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&p) == nil && p.Detail != "" {
+			return fmt.Errorf("%s: %s", resp.Status, p.Detail)
+		}
+		return fmt.Errorf("server answered %s", resp.Status)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	env := struct {
+		Data interface{} `json:"data"`
+	}{Data: out}
+	return json.NewDecoder(resp.Body).Decode(&env)
+}
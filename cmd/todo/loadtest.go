@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// loadStats is what one kind of request measured during a load test.
+type loadStats struct {
+	Op       string  `json:"op"`
+	Requests int     `json:"requests"`
+	Errors   int     `json:"errors"`
+	RPS      float64 `json:"rps"`
+	// Latencies are in milliseconds.
+	P50 float64 `json:"p50_ms"`
+	P90 float64 `json:"p90_ms"`
+	P99 float64 `json:"p99_ms"`
+	Max float64 `json:"max_ms"`
+}
+
+// loadRecorder collects latencies from every worker.
+type loadRecorder struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+	lastErr   error
+}
+
+func (r *loadRecorder) record(op string, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.errors[op]++
+		r.lastErr = err
+		return
+	}
+	r.latencies[op] = append(r.latencies[op], d)
+}
+
+func newLoadTestCmd(api func() *apiClient) *cobra.Command {
+	var (
+		concurrency int
+		duration    time.Duration
+		writes      float64
+	)
+	cmd := &cobra.Command{
+		Use:   "loadtest",
+		Short: "Drive the server with concurrent requests and report latencies",
+		Long: `loadtest runs --concurrency workers for --duration. Each request is a
+list, or with probability --writes a write: workers alternate between
+creating a todo and deleting the one they created, so the data set stays
+the same size. Mind the server's rate limit, which counts these requests
+like any others.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if concurrency < 1 {
+				return errors.New("--concurrency must be at least 1")
+			}
+			if writes < 0 || writes > 1 {
+				return errors.New("--writes must be between 0 and 1")
+			}
+			c := api()
+			ctx, cancel := context.WithTimeout(cmd.Context(), duration)
+			defer cancel()
+			rec := &loadRecorder{latencies: map[string][]time.Duration{}, errors: map[string]int{}}
+			var wg sync.WaitGroup
+			start := time.Now()
+			for i := 0; i < concurrency; i++ {
+				wg.Add(1)
+				go func(worker int) {
+					defer wg.Done()
+					loadWorker(ctx, c, rec, worker, writes)
+				}(i)
+			}
+			wg.Wait()
+			stats := summarize(rec, time.Since(start))
+			if err := printLoadStats(cmd.OutOrStdout(), cmd.Flag("output").Value.String(), stats); err != nil {
+				return err
+			}
+			if rec.lastErr != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), "last error:", rec.lastErr)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVarP(&concurrency, "concurrency", "c", 10, "number of concurrent workers")
+	cmd.Flags().DurationVarP(&duration, "duration", "d", 30*time.Second, "how long to run")
+	cmd.Flags().Float64Var(&writes, "writes", 0.2, "fraction of requests that are writes, 0 to 1")
+	return cmd
+}
+
+// loadWorker sends requests until ctx ends, then deletes its leftover todo
+// outside the measurement.
+func loadWorker(ctx context.Context, c *apiClient, rec *loadRecorder, worker int, writes float64) {
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano() + int64(worker)))
+	var created string
+	for n := 0; ctx.Err() == nil; n++ {
+		op := "list"
+		if rnd.Float64() < writes {
+			op = "create"
+			if created != "" {
+				op = "delete"
+			}
+		}
+		start := time.Now()
+		var err error
+		switch op {
+		case "list":
+			_, err = c.list(ctx)
+		case "create":
+			var t *todo
+			t, err = c.add(ctx, fmt.Sprintf("loadtest %d-%d", worker, n), "loadtest")
+			if err == nil {
+				created = t.ID
+			}
+		case "delete":
+			err = c.remove(ctx, created)
+			if err == nil {
+				created = ""
+			}
+		}
+		// Requests cut off by the end of the run don't count.
+		if ctx.Err() != nil {
+			break
+		}
+		rec.record(op, time.Since(start), err)
+	}
+	if created != "" {
+		cleanup, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		c.remove(cleanup, created)
+		cancel()
+	}
+}
+
+func summarize(rec *loadRecorder, elapsed time.Duration) []loadStats {
+	ops := map[string]bool{}
+	for op := range rec.latencies {
+		ops[op] = true
+	}
+	for op := range rec.errors {
+		ops[op] = true
+	}
+	var out []loadStats
+	for op := range ops {
+		lat := rec.latencies[op]
+		sort.Slice(lat, func(i, j int) bool { return lat[i] < lat[j] })
+		s := loadStats{
+			Op:       op,
+			Requests: len(lat) + rec.errors[op],
+			Errors:   rec.errors[op],
+			RPS:      float64(len(lat)+rec.errors[op]) / elapsed.Seconds(),
+		}
+		if len(lat) > 0 {
+			s.P50 = millis(percentile(lat, 50))
+			s.P90 = millis(percentile(lat, 90))
+			s.P99 = millis(percentile(lat, 99))
+			s.Max = millis(lat[len(lat)-1])
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Op < out[j].Op })
+	return out
+}
+
+// percentile picks the nearest-rank percentile p of sorted.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	i := (len(sorted)*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func printLoadStats(w io.Writer, format string, stats []loadStats) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "OP\tREQUESTS\tERRORS\tRPS\tP50 ms\tP90 ms\tP99 ms\tMAX ms\t")
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t\n",
+			s.Op, s.Requests, s.Errors, s.RPS, s.P50, s.P90, s.P99, s.Max)
+	}
+	return tw.Flush()
+}
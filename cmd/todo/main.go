@@ -0,0 +1,161 @@
+// Command todo is a terminal client for the todo API.
+//
+// The server URL and token come from --server/--token or the TODO_SERVER
+// and TODO_TOKEN environment variables.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultServer = "http://localhost:9000"
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	var (
+		server string
+		token  string
+		output string
+	)
+	api := func() *apiClient { return newClient(server, token) }
+
+	root := &cobra.Command{
+		Use:          "todo",
+		Short:        "Manage todos from the terminal",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if output != "table" && output != "json" {
+				return fmt.Errorf("unknown output format %q, expected table or json", output)
+			}
+			return nil
+		},
+	}
+	root.PersistentFlags().StringVar(&server, "server", envOr("TODO_SERVER", defaultServer), "todo server URL")
+	root.PersistentFlags().StringVar(&token, "token", os.Getenv("TODO_TOKEN"), "bearer token sent to the server")
+	root.PersistentFlags().StringVarP(&output, "output", "o", "table", "output format: table or json")
+
+	var pending bool
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List todos",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			todos, err := api().list(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if pending {
+				todos = filter(todos, func(t todo) bool { return !t.Completed })
+			}
+			return printTodos(cmd.OutOrStdout(), output, todos)
+		},
+	}
+	list.Flags().BoolVar(&pending, "pending", false, "only show todos that are not done")
+
+	var addList string
+	add := &cobra.Command{
+		Use:   "add TITLE...",
+		Short: "Add a todo",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := api().add(cmd.Context(), strings.Join(args, " "), addList)
+			if err != nil {
+				return err
+			}
+			return printTodos(cmd.OutOrStdout(), output, []todo{*t})
+		},
+	}
+
+	add.Flags().StringVar(&addList, "list", "", "list to add the todo to")
+
+	done := &cobra.Command{
+		Use:   "done ID",
+		Short: "Mark a todo as done",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := api()
+			t, err := find(cmd.Context(), c, args[0])
+			if err != nil {
+				return err
+			}
+			t.Completed = true
+			if t, err = c.update(cmd.Context(), *t); err != nil {
+				return err
+			}
+			return printTodos(cmd.OutOrStdout(), output, []todo{*t})
+		},
+	}
+
+	rm := &cobra.Command{
+		Use:   "rm ID...",
+		Short: "Delete todos",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := api()
+			for _, id := range args {
+				if err := c.remove(cmd.Context(), id); err != nil {
+					return fmt.Errorf("%s: %w", id, err)
+				}
+			}
+			return nil
+		},
+	}
+
+	search := &cobra.Command{
+		Use:   "search QUERY",
+		Short: "List todos whose title matches QUERY, best match first",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			todos, err := api().search(cmd.Context(), strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			return printTodos(cmd.OutOrStdout(), output, todos)
+		},
+	}
+
+	root.AddCommand(list, add, done, rm, search, newTUICmd(api), newLoadTestCmd(api))
+	return root
+}
+
+// find looks a todo up by ID; the API has no single-todo endpoint, so it
+// searches the list.
+func find(ctx context.Context, c *apiClient, id string) (*todo, error) {
+	todos, err := c.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range todos {
+		if t.ID == id {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("no todo with ID %s", id)
+}
+
+func filter(todos []todo, keep func(todo) bool) []todo {
+	out := todos[:0]
+	for _, t := range todos {
+		if keep(t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
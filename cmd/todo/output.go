@@ -0,0 +1,28 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// printTodos writes todos as an aligned table or, with format "json", as a
+// JSON array for scripts.
+func printTodos(w io.Writer, format string, todos []todo) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(todos)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tDONE\tTITLE\tLIST\tCREATED")
+	for _, t := range todos {
+		done := " "
+		if t.Completed {
+			done = "x"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", t.ID, done, t.Title, t.List, t.CreatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// Messages sent back to the TUI by API calls running in the background.
+type (
+	loadedMsg  []todo
+	changedMsg struct{}
+	errMsg     struct{ err error }
+)
+
+// tuiModel is the interactive todo list: j/k to move, space to toggle, a to
+// add inline, r to reload and q to quit.
+type tuiModel struct {
+	api    *apiClient
+	todos  []todo
+	cursor int
+	adding bool
+	input  textinput.Model
+	err    error
+}
+
+func newTUICmd(api func() *apiClient) *cobra.Command {
+	return &cobra.Command{
+		Use:   "tui",
+		Short: "Browse and edit todos interactively",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in := textinput.New()
+			in.Placeholder = "What needs doing?"
+			_, err := tea.NewProgram(tuiModel{api: api(), input: in}).Run()
+			return err
+		},
+	}
+}
+
+func (m tuiModel) Init() tea.Cmd {
+	return m.load
+}
+
+func (m tuiModel) load() tea.Msg {
+	todos, err := m.api.list(context.Background())
+	if err != nil {
+		return errMsg{err}
+	}
+	return loadedMsg(todos)
+}
+
+// call runs fn in the background and reloads the list once it succeeds.
+func (m tuiModel) call(fn func(ctx context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		if err := fn(context.Background()); err != nil {
+			return errMsg{err}
+		}
+		return changedMsg{}
+	}
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.todos, m.err = msg, nil
+		if m.cursor >= len(m.todos) {
+			m.cursor = len(m.todos) - 1
+		}
+		if m.cursor < 0 {
+			m.cursor = 0
+		}
+		return m, nil
+	case changedMsg:
+		return m, m.load
+	case errMsg:
+		m.err = msg.err
+		return m, nil
+	case tea.KeyMsg:
+		if m.adding {
+			return m.updateInput(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m tuiModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.todos)-1 {
+			m.cursor++
+		}
+	case " ", "enter", "x":
+		if len(m.todos) == 0 {
+			break
+		}
+		t := m.todos[m.cursor]
+		t.Completed = !t.Completed
+		return m, m.call(func(ctx context.Context) error {
+			_, err := m.api.update(ctx, t)
+			return err
+		})
+	case "a":
+		m.adding = true
+		m.input.Reset()
+		return m, m.input.Focus()
+	case "r":
+		return m, m.load
+	}
+	return m, nil
+}
+
+func (m tuiModel) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.adding = false
+		m.input.Blur()
+		return m, nil
+	case "enter":
+		m.adding = false
+		m.input.Blur()
+		title := strings.TrimSpace(m.input.Value())
+		if title == "" {
+			return m, nil
+		}
+		return m, m.call(func(ctx context.Context) error {
+			_, err := m.api.add(ctx, title, "")
+			return err
+		})
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m tuiModel) View() string {
+	var b strings.Builder
+	b.WriteString("Todos\n\n")
+	if len(m.todos) == 0 {
+		b.WriteString("  Nothing to do.\n")
+	}
+	for i, t := range m.todos {
+		pointer, box := " ", "[ ]"
+		if i == m.cursor {
+			pointer = ">"
+		}
+		if t.Completed {
+			box = "[x]"
+		}
+		fmt.Fprintf(&b, "%s %s %s\n", pointer, box, t.Title)
+	}
+	b.WriteString("\n")
+	if m.adding {
+		b.WriteString(m.input.View() + "\n\nenter save • esc cancel\n")
+	} else {
+		b.WriteString("j/k move • space toggle • a add • r reload • q quit\n")
+	}
+	if m.err != nil {
+		fmt.Fprintf(&b, "\nerror: %v\n", m.err)
+	}
+	return b.String()
+}
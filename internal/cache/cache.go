@@ -0,0 +1,156 @@
+// Package cache keeps recent GET responses in memory so clients polling an
+// unchanged list don't cost a database query each time. Entries are dropped
+// whenever a todo changes, and in any case after a TTL, which bounds how
+// stale a response can get from writes that bypass the event bus.
+package cache
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+)
+
+type entry struct {
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// maxEntries bounds the cache; responses beyond it aren't stored until
+// expired ones make room.
+const maxEntries = 10000
+
+type Cache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	now       func() time.Time
+	entries   map[string]entry
+	lastSweep time.Time
+	// gen counts purges; a response computed across one isn't stored.
+	gen uint64
+}
+
+func New(ttl time.Duration, now func() time.Time) *Cache {
+	return &Cache{ttl: ttl, now: now, entries: map[string]entry{}, lastSweep: now()}
+}
+
+// Purge drops every entry.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	c.entries = map[string]entry{}
+	c.gen++
+	c.mu.Unlock()
+}
+
+// Subscribe purges the cache on every todo event, which covers changes made
+// outside the routes the cache wraps (HTML pages, bots, integrations).
+func (c *Cache) Subscribe(bus *events.Bus) {
+	bus.Subscribe("cache", func(events.Event) { c.Purge() })
+}
+
+func (c *Cache) get(key string) (entry, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if ok && !c.now().Before(e.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	return e, c.gen, ok
+}
+
+func (c *Cache) put(key string, gen uint64, e entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	c.sweep(now)
+	if gen == c.gen && len(c.entries) < maxEntries {
+		e.expires = now.Add(c.ttl)
+		c.entries[key] = e
+	}
+}
+
+// sweep drops expired entries, at most once per TTL, so URLs never asked
+// for again don't stay in memory. c.mu must be held.
+func (c *Cache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+	for key, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Handler serves repeated GETs from the cache and caches their successful
+// responses by tenant, URL, Accept header, locale and time zone. HEAD
+// requests pass straight through; any other method purges the cache once
+// it's handled, so a client sees its own writes straight away rather than
+// when the event arrives.
+func (c *Cache) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			defer c.Purge()
+			next.ServeHTTP(w, r)
+			return
+		}
+		// Accept picks the envelope of the response, the locale the language
+		// of its messages, and the tenant's zone, which its settings may
+		// change at any time, how dates are shown.
+		key := tenant.FromContext(r.Context()) + " " + r.URL.RequestURI() + " " + r.Header.Get("Accept") +
+			" " + i18n.FromContext(r.Context()) + " " + tz.FromContext(r.Context()).String()
+		e, gen, ok := c.get(key)
+		if ok {
+			for k, v := range e.header {
+				w.Header()[k] = v
+			}
+			w.Header().Set("X-Cache", "HIT")
+			w.WriteHeader(http.StatusOK)
+			w.Write(e.body)
+			return
+		}
+		w.Header().Set("X-Cache", "MISS")
+		rec := &recorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == http.StatusOK {
+			c.put(key, gen, entry{header: rec.header, body: rec.body.Bytes()})
+		}
+	})
+}
+
+// recorder passes the response through while keeping a copy of it.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+		r.header = r.Header().Clone()
+		r.header.Del("X-Cache")
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
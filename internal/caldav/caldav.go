@@ -0,0 +1,250 @@
+// Package caldav maps lists to CalDAV calendars and todos to the VTODOs in
+// them, so native clients (Apple Reminders, Thunderbird, Tasks.org) can
+// sync with the server directly. The WebDAV side is in the handler.
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/ical"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Integration is the key under which Todo.External keeps the resource name
+// a client chose for a todo it created. Other todos are named by their id.
+const Integration = "caldav"
+
+// Store is the persistence the calendars need.
+type Store interface {
+	SetExternalID(ctx context.Context, id bson.ObjectID, integration, externalID string) error
+	GetTodoByExternalID(ctx context.Context, integration, externalID string) (*model.Todo, error)
+}
+
+// Calendars reads times without a zone in the zone of the tenant a request
+// acts for; see tz.
+type Calendars struct {
+	store Store
+	todos *service.TodoService
+}
+
+func New(s Store, todos *service.TodoService) *Calendars {
+	return &Calendars{store: s, todos: todos}
+}
+
+// Name is the resource name of t, without ".ics".
+func Name(t *model.Todo) string {
+	if n := t.External[Integration]; n != "" {
+		return n
+	}
+	return t.ID.Hex()
+}
+
+// ETag changes whenever t does.
+func ETag(t *model.Todo) string {
+	return fmt.Sprintf(`"%x"`, t.UpdatedAt.UnixNano())
+}
+
+// Lists returns the names of the lists holding todos, sorted, always
+// starting with "" for the default list.
+func (c *Calendars) Lists(ctx context.Context) ([]string, error) {
+	seen := map[string]bool{"": true}
+	err := c.todos.Each(ctx, store.TodoFilter{}, 0, 0, func(t *model.Todo) error {
+		seen[t.List] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(seen))
+	for l := range seen {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// Each calls fn with each todo on list.
+func (c *Calendars) Each(ctx context.Context, list string, fn func(*model.Todo) error) error {
+	return c.todos.Each(ctx, store.TodoFilter{List: list}, 0, 0, func(t *model.Todo) error {
+		if t.List != list {
+			return nil // the default list can't be filtered on
+		}
+		return fn(t)
+	})
+}
+
+// Get returns the todo named name, wherever it is.
+func (c *Calendars) Get(ctx context.Context, name string) (*model.Todo, error) {
+	if _, err := store.ParseID(name); err == nil {
+		t, err := c.todos.Get(ctx, name)
+		if !errors.Is(err, store.ErrNotFound) {
+			return t, err
+		}
+	}
+	return c.store.GetTodoByExternalID(ctx, Integration, name)
+}
+
+// Put creates or replaces the todo named name from the VTODO in r and puts
+// it on list. It keeps what a VTODO can't carry, such as custom fields.
+func (c *Calendars) Put(ctx context.Context, list, name string, r io.Reader) (t *model.Todo, created bool, err error) {
+	v, err := c.read(ctx, r)
+	if err != nil {
+		return nil, false, err
+	}
+	existing, err := c.Get(ctx, name)
+	if errors.Is(err, store.ErrNotFound) {
+		in := v.input(model.StatusTodo)
+		in.List = list
+		if t, err = c.todos.Create(ctx, in); err != nil {
+			return nil, false, err
+		}
+		if name != t.ID.Hex() {
+			if t.External == nil {
+				t.External = map[string]string{}
+			}
+			t.External[Integration] = name
+			err = c.store.SetExternalID(ctx, t.ID, Integration, name)
+		}
+		return t, true, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	in := v.input(existing.CurrentStatus())
+	in.List = list
+	in.Notes, in.EstimateMinutes, in.Fields, in.Geofence = existing.Notes, existing.EstimateMinutes, existing.Fields, existing.Geofence
+	if t, err = c.todos.Update(ctx, existing.ID.Hex(), in); err != nil {
+		return nil, false, err
+	}
+	t.External = existing.External
+	return t, false, nil
+}
+
+// Delete deletes the todo named name.
+func (c *Calendars) Delete(ctx context.Context, name string) error {
+	t, err := c.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	return c.todos.Delete(ctx, t.ID.Hex())
+}
+
+// vtodo is what the calendars read from a VTODO.
+type vtodo struct {
+	summary   string
+	due       *time.Time
+	status    string
+	completed bool
+	tags      []string
+}
+
+// read reads the first VTODO in r, with times without a zone in the zone of
+// ctx.
+func (c *Calendars) read(ctx context.Context, r io.Reader) (*vtodo, error) {
+	loc := tz.FromContext(ctx)
+	var v *vtodo
+	var in, done bool
+	err := ical.Scan(r, func(p ical.Prop) {
+		switch {
+		case done:
+		case p.Name == "BEGIN" && p.Value == "VTODO":
+			v, in = &vtodo{}, true
+		case !in:
+		case p.Name == "END" && p.Value == "VTODO":
+			done = true
+		case p.Name == "SUMMARY":
+			v.summary = p.Text()
+		case p.Name == "DUE":
+			v.due = p.Due(loc)
+		case p.Name == "STATUS":
+			v.status = strings.ToUpper(p.Value)
+		case p.Name == "COMPLETED":
+			v.completed = true
+		case p.Name == "CATEGORIES":
+			for _, cat := range p.List() {
+				if tag := service.TagSlug(cat); tag != "" {
+					v.tags = append(v.tags, tag)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !done {
+		return nil, &service.ValidationError{Field: "body", Message: "The body holds no VTODO"}
+	}
+	return v, nil
+}
+
+// input is the todo v describes. An open VTODO keeps status open, so a
+// backlog todo stays in the backlog.
+func (v *vtodo) input(open string) service.TodoInput {
+	in := service.TodoInput{Title: v.summary, DueAt: v.due, Tags: v.tags}
+	switch {
+	case v.status == "COMPLETED" || v.status == "" && v.completed:
+		in.Status = model.StatusDone
+	case v.status == "CANCELLED":
+		in.Status = model.StatusCancelled
+	case v.status == "IN-PROCESS":
+		in.Status = model.StatusInProgress
+	case open == model.StatusDone || open == model.StatusCancelled:
+		in.Status = model.StatusTodo
+	default:
+		in.Status = open
+	}
+	return in
+}
+
+// statuses are the VTODO statuses of ours.
+var statuses = map[string]string{
+	model.StatusBacklog:    "NEEDS-ACTION",
+	model.StatusTodo:       "NEEDS-ACTION",
+	model.StatusInProgress: "IN-PROCESS",
+	model.StatusDone:       "COMPLETED",
+	model.StatusCancelled:  "CANCELLED",
+}
+
+// Encode writes t as an iCalendar object holding one VTODO.
+func Encode(w io.Writer, t *model.Todo) error {
+	iw := ical.NewWriter(w)
+	iw.Line("BEGIN", "VCALENDAR")
+	iw.Line("VERSION", "2.0")
+	iw.Line("PRODID", "-//personal-todo-golang//CalDAV//EN")
+	iw.Line("BEGIN", "VTODO")
+	iw.Line("UID", ical.Escape(Name(t)))
+	iw.Line("DTSTAMP", ical.UTC(t.UpdatedAt))
+	iw.Line("CREATED", ical.UTC(t.CreatedAt))
+	iw.Line("LAST-MODIFIED", ical.UTC(t.UpdatedAt))
+	iw.Line("SUMMARY", ical.Escape(t.Title))
+	if t.DueAt != nil {
+		iw.Line("DUE", ical.UTC(*t.DueAt))
+	}
+	iw.Line("STATUS", statuses[t.CurrentStatus()])
+	if t.Completed {
+		iw.Line("PERCENT-COMPLETE", "100")
+		if t.CompletedAt != nil {
+			iw.Line("COMPLETED", ical.UTC(*t.CompletedAt))
+		}
+	}
+	if len(t.Tags) > 0 {
+		tags := make([]string, len(t.Tags))
+		for i, tag := range t.Tags {
+			tags[i] = ical.Escape(tag)
+		}
+		iw.Line("CATEGORIES", strings.Join(tags, ","))
+	}
+	iw.Line("END", "VTODO")
+	iw.Line("END", "VCALENDAR")
+	return iw.Err()
+}
@@ -0,0 +1,134 @@
+// Package events is the in-process bus domain events travel on. Features
+// that react to changes (notifications, webhooks, live updates, audit
+// logging) subscribe here instead of hooking into the handlers.
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+)
+
+// Event types.
+const (
+	TodoCreated   = "todo.created"
+	TodoUpdated   = "todo.updated"
+	TodoCompleted = "todo.completed"
+	TodoDeleted   = "todo.deleted"
+	// PomodoroBreak is published when a pomodoro on the todo is completed
+	// and its break begins.
+	PomodoroBreak = "pomodoro.break"
+	// DailyDigest is published each morning for every tenant with an
+	// integration asking for it by name; it is about no single todo.
+	DailyDigest = "digest.daily"
+	// StreakMilestone is published when a completion earns a badge.
+	StreakMilestone = "streak.milestone"
+)
+
+// Types lists every event type.
+var Types = []string{TodoCreated, TodoUpdated, TodoCompleted, TodoDeleted, PomodoroBreak, DailyDigest, StreakMilestone}
+
+type Event struct {
+	Type   string `json:"type"`
+	TodoID string `json:"todo_id"`
+	// Tenant is the tenant the todo belongs to; empty for the default one.
+	Tenant string    `json:"tenant,omitempty"`
+	At     time.Time `json:"at"`
+	// Todo is the todo after the change; nil for deletions.
+	Todo *model.Todo `json:"todo,omitempty"`
+	// Digest is set on DailyDigest events only.
+	Digest *Digest `json:"digest,omitempty"`
+	// Badge names the badge a StreakMilestone event celebrates, such as
+	// "7-day streak".
+	Badge string `json:"badge,omitempty"`
+}
+
+// Digest is the open todos needing attention today, and the todos shared
+// since the previous digest.
+type Digest struct {
+	Overdue  []model.Todo `json:"overdue"`
+	DueToday []model.Todo `json:"due_today"`
+	Shared   []model.Todo `json:"shared"`
+}
+
+// Bus delivers every published event to every subscriber. Each subscriber
+// has its own goroutine and buffer, so a slow one neither blocks the request
+// that published the event nor holds up the others; if its buffer fills up,
+// further events for it are dropped and logged.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   []*subscription
+	closed bool
+	log    *log.Logger
+	wg     sync.WaitGroup
+}
+
+type subscription struct {
+	name string
+	ch   chan Event
+}
+
+const bufferSize = 256
+
+func NewBus(logger *log.Logger) *Bus {
+	return &Bus{log: logger}
+}
+
+// Subscribe calls fn for every event published from now on. name identifies
+// the subscriber in logs.
+func (b *Bus) Subscribe(name string, fn func(Event)) {
+	s := &subscription{name: name, ch: make(chan Event, bufferSize)}
+	b.mu.Lock()
+	b.subs = append(b.subs, s)
+	b.mu.Unlock()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for e := range s.ch {
+			b.deliver(s.name, fn, e)
+		}
+	}()
+}
+
+func (b *Bus) deliver(name string, fn func(Event), e Event) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			b.log.Printf("events: subscriber %s panicked on %s: %v", name, e.Type, rvr)
+		}
+	}()
+	fn(e)
+}
+
+func (b *Bus) Publish(e Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+	for _, s := range b.subs {
+		select {
+		case s.ch <- e:
+		default:
+			b.log.Printf("events: subscriber %s is falling behind, dropped %s for %s", s.name, e.Type, e.TodoID)
+		}
+	}
+}
+
+// Close stops accepting events and waits for subscribers to drain what they
+// already have.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for _, s := range b.subs {
+		close(s.ch)
+	}
+	b.mu.Unlock()
+	b.wg.Wait()
+}
@@ -0,0 +1,78 @@
+// Package flags switches optional or experimental parts of the API on and off
+// per deployment.
+package flags
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Flags gating the experimental parts of the API. They are on unless the
+// config turns them off, so a deployment keeps them by default and an
+// operator can switch one off at runtime if it misbehaves.
+const (
+	CalDAV = "caldav"
+	Voice  = "voice"
+	Import = "import"
+	Sync   = "sync"
+)
+
+var defaults = map[string]bool{CalDAV: true, Voice: true, Import: true, Sync: true}
+
+// Flags starts from the features section of the config; the admin API can
+// flip flags at runtime, which lasts until the next restart.
+type Flags struct {
+	mu    sync.RWMutex
+	flags map[string]bool
+}
+
+// New returns flags set as in initial, the features section of the config,
+// with the experimental flags on unless initial says otherwise.
+func New(initial map[string]bool) *Flags {
+	flags := make(map[string]bool, len(defaults)+len(initial))
+	for name, on := range defaults {
+		flags[name] = on
+	}
+	for name, on := range initial {
+		flags[name] = on
+	}
+	return &Flags{flags: flags}
+}
+
+// Enabled reports whether the named flag is on. Unknown flags are off.
+func (f *Flags) Enabled(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.flags[name]
+}
+
+// Set turns the named flag on or off until the next restart.
+func (f *Flags) Set(name string, on bool) {
+	f.mu.Lock()
+	f.flags[name] = on
+	f.mu.Unlock()
+}
+
+// All returns a copy of every known flag.
+func (f *Flags) All() map[string]bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	out := make(map[string]bool, len(f.flags))
+	for name, on := range f.flags {
+		out[name] = on
+	}
+	return out
+}
+
+// Require hides the routes it wraps behind a 404 while the flag is off.
+func (f *Flags) Require(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !f.Enabled(name) {
+				http.NotFound(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Package fuzzy matches text despite typos by comparing trigrams, the
+// three-letter runs of each word, in the manner of PostgreSQL's pg_trgm.
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Threshold is the score a title must reach to match a query.
+const Threshold = 0.3
+
+// words splits s into lowercase words of letters and digits.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// wordGrams returns the trigrams of one word, padded so that its start and
+// end count too: "cat" gives "  c", " ca", "cat" and "at ".
+func wordGrams(w string) map[string]bool {
+	r := []rune("  " + w + " ")
+	grams := make(map[string]bool, len(r)-2)
+	for i := 0; i+3 <= len(r); i++ {
+		grams[string(r[i:i+3])] = true
+	}
+	return grams
+}
+
+// Trigrams returns the distinct trigrams of every word in s, sorted. They
+// are what gets indexed to find candidate matches.
+func Trigrams(s string) []string {
+	set := map[string]bool{}
+	for _, w := range words(s) {
+		for g := range wordGrams(w) {
+			set[g] = true
+		}
+	}
+	out := make([]string, 0, len(set))
+	for g := range set {
+		out = append(out, g)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// similarity is the Dice coefficient of two trigram sets.
+func similarity(a, b map[string]bool) float64 {
+	shared := 0
+	for g := range a {
+		if b[g] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+// Score rates how well text matches query, from 0 to 1: each query word is
+// paired with its most similar word in text, and the pairs are averaged.
+// "grocrey" against "Buy groceries" scores about 0.44.
+func Score(query, text string) float64 {
+	qw, tw := words(query), words(text)
+	if len(qw) == 0 || len(tw) == 0 {
+		return 0
+	}
+	tgrams := make([]map[string]bool, len(tw))
+	for i, w := range tw {
+		tgrams[i] = wordGrams(w)
+	}
+	total := 0.0
+	for _, w := range qw {
+		g := wordGrams(w)
+		best := 0.0
+		for _, t := range tgrams {
+			if s := similarity(g, t); s > best {
+				best = s
+			}
+		}
+		total += best
+	}
+	return total / float64(len(qw))
+}
@@ -0,0 +1,180 @@
+// Package gcal syncs todos that have a due date with a Google Calendar.
+// Changes to todos create, update or delete calendar events; deleting an
+// event in the calendar completes its todo.
+package gcal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/endpoints"
+)
+
+const (
+	apiBase = "https://www.googleapis.com/calendar/v3"
+	// integration is the name the account is saved under.
+	integration = "google"
+	// eventLength is how long the calendar entry for a due todo lasts.
+	eventLength = 30 * time.Minute
+)
+
+// ErrNotConnected means no Google account has been connected yet.
+var ErrNotConnected = errors.New("gcal: no Google account connected")
+
+// Store is the persistence the sync needs.
+type Store interface {
+	GetTodo(ctx context.Context, id bson.ObjectID) (*model.Todo, error)
+	GetTodoByGoogleEvent(ctx context.Context, eventID string) (*model.Todo, error)
+	SetGoogleEventID(ctx context.Context, id bson.ObjectID, eventID string) error
+	GetIntegrationState(ctx context.Context, name string, v interface{}) error
+	SaveIntegrationState(ctx context.Context, name string, v interface{}) error
+	DeleteIntegrationState(ctx context.Context, name string) error
+}
+
+type Sync struct {
+	oauth    *oauth2.Config
+	calendar string
+	store    Store
+	todos    *service.TodoService
+	log      *log.Logger
+	now      func() time.Time
+}
+
+func New(c config.Google, s Store, todos *service.TodoService, logger *log.Logger, now func() time.Time) *Sync {
+	return &Sync{
+		oauth: &oauth2.Config{
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			RedirectURL:  c.RedirectURL,
+			Endpoint:     endpoints.Google,
+			Scopes:       []string{"https://www.googleapis.com/auth/calendar.events"},
+		},
+		calendar: c.CalendarID,
+		store:    s,
+		todos:    todos,
+		log:      logger,
+		now:      now,
+	}
+}
+
+// AuthURL is where the owner grants calendar access; Google redirects back
+// to the configured redirect URL with state and a code for Connect.
+func (s *Sync) AuthURL(state string) string {
+	return s.oauth.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.ApprovalForce)
+}
+
+// Connect exchanges the code from the OAuth callback and saves the account.
+func (s *Sync) Connect(ctx context.Context, code string) error {
+	tok, err := s.oauth.Exchange(ctx, code)
+	if err != nil {
+		return err
+	}
+	if tok.RefreshToken == "" {
+		return errors.New("gcal: Google returned no refresh token")
+	}
+	return s.store.SaveIntegrationState(ctx, integration, &model.GoogleAccount{
+		RefreshToken: tok.RefreshToken,
+		AccessToken:  tok.AccessToken,
+		Expiry:       tok.Expiry,
+		ConnectedAt:  s.now(),
+	})
+}
+
+func (s *Sync) Disconnect(ctx context.Context) error {
+	return s.store.DeleteIntegrationState(ctx, integration)
+}
+
+func (s *Sync) account(ctx context.Context) (*model.GoogleAccount, error) {
+	var acc model.GoogleAccount
+	err := s.store.GetIntegrationState(ctx, integration, &acc)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, ErrNotConnected
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &acc, nil
+}
+
+// Connected reports whether an account is connected.
+func (s *Sync) Connected(ctx context.Context) (bool, error) {
+	_, err := s.account(ctx)
+	if errors.Is(err, ErrNotConnected) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *Sync) client(ctx context.Context, acc *model.GoogleAccount) *http.Client {
+	return s.oauth.Client(ctx, &oauth2.Token{
+		RefreshToken: acc.RefreshToken,
+		AccessToken:  acc.AccessToken,
+		Expiry:       acc.Expiry,
+	})
+}
+
+// apiError is an error answer from the Calendar API.
+type apiError struct {
+	Status int
+	Body   string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("gcal: %d: %s", e.Status, e.Body)
+}
+
+func statusIs(err error, status int) bool {
+	var ae *apiError
+	return errors.As(err, &ae) && ae.Status == status
+}
+
+// call sends body (if any) as JSON to the Calendar API and decodes the
+// answer into out (if any).
+func call(ctx context.Context, c *http.Client, method, path string, body, out interface{}) error {
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, apiBase+path, rd)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &apiError{Status: resp.StatusCode, Body: string(bytes.TrimSpace(b))}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (s *Sync) eventsPath() string {
+	return "/calendars/" + url.PathEscape(s.calendar) + "/events"
+}
@@ -0,0 +1,219 @@
+package gcal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/jobs"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+)
+
+const pushJob = "gcal.push"
+
+// todoIDProperty is the private extended property events carry their todo's
+// ID in.
+const todoIDProperty = "todo_id"
+
+type event struct {
+	ID                 string              `json:"id,omitempty"`
+	Status             string              `json:"status,omitempty"`
+	Summary            string              `json:"summary,omitempty"`
+	Start              *eventTime          `json:"start,omitempty"`
+	End                *eventTime          `json:"end,omitempty"`
+	ExtendedProperties *extendedProperties `json:"extendedProperties,omitempty"`
+}
+
+type eventTime struct {
+	DateTime time.Time `json:"dateTime"`
+}
+
+type extendedProperties struct {
+	Private map[string]string `json:"private"`
+}
+
+type eventList struct {
+	Items         []event `json:"items"`
+	NextPageToken string  `json:"nextPageToken"`
+	NextSyncToken string  `json:"nextSyncToken"`
+}
+
+// Subscribe queues a push job for every todo event on bus, so calendar
+// outages are retried by the job queue.
+func (s *Sync) Subscribe(bus *events.Bus, queue *jobs.Queue) {
+	queue.Register(pushJob, func(ctx context.Context, payload json.RawMessage) error {
+		var e events.Event
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return err
+		}
+		return s.Push(ctx, e)
+	})
+	bus.Subscribe("gcal", func(e events.Event) {
+		// Only changes to todos touch the calendar.
+		if e.Tenant != "" || !strings.HasPrefix(e.Type, "todo.") {
+			return
+		}
+		if _, err := queue.Enqueue(pushJob, e); err != nil {
+			s.log.Printf("gcal: queueing %s: %v", e.Type, err)
+		}
+	})
+}
+
+// Push brings the calendar in line with the todo the event is about. Todos
+// without a due date, and completed todos that never had an event, get
+// none.
+func (s *Sync) Push(ctx context.Context, e events.Event) error {
+	acc, err := s.account(ctx)
+	if errors.Is(err, ErrNotConnected) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c := s.client(ctx, acc)
+
+	if e.Type == events.TodoDeleted {
+		return s.deleteEventsFor(ctx, c, e.TodoID)
+	}
+	id, err := store.ParseID(e.TodoID)
+	if err != nil {
+		return err
+	}
+	t, err := s.store.GetTodo(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil // deleted since; the deletion event cleans up
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case t.DueAt == nil && t.GoogleEventID != "":
+		if err := s.deleteEvent(ctx, c, t.GoogleEventID); err != nil {
+			return err
+		}
+		return s.store.SetGoogleEventID(ctx, t.ID, "")
+	case t.DueAt == nil, t.GoogleEventID == "" && t.Completed:
+		return nil
+	case t.GoogleEventID != "":
+		err := call(ctx, c, http.MethodPatch, s.eventsPath()+"/"+url.PathEscape(t.GoogleEventID), toEvent(t), nil)
+		if !statusIs(err, http.StatusNotFound) && !statusIs(err, http.StatusGone) {
+			return err
+		}
+		// The event is gone from the calendar; make a new one.
+	}
+	var created event
+	if err := call(ctx, c, http.MethodPost, s.eventsPath(), toEvent(t), &created); err != nil {
+		return err
+	}
+	return s.store.SetGoogleEventID(ctx, t.ID, created.ID)
+}
+
+func toEvent(t *model.Todo) event {
+	summary := t.Title
+	if t.Completed {
+		summary = "✔ " + summary
+	}
+	return event{
+		Summary: summary,
+		Start:   &eventTime{DateTime: *t.DueAt},
+		End:     &eventTime{DateTime: t.DueAt.Add(eventLength)},
+		ExtendedProperties: &extendedProperties{
+			Private: map[string]string{todoIDProperty: t.ID.Hex()},
+		},
+	}
+}
+
+func (s *Sync) deleteEvent(ctx context.Context, c *http.Client, eventID string) error {
+	err := call(ctx, c, http.MethodDelete, s.eventsPath()+"/"+url.PathEscape(eventID), nil, nil)
+	if statusIs(err, http.StatusNotFound) || statusIs(err, http.StatusGone) {
+		return nil
+	}
+	return err
+}
+
+// deleteEventsFor finds a deleted todo's events by their todo_id property,
+// since the todo and its event ID are gone.
+func (s *Sync) deleteEventsFor(ctx context.Context, c *http.Client, todoID string) error {
+	q := url.Values{"privateExtendedProperty": {todoIDProperty + "=" + todoID}}
+	var list eventList
+	if err := call(ctx, c, http.MethodGet, s.eventsPath()+"?"+q.Encode(), nil, &list); err != nil {
+		return err
+	}
+	for _, ev := range list.Items {
+		if err := s.deleteEvent(ctx, c, ev.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pull reads the calendar changes since the last run and completes the
+// todos whose events were deleted. The first run only records where to
+// start from.
+func (s *Sync) Pull(ctx context.Context) error {
+	acc, err := s.account(ctx)
+	if errors.Is(err, ErrNotConnected) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c := s.client(ctx, acc)
+
+	initial := acc.SyncToken == ""
+	q := url.Values{"showDeleted": {"true"}}
+	if !initial {
+		q.Set("syncToken", acc.SyncToken)
+	}
+	for {
+		var list eventList
+		err := call(ctx, c, http.MethodGet, s.eventsPath()+"?"+q.Encode(), nil, &list)
+		if statusIs(err, http.StatusGone) {
+			// The sync token expired; start over with a full listing.
+			acc.SyncToken = ""
+			return s.store.SaveIntegrationState(ctx, integration, acc)
+		}
+		if err != nil {
+			return err
+		}
+		if !initial {
+			for _, ev := range list.Items {
+				if ev.Status == "cancelled" {
+					if err := s.eventDeleted(ctx, ev.ID); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if list.NextPageToken == "" {
+			acc.SyncToken = list.NextSyncToken
+			return s.store.SaveIntegrationState(ctx, integration, acc)
+		}
+		q.Set("pageToken", list.NextPageToken)
+	}
+}
+
+func (s *Sync) eventDeleted(ctx context.Context, eventID string) error {
+	t, err := s.store.GetTodoByGoogleEvent(ctx, eventID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.store.SetGoogleEventID(ctx, t.ID, ""); err != nil {
+		return err
+	}
+	if t.Completed {
+		return nil
+	}
+	_, err = s.todos.SyncCompleted(ctx, t.ID.Hex(), true)
+	return err
+}
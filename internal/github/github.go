@@ -0,0 +1,158 @@
+// Package github links lists to GitHub repositories: opened issues become
+// todos on the list, and closing either side closes the other.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/jobs"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	apiBase = "https://api.github.com"
+	// Integration is the key issue references are stored under in
+	// Todo.External, as "owner/repo#number".
+	Integration = "github"
+	closeJob    = "github.close"
+)
+
+// Store is the persistence the sync needs.
+type Store interface {
+	SetExternalID(ctx context.Context, id bson.ObjectID, integration, externalID string) error
+	GetTodoByExternalID(ctx context.Context, integration, externalID string) (*model.Todo, error)
+}
+
+type Sync struct {
+	token string
+	// lists maps "owner/repo" to the list its issues go on.
+	lists  map[string]string
+	store  Store
+	todos  *service.TodoService
+	client *http.Client
+	log    *log.Logger
+}
+
+func New(c config.GitHub, s Store, todos *service.TodoService, logger *log.Logger) *Sync {
+	lists := map[string]string{}
+	for list, repo := range c.Lists {
+		lists[strings.ToLower(repo)] = list
+	}
+	return &Sync{
+		token:  c.Token,
+		lists:  lists,
+		store:  s,
+		todos:  todos,
+		client: &http.Client{Timeout: 10 * time.Second},
+		log:    logger,
+	}
+}
+
+// Issue is the part of a webhook's issue payload the sync uses.
+type Issue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	// PullRequest is set when the "issue" is a pull request.
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+func ref(repo string, number int) string {
+	return repo + "#" + strconv.Itoa(number)
+}
+
+// IssueEvent applies an "issues" webhook for repo. Actions other than
+// opened, closed and reopened, and repos not linked to a list, are ignored.
+func (s *Sync) IssueEvent(ctx context.Context, action, repo string, issue Issue) error {
+	list, ok := s.lists[strings.ToLower(repo)]
+	if !ok || issue.PullRequest != nil {
+		return nil
+	}
+	key := ref(repo, issue.Number)
+	existing, err := s.store.GetTodoByExternalID(ctx, Integration, key)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+
+	switch action {
+	case "opened":
+		if existing != nil {
+			return nil // redelivery
+		}
+		t, err := s.todos.Create(ctx, service.TodoInput{Title: issue.Title, List: list})
+		if err != nil {
+			return err
+		}
+		return s.store.SetExternalID(ctx, t.ID, Integration, key)
+	case "closed", "reopened":
+		done := action == "closed"
+		if existing == nil || existing.Completed == done {
+			return nil
+		}
+		_, err := s.todos.SyncCompleted(ctx, existing.ID.Hex(), done)
+		return err
+	}
+	return nil
+}
+
+// Subscribe closes the linked issue when a todo is completed. The API call
+// runs as a job so it is retried if GitHub is unavailable.
+func (s *Sync) Subscribe(bus *events.Bus, queue *jobs.Queue) {
+	queue.Register(closeJob, func(ctx context.Context, payload json.RawMessage) error {
+		var key string
+		if err := json.Unmarshal(payload, &key); err != nil {
+			return err
+		}
+		return s.closeIssue(ctx, key)
+	})
+	bus.Subscribe("github", func(e events.Event) {
+		if e.Tenant != "" || e.Type != events.TodoCompleted || e.Todo == nil || e.Todo.External[Integration] == "" {
+			return
+		}
+		if _, err := queue.Enqueue(closeJob, e.Todo.External[Integration]); err != nil {
+			s.log.Printf("github: queueing close of %s: %v", e.Todo.External[Integration], err)
+		}
+	})
+}
+
+func (s *Sync) closeIssue(ctx context.Context, key string) error {
+	repo, number, ok := strings.Cut(key, "#")
+	if !ok {
+		return fmt.Errorf("github: bad issue reference %q", key)
+	}
+	body, err := json.Marshal(map[string]string{"state": "closed"})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch,
+		apiBase+"/repos/"+repo+"/issues/"+number, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("github: closing %s: %s: %s", key, resp.Status, bytes.TrimSpace(b))
+	}
+	return nil
+}
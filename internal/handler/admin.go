@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/flags"
+	"dhruvarora9/personal-todo-golang/internal/jobs"
+	"dhruvarora9/personal-todo-golang/internal/middleware"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/scheduler"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"github.com/go-chi/chi"
+)
+
+// Admin serves the operator endpoints mounted at /admin.
+type Admin struct {
+	rnd   *render.Renderer
+	token string
+	maint *middleware.Maintenance
+	flags *flags.Flags
+	jobs  *jobs.Queue
+	sched *scheduler.Scheduler
+	pool  PoolSource
+	deps  *middleware.Deprecations
+}
+
+// PoolSource reports database connection pool figures; *store.Store is one.
+type PoolSource interface {
+	PoolStats() store.PoolStats
+}
+
+// NewAdmin returns the admin API. pool may be nil when the store has no
+// connection pool to show.
+func NewAdmin(rnd *render.Renderer, token string, maint *middleware.Maintenance, f *flags.Flags, q *jobs.Queue, sched *scheduler.Scheduler, pool PoolSource, deps *middleware.Deprecations) *Admin {
+	return &Admin{rnd: rnd, token: token, maint: maint, flags: f, jobs: q, sched: sched, pool: pool, deps: deps}
+}
+
+func (a *Admin) Routes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Use(middleware.RequireAdmin(a.token, a.rnd))
+	rg.Group(func(r chi.Router) {
+		r.Get("/maintenance", a.getMaintenance)
+		r.Put("/maintenance", a.putMaintenance)
+		r.Get("/flags", a.listFlags)
+		r.Put("/flags/{name}", a.putFlag)
+		r.Get("/jobs", a.listJobs)
+		r.Get("/jobs/{id}", a.getJob)
+		r.Post("/jobs/{id}/retry", a.retryJob)
+		r.Get("/scheduler", a.schedulerStats)
+		r.Get("/deprecations", a.deprecationStats)
+		if a.pool != nil {
+			r.Get("/database", a.databaseStats)
+		}
+	})
+	return rg
+}
+
+func (a *Admin) maintenanceState() render.M {
+	enabled, message, retryAfter := a.maint.State()
+	return render.M{
+		"enabled":     enabled,
+		"message":     message,
+		"retry_after": int(retryAfter.Seconds()),
+	}
+}
+
+func (a *Admin) getMaintenance(w http.ResponseWriter, r *http.Request) {
+	a.rnd.Data(w, r, http.StatusOK, a.maintenanceState())
+}
+
+func (a *Admin) putMaintenance(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Enabled    bool   `json:"enabled"`
+		Message    string `json:"message"`
+		RetryAfter int    `json:"retry_after"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		a.rnd.Problem(w, r, http.StatusBadRequest, "The request body is not valid JSON: %v", err)
+		return
+	}
+	if body.RetryAfter < 0 {
+		a.rnd.Problem(w, r, http.StatusBadRequest, "retry_after must not be negative")
+		return
+	}
+	a.maint.Set(body.Enabled, body.Message, time.Duration(body.RetryAfter)*time.Second)
+	a.rnd.Data(w, r, http.StatusOK, a.maintenanceState())
+}
+
+func (a *Admin) listFlags(w http.ResponseWriter, r *http.Request) {
+	a.rnd.Data(w, r, http.StatusOK, a.flags.All())
+}
+
+func (a *Admin) putFlag(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSpace(chi.URLParam(r, "name"))
+	var body struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		a.rnd.Problem(w, r, http.StatusBadRequest, "The request body is not valid JSON: %v", err)
+		return
+	}
+	a.flags.Set(name, body.Enabled)
+	a.rnd.Data(w, r, http.StatusOK, render.M{
+		"name":    name,
+		"enabled": body.Enabled,
+	})
+}
+
+// listJobs shows the queued, running and recently finished jobs; ?state=
+// narrows it down, e.g. ?state=failed.
+func (a *Admin) listJobs(w http.ResponseWriter, r *http.Request) {
+	a.rnd.Data(w, r, http.StatusOK, a.jobs.List(r.URL.Query().Get("state")))
+}
+
+func (a *Admin) getJob(w http.ResponseWriter, r *http.Request) {
+	j, err := a.jobs.Get(chi.URLParam(r, "id"))
+	if err != nil {
+		a.rnd.Problem(w, r, http.StatusNotFound, "Job not found")
+		return
+	}
+	a.rnd.Data(w, r, http.StatusOK, j)
+}
+
+func (a *Admin) retryJob(w http.ResponseWriter, r *http.Request) {
+	j, err := a.jobs.Retry(chi.URLParam(r, "id"))
+	if errors.Is(err, jobs.ErrNotFound) {
+		a.rnd.Problem(w, r, http.StatusNotFound, "Job not found")
+		return
+	}
+	if errors.Is(err, jobs.ErrFull) {
+		a.rnd.Problem(w, r, http.StatusServiceUnavailable, "Too much work is queued, please try again later")
+		return
+	}
+	if err != nil {
+		a.rnd.Problem(w, r, http.StatusConflict, err.Error())
+		return
+	}
+	a.rnd.Data(w, r, http.StatusAccepted, j)
+}
+
+// schedulerStats lists the periodic tasks with their run counts, failures
+// and timings.
+func (a *Admin) schedulerStats(w http.ResponseWriter, r *http.Request) {
+	a.rnd.Data(w, r, http.StatusOK, a.sched.Stats())
+}
+
+// databaseStats shows the database connection pool: open and in-use
+// connections, and checkout failures worth watching when tuning its size.
+func (a *Admin) databaseStats(w http.ResponseWriter, r *http.Request) {
+	a.rnd.Data(w, r, http.StatusOK, render.M{"pool": a.pool.PoolStats()})
+}
+
+// deprecationStats shows how many requests each deprecated route or
+// parameter still gets, and when it was last used.
+func (a *Admin) deprecationStats(w http.ResponseWriter, r *http.Request) {
+	a.rnd.Data(w, r, http.StatusOK, a.deps.Stats())
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"github.com/go-chi/chi"
+)
+
+// dependencyNode is a todo in the dependency graph.
+type dependencyNode struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	// Blocked is true while a todo blocking it is not done.
+	Blocked bool `json:"blocked"`
+}
+
+// dependencyEdge says From must be done before To.
+type dependencyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// addBlocker makes the todo {blocker} block the todo {id}. It answers 409
+// if {id} already blocks {blocker}, directly or not.
+func (h *Handler) addBlocker(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	err := h.todos.AddBlocker(r.Context(), id, strings.TrimSpace(chi.URLParam(r, "blocker")))
+	if err != nil {
+		h.fail(w, r, err, "failed to add the blocker")
+		return
+	}
+	h.rnd.NoContent(w)
+}
+
+func (h *Handler) removeBlocker(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	err := h.todos.RemoveBlocker(r.Context(), id, strings.TrimSpace(chi.URLParam(r, "blocker")))
+	if err != nil {
+		h.fail(w, r, err, "failed to remove the blocker")
+		return
+	}
+	h.rnd.NoContent(w)
+}
+
+// dependencies returns the todos that block or are blocked by others, and
+// an edge from each blocker to each todo it blocks.
+func (h *Handler) dependencies(w http.ResponseWriter, r *http.Request) {
+	g, err := h.todos.Dependencies(r.Context())
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch the dependencies")
+		return
+	}
+	done := map[string]bool{}
+	for _, t := range g.Todos {
+		done[t.ID.Hex()] = t.Completed
+	}
+	nodes := make([]dependencyNode, 0, len(g.Todos))
+	edges := []dependencyEdge{}
+	for _, t := range g.Todos {
+		n := dependencyNode{ID: t.ID.Hex(), Title: t.Title, Status: t.CurrentStatus()}
+		for _, b := range g.Edges[t.ID] {
+			if d, ok := done[b.Hex()]; ok && !d {
+				n.Blocked = true
+			}
+			edges = append(edges, dependencyEdge{From: b.Hex(), To: n.ID})
+		}
+		nodes = append(nodes, n)
+	}
+	h.rnd.Data(w, r, http.StatusOK, render.M{"nodes": nodes, "edges": edges})
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+)
+
+// boardColumn is one status column of the board.
+type boardColumn struct {
+	Status string `json:"status"`
+	Todos  []todo `json:"todos"`
+}
+
+// board groups the todos by status, one column per status in workflow
+// order, each oldest first. It takes the filter of GET /todo.
+func (h *Handler) board(w http.ResponseWriter, r *http.Request) {
+	columns := make([]boardColumn, len(model.Statuses))
+	at := map[string]int{}
+	for i, s := range model.Statuses {
+		columns[i] = boardColumn{Status: s, Todos: []todo{}}
+		at[s] = i
+	}
+	err := h.todos.Each(r.Context(), filterQuery(r), 0, 0, func(t *model.Todo) error {
+		c := &columns[at[t.CurrentStatus()]]
+		c.Todos = append(c.Todos, toTodo(*t, zone(r)))
+		return nil
+	})
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch the board")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusOK, columns)
+}
@@ -0,0 +1,471 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"dhruvarora9/personal-todo-golang/internal/caldav"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"github.com/go-chi/chi"
+)
+
+const (
+	// calDAVRoot is where CalDAV is mounted; it is both the principal and
+	// the calendar home.
+	calDAVRoot = "/caldav/"
+	// defaultCalendar is the path segment of the default list.
+	defaultCalendar = "_default"
+	// maxVTODO caps the body of a PUT.
+	maxVTODO = 1 << 20
+)
+
+func init() {
+	chi.RegisterMethod("PROPFIND")
+	chi.RegisterMethod("REPORT")
+}
+
+// CalDAV serves lists as VTODO calendars under calDAVRoot.
+type CalDAV struct {
+	cal *caldav.Calendars
+	rnd *render.Renderer
+}
+
+func NewCalDAV(cal *caldav.Calendars, rnd *render.Renderer) *CalDAV {
+	return &CalDAV{cal: cal, rnd: rnd}
+}
+
+// Routes returns the router, which must be mounted at calDAVRoot.
+func (c *CalDAV) Routes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Options("/*", c.options)
+		r.Options("/", c.options)
+		r.MethodFunc("PROPFIND", "/", c.propfindHome)
+		r.MethodFunc("PROPFIND", "/{list}", c.propfindCalendar)
+		r.MethodFunc("PROPFIND", "/{list}/", c.propfindCalendar)
+		r.MethodFunc("PROPFIND", "/{list}/{name}", c.propfindTodo)
+		r.MethodFunc("REPORT", "/{list}", c.report)
+		r.MethodFunc("REPORT", "/{list}/", c.report)
+		r.Get("/{list}/{name}", c.getTodo)
+		r.Put("/{list}/{name}", c.putTodo)
+		r.Delete("/{list}/{name}", c.deleteTodo)
+	})
+	return rg
+}
+
+// WebDAV multistatus responses. The prefixes are declared on the root.
+type davMultistatus struct {
+	XMLName   xml.Name      `xml:"D:multistatus"`
+	D         string        `xml:"xmlns:D,attr"`
+	C         string        `xml:"xmlns:C,attr"`
+	CS        string        `xml:"xmlns:CS,attr"`
+	Responses []davResponse `xml:"D:response"`
+}
+
+type davResponse struct {
+	Href     string       `xml:"D:href"`
+	Propstat *davPropstat `xml:"D:propstat,omitempty"`
+	Status   string       `xml:"D:status,omitempty"`
+}
+
+type davPropstat struct {
+	Prop   davProp `xml:"D:prop"`
+	Status string  `xml:"D:status"`
+}
+
+type davProp struct {
+	ResourceType *davResourceType `xml:"D:resourcetype,omitempty"`
+	DisplayName  string           `xml:"D:displayname,omitempty"`
+	Principal    *davHref         `xml:"D:current-user-principal,omitempty"`
+	PrincipalURL *davHref         `xml:"D:principal-URL,omitempty"`
+	HomeSet      *davHref         `xml:"C:calendar-home-set,omitempty"`
+	Components   *davComponents   `xml:"C:supported-calendar-component-set,omitempty"`
+	Reports      *davReports      `xml:"D:supported-report-set,omitempty"`
+	CTag         string           `xml:"CS:getctag,omitempty"`
+	ETag         string           `xml:"D:getetag,omitempty"`
+	ContentType  string           `xml:"D:getcontenttype,omitempty"`
+	CalendarData string           `xml:"C:calendar-data,omitempty"`
+}
+
+type davResourceType struct {
+	Collection *struct{} `xml:"D:collection,omitempty"`
+	Calendar   *struct{} `xml:"C:calendar,omitempty"`
+	Principal  *struct{} `xml:"D:principal,omitempty"`
+}
+
+type davHref struct {
+	Href string `xml:"D:href"`
+}
+
+type davComponents struct {
+	Comp struct {
+		Name string `xml:"name,attr"`
+	} `xml:"C:comp"`
+}
+
+type davReports struct {
+	Reports []davReport `xml:"D:supported-report"`
+}
+
+type davReport struct {
+	Query    *struct{} `xml:"D:report>C:calendar-query,omitempty"`
+	Multiget *struct{} `xml:"D:report>C:calendar-multiget,omitempty"`
+}
+
+const (
+	statusOK       = "HTTP/1.1 200 OK"
+	statusNotFound = "HTTP/1.1 404 Not Found"
+	todoType       = "text/calendar; charset=utf-8; component=vtodo"
+)
+
+func (c *CalDAV) options(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("DAV", "1, 3, calendar-access")
+	w.Header().Set("Allow", "OPTIONS, GET, PUT, DELETE, PROPFIND, REPORT")
+	w.WriteHeader(http.StatusOK)
+}
+
+// propfindHome describes the home, which is also the principal, and with
+// Depth 1 its calendars: one per list, the default list always among them.
+func (c *CalDAV) propfindHome(w http.ResponseWriter, r *http.Request) {
+	home := &davHref{Href: calDAVRoot}
+	out := []davResponse{found(calDAVRoot, davProp{
+		ResourceType: &davResourceType{Collection: &struct{}{}, Principal: &struct{}{}},
+		DisplayName:  "Todos",
+		Principal:    home,
+		PrincipalURL: home,
+		HomeSet:      home,
+	})}
+	if r.Header.Get("Depth") != "0" {
+		lists, err := c.cal.Lists(r.Context())
+		if err != nil {
+			c.fail(w, r, err)
+			return
+		}
+		for _, l := range lists {
+			p, err := c.calendarProp(r, l)
+			if err != nil {
+				c.fail(w, r, err)
+				return
+			}
+			out = append(out, found(calendarHref(l), p))
+		}
+	}
+	c.multistatus(w, r, out)
+}
+
+// propfindCalendar describes a list and with Depth 1 its todos.
+func (c *CalDAV) propfindCalendar(w http.ResponseWriter, r *http.Request) {
+	list, ok := c.list(w, r)
+	if !ok {
+		return
+	}
+	p, err := c.calendarProp(r, list)
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	out := []davResponse{found(calendarHref(list), p)}
+	if r.Header.Get("Depth") != "0" {
+		err = c.cal.Each(r.Context(), list, func(t *model.Todo) error {
+			out = append(out, found(todoHref(t), todoProp(t)))
+			return nil
+		})
+		if err != nil {
+			c.fail(w, r, err)
+			return
+		}
+	}
+	c.multistatus(w, r, out)
+}
+
+func (c *CalDAV) propfindTodo(w http.ResponseWriter, r *http.Request) {
+	t, ok := c.todo(w, r)
+	if !ok {
+		return
+	}
+	c.multistatus(w, r, []davResponse{found(todoHref(t), todoProp(t))})
+}
+
+// calendarProp describes list. Its CTag hashes the names and ETags of its
+// todos, so it changes whenever one is added, changed or removed.
+func (c *CalDAV) calendarProp(r *http.Request, list string) (davProp, error) {
+	h := fnv.New64a()
+	err := c.cal.Each(r.Context(), list, func(t *model.Todo) error {
+		io.WriteString(h, caldav.Name(t)+caldav.ETag(t))
+		return nil
+	})
+	if err != nil {
+		return davProp{}, err
+	}
+	name := list
+	if name == "" {
+		name = "Todos"
+	}
+	tag := `"` + strconv.FormatUint(h.Sum64(), 16) + `"`
+	p := davProp{
+		ResourceType: &davResourceType{Collection: &struct{}{}, Calendar: &struct{}{}},
+		DisplayName:  name,
+		Principal:    &davHref{Href: calDAVRoot},
+		Components:   &davComponents{},
+		Reports:      &davReports{Reports: []davReport{{Query: &struct{}{}}, {Multiget: &struct{}{}}}},
+		CTag:         tag,
+		ETag:         tag,
+	}
+	p.Components.Comp.Name = "VTODO"
+	return p, nil
+}
+
+func todoProp(t *model.Todo) davProp {
+	return davProp{ResourceType: &davResourceType{}, ETag: caldav.ETag(t), ContentType: todoType}
+}
+
+// report answers calendar-query, with every todo of the list, and
+// calendar-multiget, with the todos it names. Filters are not applied; a
+// client keeps what it asked for.
+func (c *CalDAV) report(w http.ResponseWriter, r *http.Request) {
+	list, ok := c.list(w, r)
+	if !ok {
+		return
+	}
+	kind, hrefs, withData, err := readReport(r.Body)
+	if err != nil {
+		c.rnd.Problem(w, r, http.StatusBadRequest, "The report is not valid XML: %v", err)
+		return
+	}
+	var out []davResponse
+	add := func(t *model.Todo) error {
+		p := todoProp(t)
+		if withData {
+			var buf bytes.Buffer
+			if err := caldav.Encode(&buf, t); err != nil {
+				return err
+			}
+			p.CalendarData = buf.String()
+		}
+		out = append(out, found(todoHref(t), p))
+		return nil
+	}
+	switch kind {
+	case "calendar-query":
+		err = c.cal.Each(r.Context(), list, add)
+	case "calendar-multiget":
+		for _, href := range hrefs {
+			name, _ := url.PathUnescape(strings.TrimSuffix(path.Base(href), ".ics"))
+			t, gerr := c.cal.Get(r.Context(), name)
+			switch {
+			case errors.Is(gerr, store.ErrNotFound) || gerr == nil && t.List != list:
+				out = append(out, davResponse{Href: href, Status: statusNotFound})
+				continue
+			case gerr != nil:
+				err = gerr
+			default:
+				err = add(t)
+			}
+			if err != nil {
+				break
+			}
+		}
+	default:
+		c.rnd.Problem(w, r, http.StatusForbidden, "Only calendar-query and calendar-multiget reports are supported")
+		return
+	}
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	c.multistatus(w, r, out)
+}
+
+// readReport returns the name of a REPORT body's root element, the hrefs
+// it lists and whether it asks for calendar-data.
+func readReport(body io.Reader) (kind string, hrefs []string, withData bool, err error) {
+	dec := xml.NewDecoder(body)
+	inHref := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return kind, hrefs, withData, nil
+		}
+		if err != nil {
+			return "", nil, false, err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case kind == "":
+				kind = tok.Name.Local
+			case tok.Name.Local == "href":
+				inHref = true
+				hrefs = append(hrefs, "")
+			case tok.Name.Local == "calendar-data":
+				withData = true
+			}
+		case xml.EndElement:
+			inHref = false
+		case xml.CharData:
+			if inHref {
+				hrefs[len(hrefs)-1] += strings.TrimSpace(string(tok))
+			}
+		}
+	}
+}
+
+func (c *CalDAV) getTodo(w http.ResponseWriter, r *http.Request) {
+	t, ok := c.todo(w, r)
+	if !ok {
+		return
+	}
+	var buf bytes.Buffer
+	if err := caldav.Encode(&buf, t); err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", todoType)
+	w.Header().Set("ETag", caldav.ETag(t))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// putTodo creates or replaces a todo, honouring If-Match and
+// If-None-Match: * so clients don't overwrite changes they haven't seen.
+func (c *CalDAV) putTodo(w http.ResponseWriter, r *http.Request) {
+	list, ok := c.list(w, r)
+	if !ok {
+		return
+	}
+	name := resourceName(r)
+	existing, err := c.cal.Get(r.Context(), name)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		c.fail(w, r, err)
+		return
+	}
+	if !preconditions(r, existing) {
+		c.rnd.Problem(w, r, http.StatusPreconditionFailed, "The todo has changed")
+		return
+	}
+	t, created, err := c.cal.Put(r.Context(), list, name, http.MaxBytesReader(w, r.Body, maxVTODO))
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", caldav.ETag(t))
+	if created {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *CalDAV) deleteTodo(w http.ResponseWriter, r *http.Request) {
+	t, ok := c.todo(w, r)
+	if !ok {
+		return
+	}
+	if !preconditions(r, t) {
+		c.rnd.Problem(w, r, http.StatusPreconditionFailed, "The todo has changed")
+		return
+	}
+	if err := c.cal.Delete(r.Context(), caldav.Name(t)); err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	c.rnd.NoContent(w)
+}
+
+// preconditions reports whether r's If-Match and If-None-Match hold for
+// t, which is nil if there is no such todo.
+func preconditions(r *http.Request, t *model.Todo) bool {
+	if m := r.Header.Get("If-Match"); m != "" {
+		return t != nil && (m == "*" || m == caldav.ETag(t))
+	}
+	if r.Header.Get("If-None-Match") == "*" {
+		return t == nil
+	}
+	return true
+}
+
+// list returns the list the URL names.
+func (c *CalDAV) list(w http.ResponseWriter, r *http.Request) (string, bool) {
+	seg, err := url.PathUnescape(chi.URLParam(r, "list"))
+	if err != nil {
+		c.rnd.Problem(w, r, http.StatusNotFound, "Calendar not found")
+		return "", false
+	}
+	if seg == defaultCalendar {
+		return "", true
+	}
+	return seg, true
+}
+
+// todo returns the todo the URL names, answering 404 if it is not on the
+// URL's list.
+func (c *CalDAV) todo(w http.ResponseWriter, r *http.Request) (*model.Todo, bool) {
+	list, ok := c.list(w, r)
+	if !ok {
+		return nil, false
+	}
+	t, err := c.cal.Get(r.Context(), resourceName(r))
+	if err == nil && t.List != list {
+		err = store.ErrNotFound
+	}
+	if err != nil {
+		c.fail(w, r, err)
+		return nil, false
+	}
+	return t, true
+}
+
+func resourceName(r *http.Request) string {
+	name, err := url.PathUnescape(chi.URLParam(r, "name"))
+	if err != nil {
+		name = chi.URLParam(r, "name")
+	}
+	return strings.TrimSuffix(name, ".ics")
+}
+
+func calendarHref(list string) string {
+	if list == "" {
+		list = defaultCalendar
+	}
+	return calDAVRoot + url.PathEscape(list) + "/"
+}
+
+func todoHref(t *model.Todo) string {
+	return calendarHref(t.List) + url.PathEscape(caldav.Name(t)) + ".ics"
+}
+
+func found(href string, p davProp) davResponse {
+	return davResponse{Href: href, Propstat: &davPropstat{Prop: p, Status: statusOK}}
+}
+
+func (c *CalDAV) multistatus(w http.ResponseWriter, r *http.Request, responses []davResponse) {
+	b, err := xml.Marshal(davMultistatus{
+		D:         "DAV:",
+		C:         "urn:ietf:params:xml:ns:caldav",
+		CS:        "http://calendarserver.org/ns/",
+		Responses: responses,
+	})
+	if err != nil {
+		c.rnd.Problem(w, r, http.StatusInternalServerError, "failed to describe the calendars")
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	io.WriteString(w, xml.Header)
+	w.Write(b)
+}
+
+func (c *CalDAV) fail(w http.ResponseWriter, r *http.Request, err error) {
+	status, msg := classify(r, err, "failed to sync the calendar")
+	c.rnd.Problem(w, r, status, msg)
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/service"
+)
+
+// edit is an entry of a bulk or sync request: a todo and, optionally, the
+// copy of it the client edited, which ?on_conflict=merge needs.
+type edit struct {
+	todo
+	// Base is the todo as the client last saw it. Without one, the todo's
+	// own updated_at says which version it was edited from.
+	Base *todo `json:"base,omitempty"`
+}
+
+func (e edit) item(onConflict string) service.BulkItem {
+	it := service.BulkItem{ID: strings.TrimSpace(e.ID), TodoInput: e.input(), OnConflict: onConflict}
+	switch {
+	case e.Base != nil:
+		in := e.Base.input()
+		it.Base = &service.Base{UpdatedAt: e.Base.UpdatedAt, Values: &in}
+	case !e.UpdatedAt.IsZero():
+		it.Base = &service.Base{UpdatedAt: e.UpdatedAt}
+	}
+	return it
+}
+
+// onConflict reads ?on_conflict=, the strategy for edits made against an
+// out-of-date todo: last_write_wins (the default), merge or reject.
+func (h *Handler) onConflict(w http.ResponseWriter, r *http.Request) (string, bool) {
+	s := r.URL.Query().Get("on_conflict")
+	if s == "" {
+		return service.ConflictLastWriteWins, true
+	}
+	for _, known := range service.ConflictStrategies {
+		if s == known {
+			return s, true
+		}
+	}
+	h.rnd.Problem(w, r, http.StatusBadRequest, "on_conflict must be one of %s", strings.Join(service.ConflictStrategies, ", "))
+	return "", false
+}
+
+// conflict shows both sides of an edit that conflicted, so the client can
+// settle it and try again against the server's version.
+type conflict struct {
+	// Fields both sides changed, when merging.
+	Fields []string `json:"fields,omitempty"`
+	Server todo     `json:"server"`
+	Client todo     `json:"client"`
+}
+
+// toConflict is the conflict err reports for sent, or nil if err isn't one.
+// The server's copy shows its due date in loc.
+func toConflict(err error, sent todo, loc *time.Location) *conflict {
+	var ce *service.ConflictError
+	if !errors.As(err, &ce) {
+		return nil
+	}
+	return &conflict{Fields: ce.Fields, Server: toTodo(*ce.Current, loc), Client: sent}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/inbound"
+	"dhruvarora9/personal-todo-golang/internal/middleware"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"github.com/go-chi/chi"
+)
+
+// Email turns mail that Mailgun or SES posts into todos on the tenant
+// whose address it was sent to.
+type Email struct {
+	todos      *service.TodoService
+	addrs      *inbound.Addresses
+	rnd        *render.Renderer
+	mailgunKey string
+	sesToken   string
+	now        func() time.Time
+	// client confirms SNS subscriptions.
+	client *http.Client
+}
+
+func NewEmail(todos *service.TodoService, addrs *inbound.Addresses, rnd *render.Renderer, mailgunKey, sesToken string, now func() time.Time) *Email {
+	return &Email{todos: todos, addrs: addrs, rnd: rnd, mailgunKey: mailgunKey, sesToken: sesToken, now: now, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Routes returns the webhooks of the providers configured.
+func (e *Email) Routes() http.Handler {
+	rg := chi.NewRouter()
+	if e.mailgunKey != "" {
+		rg.With(middleware.VerifyMailgun(e.mailgunKey, e.now, e.rnd)).Post("/mailgun", e.mailgun)
+	}
+	if e.sesToken != "" {
+		rg.Post("/ses", e.ses)
+	}
+	return rg
+}
+
+// Address shows the address that mails todos to the caller's tenant.
+func (e *Email) Address(w http.ResponseWriter, r *http.Request) {
+	e.rnd.Data(w, r, http.StatusOK, render.M{"address": e.addrs.Address(tenant.FromContext(r.Context()))})
+}
+
+// create adds the todo a message asks for to the tenant of the first of
+// recipients that has an address. ok is false for mail to no such address.
+func (e *Email) create(ctx context.Context, recipients []string, subject, text string) (ok bool, err error) {
+	id, ok := e.addrs.FirstTenant(recipients)
+	if !ok {
+		return false, nil
+	}
+	in, err := inbound.Todo(subject, text)
+	if err != nil {
+		return true, err
+	}
+	_, err = e.todos.Create(tenant.NewContext(ctx, id), in)
+	return true, err
+}
+
+// mailgun handles a message forwarded by a Mailgun route. Mail it can't
+// take is answered 406, which tells Mailgun not to retry.
+func (e *Email) mailgun(w http.ResponseWriter, r *http.Request) {
+	f := r.PostForm
+	ok, err := e.create(r.Context(), []string{f.Get("recipient")}, f.Get("subject"), f.Get("body-plain"))
+	var ve *service.ValidationError
+	switch {
+	case !ok:
+		e.rnd.Problem(w, r, http.StatusNotAcceptable, "The recipient is not a todo address")
+	case errors.Is(err, inbound.ErrEmpty), errors.As(err, &ve):
+		e.rnd.Problem(w, r, http.StatusNotAcceptable, err.Error())
+	case err != nil:
+		middleware.RecordError(r, err)
+		e.rnd.Problem(w, r, http.StatusInternalServerError, "failed to create the todo")
+	default:
+		e.rnd.NoContent(w)
+	}
+}
+
+// snsMessage is what SNS posts to a subscribed endpoint.
+type snsMessage struct {
+	Type         string `json:"Type"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+// sesNotification is the Message of an SES receipt rule's SNS action.
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	Mail             struct {
+		Destination []string `json:"destination"`
+	} `json:"mail"`
+	// Content is the raw message, base64 encoded if the action asks.
+	Content string `json:"content"`
+}
+
+// ses handles SES mail delivered through SNS, which must post to
+// ?token=<ses_token>. Only store failures are errors, so SNS doesn't
+// retry mail that will never be taken.
+func (e *Email) ses(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(e.sesToken)) != 1 {
+		e.rnd.Problem(w, r, http.StatusUnauthorized, "The token is invalid")
+		return
+	}
+	var msg snsMessage
+	if err := json.NewDecoder(io.LimitReader(r.Body, 25<<20)).Decode(&msg); err != nil {
+		e.rnd.Problem(w, r, http.StatusBadRequest, "The request body is not valid JSON: %v", err)
+		return
+	}
+	switch msg.Type {
+	case "SubscriptionConfirmation":
+		if err := e.confirm(r.Context(), msg.SubscribeURL); err != nil {
+			middleware.RecordError(r, err)
+			e.rnd.Problem(w, r, http.StatusBadGateway, "failed to confirm the subscription")
+			return
+		}
+	case "Notification":
+		var n sesNotification
+		if json.Unmarshal([]byte(msg.Message), &n) != nil || n.NotificationType != "Received" {
+			break
+		}
+		raw := n.Content
+		if b, err := base64.StdEncoding.DecodeString(raw); err == nil {
+			raw = string(b)
+		}
+		m, err := inbound.Parse(strings.NewReader(raw))
+		if err != nil {
+			break
+		}
+		_, err = e.create(r.Context(), n.Mail.Destination, m.Subject, m.Text)
+		var ve *service.ValidationError
+		if err != nil && !errors.Is(err, inbound.ErrEmpty) && !errors.As(err, &ve) {
+			middleware.RecordError(r, err)
+			e.rnd.Problem(w, r, http.StatusInternalServerError, "failed to create the todo")
+			return
+		}
+	}
+	e.rnd.NoContent(w)
+}
+
+// confirm follows an SNS subscription's confirmation link, which must lead
+// to AWS.
+func (e *Email) confirm(ctx context.Context, link string) error {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != "https" || !strings.HasSuffix(u.Hostname(), ".amazonaws.com") {
+		return errors.New("email: the SNS confirmation link doesn't lead to AWS")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("email: SNS answered the confirmation with " + resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+	"dhruvarora9/personal-todo-golang/internal/middleware"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+)
+
+// fail is the one place errors from the service and store become HTTP
+// responses. Anything it doesn't recognise is a server error: the details
+// go to the error reporter and the client only sees msg.
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	status, msg := classify(r, err, msg)
+	h.rnd.Problem(w, r, status, msg)
+}
+
+// classify picks the status and message for err, reporting errors it
+// doesn't recognise and answering those with msg. The message is in the
+// request's locale.
+func classify(r *http.Request, err error, msg string) (int, string) {
+	locale := i18n.FromContext(r.Context())
+	var ve *service.ValidationError
+	var ce *service.ConflictError
+	switch {
+	case errors.As(err, &ve):
+		return http.StatusBadRequest, ve.In(locale)
+	case errors.As(err, &ce):
+		return http.StatusConflict, ce.In(locale)
+	case errors.Is(err, store.ErrInvalidID):
+		return http.StatusBadRequest, i18n.T(locale, "The id is invalid")
+	case errors.Is(err, store.ErrNotFound):
+		return http.StatusNotFound, i18n.T(locale, "Todo not found")
+	case errors.Is(err, service.ErrTimerRunning), errors.Is(err, service.ErrNoTimer),
+		errors.Is(err, service.ErrPomodoroRunning), errors.Is(err, service.ErrPomodoroEnded),
+		errors.Is(err, service.ErrFieldExists), errors.Is(err, service.ErrBlocked),
+		errors.Is(err, service.ErrCycle):
+		return http.StatusConflict, i18n.T(locale, err.Error())
+	case errors.Is(err, service.ErrCursorExpired):
+		return http.StatusGone, i18n.T(locale, err.Error())
+	case errors.Is(err, store.ErrConflict):
+		return http.StatusConflict, i18n.T(locale, "Todo conflicts with an existing one")
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, i18n.T(locale, "The request took too long")
+	}
+	middleware.RecordError(r, err)
+	return http.StatusInternalServerError, i18n.T(locale, msg)
+}
+
+func (h *Handler) badBody(w http.ResponseWriter, r *http.Request, err error) {
+	h.rnd.Problem(w, r, http.StatusBadRequest, "The request body is not valid JSON: %v", err)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		locale     string
+		err        error
+		wantStatus int
+		wantMsg    string
+	}{
+		{"", &service.ValidationError{Field: "title", Message: "title is required"}, http.StatusBadRequest, "title is required"},
+		{"", &service.ConflictError{Fields: []string{"title", "tags"}}, http.StatusConflict, "the todo was changed meanwhile: title, tags"},
+		{"", store.ErrInvalidID, http.StatusBadRequest, "The id is invalid"},
+		{"", fmt.Errorf("get todo: %w", store.ErrNotFound), http.StatusNotFound, "Todo not found"},
+		{"de", store.ErrNotFound, http.StatusNotFound, "Todo nicht gefunden"},
+		{"", service.ErrTimerRunning, http.StatusConflict, service.ErrTimerRunning.Error()},
+		{"", service.ErrCycle, http.StatusConflict, service.ErrCycle.Error()},
+		{"", service.ErrCursorExpired, http.StatusGone, service.ErrCursorExpired.Error()},
+		{"", store.ErrConflict, http.StatusConflict, "Todo conflicts with an existing one"},
+		{"", fmt.Errorf("list: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, "The request took too long"},
+		{"", errors.New("connection reset"), http.StatusInternalServerError, "failed to list todos"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/todo", nil)
+		if tt.locale != "" {
+			r = r.WithContext(i18n.NewContext(r.Context(), tt.locale))
+		}
+		status, msg := classify(r, tt.err, "failed to list todos")
+		if status != tt.wantStatus || msg != tt.wantMsg {
+			t.Errorf("classify(%v) in %q = %d, %q, want %d, %q", tt.err, tt.locale, status, msg, tt.wantStatus, tt.wantMsg)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/jobs"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"github.com/go-chi/chi"
+)
+
+const (
+	exportJob = "account.export"
+	// smallExport is the most todos GET /account/export.zip builds while
+	// the client waits; larger accounts are exported in the background.
+	smallExport = 1000
+	// exportTTL is how long a finished archive can be downloaded.
+	exportTTL = time.Hour
+	// exportFiles matches the names of archives in the export directory.
+	exportFiles = "todo-export-*.zip"
+)
+
+// Exports serves the account data export mounted at /account. Background
+// archives are written to files in its directory and kept, one per tenant,
+// for exportTTL; Expire removes them after that, and Close when the server
+// stops.
+type Exports struct {
+	todos *service.TodoService
+	goals *service.GoalService
+	queue *jobs.Queue
+	dir   string
+	rnd   *render.Renderer
+	now   func() time.Time
+	log   *log.Logger
+
+	mu      sync.Mutex
+	exports map[string]*export // by tenant
+	closed  bool
+}
+
+// export is one tenant's latest background export.
+type export struct {
+	ID         string
+	JobID      string
+	CreatedAt  time.Time
+	FinishedAt time.Time
+	// path is the finished archive's file.
+	path string
+}
+
+// exportJobPayload is what the export job is queued with.
+type exportJobPayload struct {
+	Tenant string `json:"tenant"`
+	ID     string `json:"id"`
+}
+
+// NewExports returns the export API and registers its job with queue.
+// Archives go in dir, which is created if need be; those a previous run
+// left there can no longer be downloaded and are removed.
+func NewExports(todos *service.TodoService, goals *service.GoalService, queue *jobs.Queue, dir string, rnd *render.Renderer, now func() time.Time, logger *log.Logger) (*Exports, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	stale, err := filepath.Glob(filepath.Join(dir, exportFiles))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range stale {
+		if err := os.Remove(name); err != nil {
+			return nil, err
+		}
+	}
+	e := &Exports{todos: todos, goals: goals, queue: queue, dir: dir, rnd: rnd, now: now, log: logger, exports: map[string]*export{}}
+	queue.Register(exportJob, e.run)
+	return e, nil
+}
+
+func (e *Exports) Routes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Get("/export.zip", e.exportZip)
+		r.Post("/exports", e.startExport)
+		r.Get("/exports/{id}", e.exportStatus)
+		r.Get("/exports/{id}/download", e.download)
+	})
+	return rg
+}
+
+// exportZip sends the archive straight away for small accounts and starts
+// a background export, answering 202 with its status, for large ones.
+func (e *Exports) exportZip(w http.ResponseWriter, r *http.Request) {
+	n, err := e.todos.Count(r.Context(), store.TodoFilter{})
+	if err != nil {
+		e.fail(w, r, err)
+		return
+	}
+	if n > smallExport {
+		e.startExport(w, r)
+		return
+	}
+	var buf bytes.Buffer
+	if err := e.build(r.Context(), &buf); err != nil {
+		e.fail(w, r, err)
+		return
+	}
+	e.sendZip(w, r, bytes.NewReader(buf.Bytes()), e.now())
+}
+
+func (e *Exports) startExport(w http.ResponseWriter, r *http.Request) {
+	id := make([]byte, 8)
+	rand.Read(id)
+	ex := &export{ID: hex.EncodeToString(id), CreatedAt: e.now()}
+	t := tenant.FromContext(r.Context())
+	jobID, err := e.queue.Enqueue(exportJob, exportJobPayload{Tenant: t, ID: ex.ID})
+	if errors.Is(err, jobs.ErrFull) {
+		e.rnd.Problem(w, r, http.StatusServiceUnavailable, "Too much work is queued, please try again later")
+		return
+	}
+	if err != nil {
+		e.fail(w, r, err)
+		return
+	}
+	ex.JobID = jobID
+	e.mu.Lock()
+	if old, ok := e.exports[t]; ok {
+		e.remove(old)
+	}
+	e.exports[t] = ex
+	e.mu.Unlock()
+	w.Header().Set("Location", "/account/exports/"+ex.ID)
+	e.rnd.Data(w, r, http.StatusAccepted, e.status(ex))
+}
+
+func (e *Exports) exportStatus(w http.ResponseWriter, r *http.Request) {
+	ex, ok := e.lookup(r)
+	if !ok {
+		e.rnd.Problem(w, r, http.StatusNotFound, "Export not found")
+		return
+	}
+	e.rnd.Data(w, r, http.StatusOK, e.status(&ex))
+}
+
+func (e *Exports) download(w http.ResponseWriter, r *http.Request) {
+	ex, ok := e.lookup(r)
+	if !ok {
+		e.rnd.Problem(w, r, http.StatusNotFound, "Export not found")
+		return
+	}
+	if ex.path == "" {
+		e.rnd.Problem(w, r, http.StatusConflict, "The export is not ready yet")
+		return
+	}
+	f, err := os.Open(ex.path)
+	if err != nil {
+		// Expired since the lookup.
+		e.rnd.Problem(w, r, http.StatusNotFound, "Export not found")
+		return
+	}
+	defer f.Close()
+	e.sendZip(w, r, f, ex.FinishedAt)
+}
+
+// lookup finds the caller's export named in the URL, forgetting it once it
+// has expired.
+func (e *Exports) lookup(r *http.Request) (export, bool) {
+	t := tenant.FromContext(r.Context())
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ex, ok := e.exports[t]
+	if !ok || ex.ID != strings.TrimSpace(chi.URLParam(r, "id")) {
+		return export{}, false
+	}
+	if e.expired(ex) {
+		e.remove(ex)
+		delete(e.exports, t)
+		return export{}, false
+	}
+	return *ex, true
+}
+
+func (e *Exports) expired(ex *export) bool {
+	return !ex.FinishedAt.IsZero() && e.now().Sub(ex.FinishedAt) > exportTTL
+}
+
+// remove deletes the archive of ex, if it has one. e.mu must be held.
+func (e *Exports) remove(ex *export) {
+	if ex.path == "" {
+		return
+	}
+	if err := os.Remove(ex.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		e.log.Printf("account export: %v", err)
+	}
+	ex.path = ""
+}
+
+// Expire removes the archives that can no longer be downloaded, including
+// those nobody asks for again. It runs as a scheduled task.
+func (e *Exports) Expire(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for t, ex := range e.exports {
+		if e.expired(ex) {
+			e.remove(ex)
+			delete(e.exports, t)
+		}
+	}
+	return nil
+}
+
+// Close removes every archive, finished or not, for a server stopping.
+// Exports that finish afterwards are discarded.
+func (e *Exports) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for t, ex := range e.exports {
+		e.remove(ex)
+		delete(e.exports, t)
+	}
+	e.closed = true
+}
+
+// status describes ex as the job queue sees it.
+func (e *Exports) status(ex *export) render.M {
+	out := render.M{"id": ex.ID, "state": jobs.StateQueued, "created_at": ex.CreatedAt}
+	switch {
+	case ex.path != "":
+		out["state"] = jobs.StateDone
+		out["download_url"] = "/account/exports/" + ex.ID + "/download"
+		out["expires_at"] = ex.FinishedAt.Add(exportTTL)
+	default:
+		if j, err := e.queue.Get(ex.JobID); err == nil {
+			out["state"] = j.State
+			if j.LastError != "" {
+				out["error"] = "The export failed; try again later"
+			}
+		}
+	}
+	return out
+}
+
+func (e *Exports) sendZip(w http.ResponseWriter, r *http.Request, content io.ReadSeeker, at time.Time) {
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="todo-export-`+at.Format("2006-01-02")+`.zip"`)
+	http.ServeContent(w, r, "", at, content)
+}
+
+func (e *Exports) fail(w http.ResponseWriter, r *http.Request, err error) {
+	e.log.Printf("account export: %v", err)
+	e.rnd.Problem(w, r, http.StatusInternalServerError, "failed to export the account")
+}
+
+// run is the export job: it writes the archive to a file in the export
+// directory and keeps it for download, unless the tenant has started
+// another export since or the server is stopping.
+func (e *Exports) run(ctx context.Context, payload json.RawMessage) error {
+	var p exportJobPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(e.dir, exportFiles)
+	if err != nil {
+		return err
+	}
+	err = e.build(tenant.NewContext(ctx, p.Tenant), f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if ex, ok := e.exports[p.Tenant]; ok && ex.ID == p.ID && !e.closed {
+		ex.path, ex.FinishedAt = f.Name(), e.now()
+		return nil
+	}
+	os.Remove(f.Name())
+	return nil
+}
+
+// exportList is an entry of lists.json.
+type exportList struct {
+	Name  string `json:"name"`
+	Todos int    `json:"todos"`
+	Done  int    `json:"done"`
+}
+
+// build writes the zip of the tenant's data to w: todos.json, in the same
+// shape as the API, lists.json and goals.json.
+func (e *Exports) build(ctx context.Context, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	f, err := zw.Create("todos.json")
+	if err != nil {
+		return err
+	}
+	lists := map[string]*exportList{}
+	sep := "[\n"
+	err = e.todos.Each(ctx, store.TodoFilter{}, 0, 0, func(t *model.Todo) error {
+		b, err := json.Marshal(toTodo(*t, nil))
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, sep); err != nil {
+			return err
+		}
+		sep = ",\n"
+		if _, err := f.Write(b); err != nil {
+			return err
+		}
+		l, ok := lists[t.List]
+		if !ok {
+			l = &exportList{Name: t.List}
+			lists[t.List] = l
+		}
+		l.Todos++
+		if t.Completed {
+			l.Done++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if sep == "[\n" {
+		io.WriteString(f, sep)
+	}
+	if _, err := io.WriteString(f, "\n]\n"); err != nil {
+		return err
+	}
+
+	out := make([]exportList, 0, len(lists))
+	for _, l := range lists {
+		out = append(out, *l)
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Name < out[b].Name })
+	if err := writeJSON(zw, "lists.json", out); err != nil {
+		return err
+	}
+
+	all, err := e.goals.List(ctx)
+	if err != nil {
+		return err
+	}
+	goals := make([]goal, 0, len(all))
+	for _, p := range all {
+		goals = append(goals, toGoalProgress(p))
+	}
+	if err := writeJSON(zw, "goals.json", goals); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// writeJSON adds a file named name holding v, indented, to zw.
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
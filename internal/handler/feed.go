@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/render"
+)
+
+// activity is the JSON representation of a feed entry.
+type activity struct {
+	ID     string    `json:"id"`
+	Type   string    `json:"type"`
+	TodoID string    `json:"todo_id"`
+	Title  string    `json:"title,omitempty"`
+	List   string    `json:"list,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// Feed returns what happened to the todos, newest first: creations,
+// completions and deletions. It takes ?limit= (50 by default) and
+// ?cursor=, which the X-Next-Cursor header and the next_cursor meta of
+// the previous page give; the last page has neither.
+func (h *Handler) Feed(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			h.rnd.Problem(w, r, http.StatusBadRequest, "limit must be a number")
+			return
+		}
+		limit = n
+	}
+	entries, next, err := h.feed.Feed(r.Context(), r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch the feed")
+		return
+	}
+	out := make([]activity, 0, len(entries))
+	for _, a := range entries {
+		out = append(out, activity{
+			ID:     a.ID.Hex(),
+			Type:   a.Type,
+			TodoID: a.TodoID,
+			Title:  a.Title,
+			List:   a.List,
+			At:     a.At,
+		})
+	}
+	var meta render.M
+	if next != "" {
+		w.Header().Set("X-Next-Cursor", next)
+		meta = render.M{"next_cursor": next}
+	}
+	h.rnd.DataMeta(w, r, http.StatusOK, out, meta)
+}
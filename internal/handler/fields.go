@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"github.com/go-chi/chi"
+)
+
+// fieldDef is the JSON representation of a custom field definition.
+type fieldDef struct {
+	ID        string    `json:"id"`
+	List      string    `json:"list"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Options   []string  `json:"options,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func toFieldDef(d model.FieldDef) fieldDef {
+	return fieldDef{ID: d.ID.Hex(), List: d.List, Name: d.Name, Type: d.Type, Options: d.Options, CreatedAt: d.CreatedAt}
+}
+
+// FieldRoutes returns the router mounted at /fields, which defines the
+// custom fields of each list. Todos set them in their "fields".
+func (h *Handler) FieldRoutes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Get("/", h.listFields)
+		r.Post("/", h.defineField)
+		r.Delete("/{id}", h.deleteField)
+	})
+	return rg
+}
+
+// listFields returns the fields of ?list=, the default list if empty.
+func (h *Handler) listFields(w http.ResponseWriter, r *http.Request) {
+	defs, err := h.todos.ListFields(r.Context(), r.URL.Query().Get("list"))
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch custom fields")
+		return
+	}
+	out := make([]fieldDef, 0, len(defs))
+	for _, d := range defs {
+		out = append(out, toFieldDef(d))
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
+
+func (h *Handler) defineField(w http.ResponseWriter, r *http.Request) {
+	var in fieldDef
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	d, err := h.todos.DefineField(r.Context(), service.FieldInput{List: in.List, Name: in.Name, Type: in.Type, Options: in.Options})
+	if err != nil {
+		h.fail(w, r, err, "failed to save the custom field")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusCreated, toFieldDef(*d))
+}
+
+// deleteField removes a field along with the values todos hold for it.
+func (h *Handler) deleteField(w http.ResponseWriter, r *http.Request) {
+	err := h.todos.DeleteField(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")))
+	if errors.Is(err, store.ErrNotFound) {
+		h.rnd.Problem(w, r, http.StatusNotFound, "Custom field not found")
+		return
+	}
+	if err != nil {
+		h.fail(w, r, err, "failed to delete the custom field")
+		return
+	}
+	h.rnd.NoContent(w)
+}
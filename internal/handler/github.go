@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"dhruvarora9/personal-todo-golang/internal/github"
+	"dhruvarora9/personal-todo-golang/internal/middleware"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"github.com/go-chi/chi"
+)
+
+// GitHub receives the repository webhooks for the issue sync.
+type GitHub struct {
+	sync   *github.Sync
+	rnd    *render.Renderer
+	secret string
+}
+
+func NewGitHub(sync *github.Sync, rnd *render.Renderer, webhookSecret string) *GitHub {
+	return &GitHub{sync: sync, rnd: rnd, secret: webhookSecret}
+}
+
+func (g *GitHub) Routes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Use(middleware.VerifyGitHub(g.secret, g.rnd))
+	rg.Post("/webhook", g.webhook)
+	return rg
+}
+
+func (g *GitHub) webhook(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-GitHub-Event") != "issues" {
+		g.rnd.NoContent(w) // ping and events we don't use
+		return
+	}
+	var p struct {
+		Action     string       `json:"action"`
+		Issue      github.Issue `json:"issue"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		g.rnd.Problem(w, r, http.StatusBadRequest, "The request body is not valid JSON: %v", err)
+		return
+	}
+	if err := g.sync.IssueEvent(r.Context(), p.Action, p.Repository.FullName, p.Issue); err != nil {
+		middleware.RecordError(r, err)
+		g.rnd.Problem(w, r, http.StatusInternalServerError, "failed to apply the issue event")
+		return
+	}
+	g.rnd.NoContent(w)
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"github.com/go-chi/chi"
+)
+
+// goal is the JSON representation of a goal and its progress, which is
+// read-only.
+type goal struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	DueAt       *time.Time `json:"due_at"`
+	Todos       int        `json:"todos"`
+	Done        int        `json:"done"`
+	Percent     int        `json:"percent"`
+	Overdue     bool       `json:"overdue"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func toGoal(g model.Goal) goal {
+	return goal{
+		ID:          g.ID.Hex(),
+		Name:        g.Name,
+		Description: g.Description,
+		DueAt:       g.DueAt,
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+	}
+}
+
+func toGoalProgress(p service.GoalProgress) goal {
+	out := toGoal(p.Goal)
+	out.Todos, out.Done, out.Percent, out.Overdue = p.Total, p.Done, p.Percent, p.Overdue
+	return out
+}
+
+func (g goal) input() service.GoalInput {
+	return service.GoalInput{Name: g.Name, Description: g.Description, DueAt: g.DueAt}
+}
+
+// GoalRoutes returns the router mounted at /goals, which manages goals and
+// attaches todos to them.
+func (h *Handler) GoalRoutes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Get("/", h.listGoals)
+		r.Post("/", h.createGoal)
+		r.Get("/dashboard", h.goalDashboard)
+		r.Get("/{id}", h.getGoal)
+		r.Put("/{id}", h.updateGoal)
+		r.Delete("/{id}", h.deleteGoal)
+		r.Put("/{id}/todos/{todo}", h.attachTodo)
+		r.Delete("/{id}/todos/{todo}", h.detachTodo)
+	})
+	return rg
+}
+
+// failGoal is fail with a not-found message that names goals.
+func (h *Handler) failGoal(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	if errors.Is(err, store.ErrNotFound) {
+		h.rnd.Problem(w, r, http.StatusNotFound, "Goal not found")
+		return
+	}
+	h.fail(w, r, err, msg)
+}
+
+func (h *Handler) listGoals(w http.ResponseWriter, r *http.Request) {
+	all, err := h.goals.List(r.Context())
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch goals")
+		return
+	}
+	out := make([]goal, 0, len(all))
+	for _, p := range all {
+		out = append(out, toGoalProgress(p))
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
+
+// goalDashboard returns the goals not yet reached, soonest due first.
+func (h *Handler) goalDashboard(w http.ResponseWriter, r *http.Request) {
+	open, err := h.goals.Dashboard(r.Context())
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch the goal dashboard")
+		return
+	}
+	out := make([]goal, 0, len(open))
+	for _, p := range open {
+		out = append(out, toGoalProgress(p))
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
+
+func (h *Handler) getGoal(w http.ResponseWriter, r *http.Request) {
+	p, err := h.goals.Get(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.failGoal(w, r, err, "failed to fetch the goal")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusOK, toGoalProgress(*p))
+}
+
+func (h *Handler) createGoal(w http.ResponseWriter, r *http.Request) {
+	var in goal
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	g, err := h.goals.Create(r.Context(), in.input())
+	if err != nil {
+		h.fail(w, r, err, "failed to save the goal")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusCreated, toGoal(*g))
+}
+
+func (h *Handler) updateGoal(w http.ResponseWriter, r *http.Request) {
+	var in goal
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	g, err := h.goals.Update(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")), in.input())
+	if err != nil {
+		h.failGoal(w, r, err, "failed to save the goal")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusOK, toGoal(*g))
+}
+
+func (h *Handler) deleteGoal(w http.ResponseWriter, r *http.Request) {
+	if err := h.goals.Delete(r.Context(), strings.TrimSpace(chi.URLParam(r, "id"))); err != nil {
+		h.failGoal(w, r, err, "failed to delete the goal")
+		return
+	}
+	h.rnd.NoContent(w)
+}
+
+// attachTodo attaches the todo {todo} to the goal, detaching it from any
+// other goal.
+func (h *Handler) attachTodo(w http.ResponseWriter, r *http.Request) {
+	err := h.goals.Attach(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")), strings.TrimSpace(chi.URLParam(r, "todo")))
+	if err != nil {
+		h.fail(w, r, err, "failed to attach the todo")
+		return
+	}
+	h.rnd.NoContent(w)
+}
+
+func (h *Handler) detachTodo(w http.ResponseWriter, r *http.Request) {
+	err := h.goals.Detach(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")), strings.TrimSpace(chi.URLParam(r, "todo")))
+	if err != nil {
+		h.fail(w, r, err, "failed to detach the todo")
+		return
+	}
+	h.rnd.NoContent(w)
+}
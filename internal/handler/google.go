@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+
+	"dhruvarora9/personal-todo-golang/internal/gcal"
+	"dhruvarora9/personal-todo-golang/internal/middleware"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"github.com/go-chi/chi"
+)
+
+const googleStateCookie = "google_oauth_state"
+
+// Google serves the OAuth flow that connects a Google Calendar.
+type Google struct {
+	sync *gcal.Sync
+	rnd  *render.Renderer
+}
+
+func NewGoogle(sync *gcal.Sync, rnd *render.Renderer) *Google {
+	return &Google{sync: sync, rnd: rnd}
+}
+
+func (g *Google) Routes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Get("/", g.status)
+		r.Get("/connect", g.connect)
+		r.Get("/callback", g.callback)
+		r.Delete("/", g.disconnect)
+	})
+	return rg
+}
+
+func (g *Google) status(w http.ResponseWriter, r *http.Request) {
+	connected, err := g.sync.Connected(r.Context())
+	if err != nil {
+		middleware.RecordError(r, err)
+		g.rnd.Problem(w, r, http.StatusInternalServerError, "failed to load the Google account")
+		return
+	}
+	g.rnd.Data(w, r, http.StatusOK, render.M{"connected": connected})
+}
+
+// connect sends the browser to Google's consent screen. The state is kept
+// in a cookie and checked on the way back.
+func (g *Google) connect(w http.ResponseWriter, r *http.Request) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		middleware.RecordError(r, err)
+		g.rnd.Problem(w, r, http.StatusInternalServerError, "failed to start the Google sign-in")
+		return
+	}
+	state := hex.EncodeToString(b)
+	http.SetCookie(w, &http.Cookie{
+		Name:     googleStateCookie,
+		Value:    state,
+		Path:     "/integrations/google",
+		MaxAge:   600,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, g.sync.AuthURL(state), http.StatusFound)
+}
+
+func (g *Google) callback(w http.ResponseWriter, r *http.Request) {
+	c, err := r.Cookie(googleStateCookie)
+	state := r.URL.Query().Get("state")
+	if err != nil || subtle.ConstantTimeCompare([]byte(c.Value), []byte(state)) != 1 {
+		g.rnd.Problem(w, r, http.StatusBadRequest, "The sign-in state does not match; start again from /integrations/google/connect")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: googleStateCookie, Path: "/integrations/google", MaxAge: -1})
+	if msg := r.URL.Query().Get("error"); msg != "" {
+		g.rnd.Problem(w, r, http.StatusBadRequest, "Google sign-in failed: %s", msg)
+		return
+	}
+	if err := g.sync.Connect(r.Context(), r.URL.Query().Get("code")); err != nil {
+		middleware.RecordError(r, err)
+		g.rnd.Problem(w, r, http.StatusBadGateway, "failed to connect the Google account")
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+func (g *Google) disconnect(w http.ResponseWriter, r *http.Request) {
+	if err := g.sync.Disconnect(r.Context()); err != nil {
+		middleware.RecordError(r, err)
+		g.rnd.Problem(w, r, http.StatusInternalServerError, "failed to disconnect the Google account")
+		return
+	}
+	g.rnd.NoContent(w)
+}
@@ -0,0 +1,79 @@
+// Package handler implements the HTTP endpoints.
+package handler
+
+import (
+	"log"
+	"net/http"
+	"runtime"
+
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/version"
+	"github.com/go-chi/chi"
+)
+
+// Handler serves the JSON API.
+type Handler struct {
+	todos        *service.TodoService
+	integrations *service.IntegrationService
+	smartLists   *service.SmartListService
+	push         *service.PushService
+	shares       *service.ShareService
+	feed         *service.FeedService
+	streaks      *service.StreakService
+	goals        *service.GoalService
+	sync         *service.SyncService
+	rnd          *render.Renderer
+	log          *log.Logger
+}
+
+func New(todos *service.TodoService, integrations *service.IntegrationService, smartLists *service.SmartListService, push *service.PushService, shares *service.ShareService, feed *service.FeedService, streaks *service.StreakService, goals *service.GoalService, sync *service.SyncService, rnd *render.Renderer, logger *log.Logger) *Handler {
+	return &Handler{todos: todos, integrations: integrations, smartLists: smartLists, push: push, shares: shares, feed: feed, streaks: streaks, goals: goals, sync: sync, rnd: rnd, log: logger}
+}
+
+func (h *Handler) Version(w http.ResponseWriter, r *http.Request) {
+	h.rnd.Data(w, r, http.StatusOK, render.M{
+		"version":    version.Version,
+		"commit":     version.Commit,
+		"build_date": version.BuildDate,
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+	})
+}
+
+// TodoRoutes returns the router mounted at /todo.
+func (h *Handler) TodoRoutes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Get("/", h.fetchTodo)
+		r.Head("/", h.countTodos)
+		r.Get("/archive", h.searchArchive)
+		r.Get("/search", h.searchTodos)
+		r.Get("/time", h.timeReport)
+		r.Get("/pomodoros/stats", h.pomodoroStats)
+		r.Get("/workload", h.workload)
+		r.Get("/board", h.board)
+		r.Get("/schedule", h.schedule)
+		r.Get("/nearby", h.nearby)
+		r.Get("/dependencies", h.dependencies)
+		r.Post("/", h.createTodo)
+		r.Post("/bulk", h.bulkTodos)
+		r.Get("/{id}", h.getTodo)
+		r.Put("/{id}", h.updateTodo)
+		r.Delete("/{id}", h.deleteTodo)
+		r.Post("/{id}/timer/start", h.startTimer)
+		r.Post("/{id}/timer/stop", h.stopTimer)
+		r.Post("/{id}/pomodoros", h.startPomodoro)
+		r.Post("/{id}/pomodoros/{pid}/complete", h.completePomodoro)
+		r.Post("/{id}/pomodoros/{pid}/interrupt", h.interruptPomodoro)
+		r.Put("/{id}/blocked_by/{blocker}", h.addBlocker)
+		r.Delete("/{id}/blocked_by/{blocker}", h.removeBlocker)
+		r.Post("/{id}/snooze", h.snoozeTodo)
+		r.Post("/{id}/share", h.shareTodo)
+		r.Post("/{id}/slug", h.createSlug)
+		r.Put("/{id}/links/{type}/{other}", h.addLink)
+		r.Delete("/{id}/links/{type}/{other}", h.removeLink)
+	})
+	return rg
+}
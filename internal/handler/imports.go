@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/middleware"
+	"dhruvarora9/personal-todo-golang/internal/mstodo"
+	"dhruvarora9/personal-todo-golang/internal/reminders"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+	"github.com/go-chi/chi"
+)
+
+// Imports serves the importers from other todo apps, mounted at /import.
+type Imports struct {
+	todos  *service.TodoService
+	mstodo *mstodo.Client
+	rnd    *render.Renderer
+	now    func() time.Time
+}
+
+func NewImports(todos *service.TodoService, ms *mstodo.Client, rnd *render.Renderer, now func() time.Time) *Imports {
+	return &Imports{todos: todos, mstodo: ms, rnd: rnd, now: now}
+}
+
+func (i *Imports) Routes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Post("/microsoft", i.microsoft)
+		r.Post("/apple", i.apple)
+	})
+	return rg
+}
+
+// importFailure is an entry of an import's failures.
+type importFailure struct {
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Error  string `json:"error"`
+}
+
+// microsoft imports Microsoft To Do lists: either "lists", Graph
+// todoTaskLists each with its "tasks" (steps in "checklistItems"), or an
+// "access_token" for Graph with Tasks.Read, to pull them directly.
+func (i *Imports) microsoft(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Lists       []mstodo.List `json:"lists"`
+		AccessToken string        `json:"access_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		i.rnd.Problem(w, r, http.StatusBadRequest, "The request body is not valid JSON: %v", err)
+		return
+	}
+	lists := in.Lists
+	if token := strings.TrimSpace(in.AccessToken); token != "" {
+		var err error
+		if lists, err = i.mstodo.Pull(r.Context(), token); err != nil {
+			if errors.Is(err, mstodo.ErrUnauthorized) {
+				i.rnd.Problem(w, r, http.StatusBadRequest, "Microsoft rejected the access token")
+				return
+			}
+			middleware.RecordError(r, err)
+			i.rnd.Problem(w, r, http.StatusBadGateway, "failed to fetch the lists from Microsoft")
+			return
+		}
+	}
+	i.run(w, r, mstodo.Tasks(lists))
+}
+
+// maxAppleExport caps the size of an Apple Reminders export.
+const maxAppleExport = 10 << 20
+
+// apple imports an Apple Reminders export sent as the body: iCalendar
+// (text/calendar), whose calendar name is the list, or CSV (text/csv).
+// Times without a zone are read in the tenant's.
+func (i *Imports) apple(w http.ResponseWriter, r *http.Request) {
+	body := bufio.NewReader(http.MaxBytesReader(w, r.Body, maxAppleExport))
+	head, _ := body.Peek(64)
+	var tasks []service.ImportTask
+	var err error
+	if strings.Contains(strings.ToUpper(string(head)), "BEGIN:VCALENDAR") {
+		tasks, err = reminders.ParseICS(body, tz.FromContext(r.Context()))
+	} else {
+		tasks, err = reminders.ParseCSV(body, tz.FromContext(r.Context()), i.now())
+	}
+	if err != nil {
+		i.rnd.Problem(w, r, http.StatusBadRequest, "The export could not be read: %v", err)
+		return
+	}
+	i.run(w, r, tasks)
+}
+
+// run imports tasks and reports how many todos were created and which
+// failed, with the status a single request for each would have had.
+func (i *Imports) run(w http.ResponseWriter, r *http.Request, tasks []service.ImportTask) {
+	if len(tasks) == 0 {
+		i.rnd.Problem(w, r, http.StatusBadRequest, "There is nothing to import")
+		return
+	}
+	res, err := i.todos.Import(r.Context(), tasks)
+	if err != nil {
+		status, msg := classify(r, err, "failed to import the todos")
+		i.rnd.Problem(w, r, status, msg)
+		return
+	}
+	failures := make([]importFailure, 0, len(res.Failures))
+	for _, f := range res.Failures {
+		status, msg := classify(r, f.Err, "failed to save todo")
+		failures = append(failures, importFailure{Title: f.Title, Status: status, Error: msg})
+	}
+	i.rnd.Data(w, r, http.StatusOK, render.M{"created": res.Created, "failures": failures})
+}
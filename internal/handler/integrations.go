@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"github.com/go-chi/chi"
+)
+
+// integration is the JSON representation of an integration.
+type integration struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Channel   string    `json:"channel"`
+	Address   string    `json:"address"`
+	Events    []string  `json:"events"`
+	List      string    `json:"list"`
+	Template  string    `json:"template"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func toIntegration(in model.Integration) integration {
+	return integration{
+		ID:        in.ID.Hex(),
+		Name:      in.Name,
+		Channel:   in.Channel,
+		Address:   in.Address,
+		Events:    in.Events,
+		List:      in.List,
+		Template:  in.Template,
+		Enabled:   in.Enabled,
+		CreatedAt: in.CreatedAt,
+		UpdatedAt: in.UpdatedAt,
+	}
+}
+
+func (in integration) input() service.IntegrationInput {
+	return service.IntegrationInput{
+		Name:     in.Name,
+		Channel:  in.Channel,
+		Address:  in.Address,
+		Events:   in.Events,
+		List:     in.List,
+		Template: in.Template,
+		Enabled:  in.Enabled,
+	}
+}
+
+// IntegrationRoutes returns the router mounted at /integrations, which
+// manages where todo events are sent.
+func (h *Handler) IntegrationRoutes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Get("/", h.listIntegrations)
+		r.Post("/", h.createIntegration)
+		r.Get("/{id}", h.getIntegration)
+		r.Put("/{id}", h.updateIntegration)
+		r.Delete("/{id}", h.deleteIntegration)
+	})
+	return rg
+}
+
+// failIntegration is fail with a not-found message that names integrations.
+func (h *Handler) failIntegration(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	if errors.Is(err, store.ErrNotFound) {
+		h.rnd.Problem(w, r, http.StatusNotFound, "Integration not found")
+		return
+	}
+	h.fail(w, r, err, msg)
+}
+
+func (h *Handler) listIntegrations(w http.ResponseWriter, r *http.Request) {
+	all, err := h.integrations.List(r.Context())
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch integrations")
+		return
+	}
+	out := make([]integration, 0, len(all))
+	for _, in := range all {
+		out = append(out, toIntegration(in))
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
+
+func (h *Handler) getIntegration(w http.ResponseWriter, r *http.Request) {
+	in, err := h.integrations.Get(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.failIntegration(w, r, err, "failed to fetch the integration")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusOK, toIntegration(*in))
+}
+
+// createIntegration enables new integrations unless the body says
+// "enabled": false.
+func (h *Handler) createIntegration(w http.ResponseWriter, r *http.Request) {
+	in := integration{Enabled: true}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	created, err := h.integrations.Create(r.Context(), in.input())
+	if err != nil {
+		h.fail(w, r, err, "failed to save the integration")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusCreated, toIntegration(*created))
+}
+
+func (h *Handler) updateIntegration(w http.ResponseWriter, r *http.Request) {
+	var in integration
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	updated, err := h.integrations.Update(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")), in.input())
+	if err != nil {
+		h.failIntegration(w, r, err, "failed to save the integration")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusOK, toIntegration(*updated))
+}
+
+func (h *Handler) deleteIntegration(w http.ResponseWriter, r *http.Request) {
+	if err := h.integrations.Delete(r.Context(), strings.TrimSpace(chi.URLParam(r, "id"))); err != nil {
+		h.failIntegration(w, r, err, "failed to delete the integration")
+		return
+	}
+	h.rnd.NoContent(w)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi"
+)
+
+// link is the JSON representation of a model.Link.
+type link struct {
+	Type   string `json:"type"`
+	TodoID string `json:"todo_id"`
+}
+
+// getTodo returns one todo with its links in both directions.
+func (h *Handler) getTodo(w http.ResponseWriter, r *http.Request) {
+	t, err := h.todos.Get(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch the todo")
+		return
+	}
+	links, err := h.todos.Links(r.Context(), t)
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch the todo's links")
+		return
+	}
+	out := toTodo(*t, zone(r))
+	for _, l := range links {
+		out.Links = append(out.Links, link{Type: l.Type, TodoID: l.TodoID.Hex()})
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
+
+// addLink links the todo {id} to the todo {other}: {type} is relates_to,
+// duplicates or follows, read from {id}.
+func (h *Handler) addLink(w http.ResponseWriter, r *http.Request) {
+	err := h.todos.AddLink(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")),
+		chi.URLParam(r, "type"), strings.TrimSpace(chi.URLParam(r, "other")))
+	if err != nil {
+		h.fail(w, r, err, "failed to link the todos")
+		return
+	}
+	h.rnd.NoContent(w)
+}
+
+func (h *Handler) removeLink(w http.ResponseWriter, r *http.Request) {
+	err := h.todos.RemoveLink(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")),
+		chi.URLParam(r, "type"), strings.TrimSpace(chi.URLParam(r, "other")))
+	if err != nil {
+		h.fail(w, r, err, "failed to unlink the todos")
+		return
+	}
+	h.rnd.NoContent(w)
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/pdf"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+	"github.com/go-chi/chi"
+)
+
+// defaultList is the path segment of the default list, as in CalDAV.
+const defaultList = "_default"
+
+// Layout of the printed checklist, in points.
+const (
+	printMargin   = 56
+	printBox      = 11
+	printIndent   = 22
+	printDueWidth = 96
+	printSize     = 12
+	printLeading  = 15
+	printGap      = 9
+)
+
+// ListRoutes returns the router mounted at /lists, which works on a whole
+// list named by its path segment.
+func (h *Handler) ListRoutes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Get("/{list}/export.pdf", h.exportListPDF)
+	})
+	return rg
+}
+
+// exportListPDF prints the list's open todos as a checklist, oldest first;
+// ?completed=true adds the done ones, ticked, at the end. ?paper=letter
+// prints on US Letter instead of A4.
+func (h *Handler) exportListPDF(w http.ResponseWriter, r *http.Request) {
+	list, err := url.PathUnescape(chi.URLParam(r, "list"))
+	if err != nil {
+		h.rnd.Problem(w, r, http.StatusNotFound, "List not found")
+		return
+	}
+	if list == defaultList {
+		list = ""
+	}
+	q := r.URL.Query()
+	size := pdf.A4
+	switch q.Get("paper") {
+	case "", "a4":
+	case "letter":
+		size = pdf.Letter
+	default:
+		h.rnd.Problem(w, r, http.StatusBadRequest, "paper must be a4 or letter")
+		return
+	}
+	withDone, _ := strconv.ParseBool(q.Get("completed"))
+	var todos []model.Todo
+	found := false
+	err = h.todos.Each(r.Context(), store.TodoFilter{List: list, Sort: "created_at"}, 0, 0, func(t *model.Todo) error {
+		// An empty filter list matches every list, so the default one is
+		// picked out here.
+		if t.List != list {
+			return nil
+		}
+		found = true
+		if withDone || !t.Completed {
+			todos = append(todos, *t)
+		}
+		return nil
+	})
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch the list")
+		return
+	}
+	if !found && list != "" {
+		h.rnd.Problem(w, r, http.StatusNotFound, "List not found")
+		return
+	}
+	sort.SliceStable(todos, func(i, j int) bool {
+		return !todos[i].Completed && todos[j].Completed
+	})
+	title := list
+	if title == "" {
+		title = "Todos"
+	}
+	name := strings.Map(func(c rune) rune {
+		if c < ' ' || c == '"' || c == '\\' || c == '/' || c >= 0x7f {
+			return '_'
+		}
+		return c
+	}, title)
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="`+name+`.pdf"`)
+	if _, err := printList(size, title, todos, time.Now().In(tz.FromContext(r.Context()))).WriteTo(w); err != nil {
+		h.log.Printf("export list pdf: %v", err)
+	}
+}
+
+// printList lays todos out as a checklist with a heading, a box before
+// each title and the due date on the right, dates in now's time zone.
+func printList(size pdf.Size, title string, todos []model.Todo, now time.Time) *pdf.Document {
+	doc := pdf.New(size, title)
+	textWidth := size.Width - 2*printMargin - printIndent - printDueWidth
+	var pages []*pdf.Page
+	var page *pdf.Page
+	var y float64
+	newPage := func() {
+		page = doc.AddPage()
+		pages = append(pages, page)
+		y = size.Height - printMargin
+	}
+	newPage()
+	page.Text(printMargin, y-20, pdf.Bold, 20, fit(pdf.Bold, 20, title, size.Width-2*printMargin))
+	open := 0
+	for _, t := range todos {
+		if !t.Completed {
+			open++
+		}
+	}
+	page.Gray(0.4)
+	page.Text(printMargin, y-38, pdf.Regular, 10, strconv.Itoa(open)+" to do · printed "+now.Format("2 January 2006"))
+	y -= 64
+	for _, t := range todos {
+		lines := wrap(pdf.Regular, printSize, t.Title, textWidth)
+		height := float64(len(lines))*printLeading + printGap
+		if y-height < printMargin {
+			newPage()
+		}
+		base := y - printSize
+		page.Gray(0)
+		page.Rect(printMargin, base-1, printBox, printBox)
+		if t.Completed {
+			page.Line(printMargin+2, base+4, printMargin+4.5, base+1, printMargin+9, base+9)
+			page.Gray(0.5)
+		}
+		for i, line := range lines {
+			page.Text(printMargin+printIndent, base-float64(i)*printLeading, pdf.Regular, printSize, line)
+		}
+		if t.DueAt != nil {
+			due := dueLabel(t.DueAt.In(now.Location()))
+			page.Gray(0.4)
+			page.Text(size.Width-printMargin-pdf.Width(pdf.Regular, 10, due), base, pdf.Regular, 10, due)
+		}
+		y -= height
+	}
+	if len(pages) > 1 {
+		for i, p := range pages {
+			n := "Page " + strconv.Itoa(i+1) + " of " + strconv.Itoa(len(pages))
+			p.Gray(0.4)
+			p.Text(size.Width-printMargin-pdf.Width(pdf.Regular, 9, n), printMargin-14, pdf.Regular, 9, n)
+		}
+	}
+	return doc
+}
+
+// dueLabel shows a due date briefly, with the time unless it is midnight.
+func dueLabel(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return "Due " + t.Format("Mon 2 Jan")
+	}
+	return "Due " + t.Format("Mon 2 Jan 15:04")
+}
+
+// wrap breaks s into lines no wider than width, splitting words only when
+// one alone is too wide.
+func wrap(f pdf.Font, size float64, s string, width float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for pdf.Width(f, size, word) > width {
+			if line != "" {
+				lines, line = append(lines, line), ""
+			}
+			head := fit(f, size, word, width)
+			lines, word = append(lines, head), word[len(head):]
+		}
+		switch {
+		case line == "":
+			line = word
+		case pdf.Width(f, size, line+" "+word) <= width:
+			line += " " + word
+		default:
+			lines, line = append(lines, line), word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// fit returns the longest prefix of s no wider than width, cut between
+// characters, but at least its first character.
+func fit(f pdf.Font, size float64, s string, width float64) string {
+	end := 0
+	for i := range s {
+		_, n := utf8.DecodeRuneInString(s[i:])
+		next := i + n
+		if end > 0 && pdf.Width(f, size, s[:next]) > width {
+			break
+		}
+		end = next
+	}
+	return s[:end]
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// geofence is the JSON representation of a model.Geofence.
+type geofence struct {
+	Lat          float64 `json:"lat"`
+	Lng          float64 `json:"lng"`
+	RadiusMeters int     `json:"radius_m"`
+}
+
+// nearbyTodo is a todo whose geofence holds the caller.
+type nearbyTodo struct {
+	Todo           todo `json:"todo"`
+	DistanceMeters int  `json:"distance_m"`
+}
+
+// nearby returns the open todos whose geofence holds ?lat= and ?lng=,
+// nearest first.
+func (h *Handler) nearby(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	lat, err := strconv.ParseFloat(q.Get("lat"), 64)
+	if err != nil {
+		h.rnd.Problem(w, r, http.StatusBadRequest, "lat must be a number")
+		return
+	}
+	lng, err := strconv.ParseFloat(q.Get("lng"), 64)
+	if err != nil {
+		h.rnd.Problem(w, r, http.StatusBadRequest, "lng must be a number")
+		return
+	}
+	found, err := h.todos.Nearby(r.Context(), lat, lng)
+	if err != nil {
+		h.fail(w, r, err, "failed to find nearby todos")
+		return
+	}
+	out := make([]nearbyTodo, 0, len(found))
+	for _, n := range found {
+		out = append(out, nearbyTodo{Todo: toTodo(n.Todo, zone(r)), DistanceMeters: n.DistanceMeters})
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
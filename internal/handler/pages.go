@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+	"github.com/go-chi/chi"
+)
+
+// pageSize is how many todos one HTML page shows.
+const pageSize = 20
+
+// todosPage is the data todos.tpl renders.
+type todosPage struct {
+	Rows        []todoRow
+	Page, Pages int
+	Prev, Next  int
+	// Error, Title and List refill the add form after a failed submit.
+	Error string
+	Title string
+	List  string
+	// Editing is the ID of the todo shown as an edit form.
+	Editing string
+}
+
+// todoRow is the data of the "row" template, which is also sent alone as
+// an HTMX fragment.
+type todoRow struct {
+	Todo    todo
+	Page    int
+	Editing bool
+}
+
+// PageRoutes returns the router mounted at /html: server-rendered pages
+// that work without JavaScript through plain form POSTs. With htmx loaded,
+// the same endpoints answer requests carrying HX-Request with just the
+// changed row, so the page updates in place.
+func (h *Handler) PageRoutes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Get("/", h.todosPage)
+		r.Get("/todos/{id}", h.rowFragment)
+		r.Get("/todos/{id}/edit", h.editForm)
+		r.With(sameOrigin).Post("/todos", h.addFromForm)
+		r.With(sameOrigin).Post("/todos/{id}", h.editFromForm)
+		r.With(sameOrigin).Post("/todos/{id}/toggle", h.toggleFromForm)
+		r.With(sameOrigin).Post("/todos/{id}/delete", h.deleteFromForm)
+	})
+	return rg
+}
+
+// sameOrigin refuses form posts from other sites, which browsers would
+// otherwise send along with the user's access to this one.
+func sameOrigin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			origin = r.Header.Get("Referer")
+		}
+		if origin != "" {
+			if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+				http.Error(w, "Cross-site form submissions are not allowed", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isHTMX(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true"
+}
+
+func pageParam(r *http.Request) int {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		return 1
+	}
+	return page
+}
+
+func (h *Handler) renderTodos(w http.ResponseWriter, r *http.Request, status int, p todosPage) {
+	todos, err := h.todos.List(r.Context())
+	if err != nil {
+		h.pageError(w, r, err)
+		return
+	}
+	p.Pages = (len(todos) + pageSize - 1) / pageSize
+	if p.Pages == 0 {
+		p.Pages = 1
+	}
+	if p.Page > p.Pages {
+		p.Page = p.Pages
+	}
+	p.Prev, p.Next = p.Page-1, p.Page+1
+	// Newest first, like the order todos are usually looked at in.
+	start := len(todos) - p.Page*pageSize
+	end := start + pageSize
+	if start < 0 {
+		start = 0
+	}
+	page := todos[start:end]
+	p.Rows = make([]todoRow, 0, len(page))
+	for i := len(page) - 1; i >= 0; i-- {
+		t := toTodo(page[i], tz.FromContext(r.Context()))
+		p.Rows = append(p.Rows, todoRow{Todo: t, Page: p.Page, Editing: t.ID == p.Editing})
+	}
+	if err := h.rnd.HTML(w, r, status, "todos.tpl", p); err != nil {
+		h.pageError(w, r, err)
+	}
+}
+
+// renderRow answers an HTMX request with the todo's row.
+func (h *Handler) renderRow(w http.ResponseWriter, r *http.Request, id string, editing bool) {
+	t, err := h.todos.Get(r.Context(), id)
+	if err != nil {
+		h.pageError(w, r, err)
+		return
+	}
+	if err := h.rnd.HTML(w, r, http.StatusOK, "row", todoRow{Todo: toTodo(*t, tz.FromContext(r.Context())), Page: pageParam(r), Editing: editing}); err != nil {
+		h.pageError(w, r, err)
+	}
+}
+
+// pageError is fail for HTML pages: a plain text error instead of problem
+// JSON.
+func (h *Handler) pageError(w http.ResponseWriter, r *http.Request, err error) {
+	status, msg := classify(r, err, "Something went wrong")
+	http.Error(w, msg, status)
+}
+
+// formError shows a validation message above the list. htmx only swaps
+// successful responses, so for it the message is sent with a 200 and
+// retargeted at the error slot.
+func (h *Handler) formError(w http.ResponseWriter, r *http.Request, msg string, p todosPage) {
+	if isHTMX(r) {
+		w.Header().Set("HX-Retarget", "#error-slot")
+		w.Header().Set("HX-Reswap", "innerHTML")
+		h.rnd.HTML(w, r, http.StatusOK, "error", msg)
+		return
+	}
+	p.Error = msg
+	h.renderTodos(w, r, http.StatusBadRequest, p)
+}
+
+func (h *Handler) todosPage(w http.ResponseWriter, r *http.Request) {
+	h.renderTodos(w, r, http.StatusOK, todosPage{Page: pageParam(r)})
+}
+
+func (h *Handler) rowFragment(w http.ResponseWriter, r *http.Request) {
+	h.renderRow(w, r, strings.TrimSpace(chi.URLParam(r, "id")), false)
+}
+
+func (h *Handler) editForm(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	if isHTMX(r) {
+		h.renderRow(w, r, id, true)
+		return
+	}
+	h.renderTodos(w, r, http.StatusOK, todosPage{Page: pageParam(r), Editing: id})
+}
+
+// backToList redirects to the page the form was on, or for htmx sends the
+// todo's updated row.
+func (h *Handler) backToList(w http.ResponseWriter, r *http.Request, id string) {
+	if isHTMX(r) {
+		h.renderRow(w, r, id, false)
+		return
+	}
+	http.Redirect(w, r, "/html?page="+strconv.Itoa(pageParam(r)), http.StatusSeeOther)
+}
+
+func (h *Handler) addFromForm(w http.ResponseWriter, r *http.Request) {
+	in := service.TodoInput{Title: r.PostFormValue("title"), List: r.PostFormValue("list")}
+	t, err := h.todos.Create(r.Context(), in)
+	var ve *service.ValidationError
+	if errors.As(err, &ve) {
+		h.formError(w, r, ve.In(i18n.FromContext(r.Context())), todosPage{Page: 1, Title: in.Title, List: in.List})
+		return
+	}
+	if err != nil {
+		h.pageError(w, r, err)
+		return
+	}
+	if isHTMX(r) {
+		h.rnd.HTML(w, r, http.StatusOK, "row", todoRow{Todo: toTodo(*t, tz.FromContext(r.Context())), Page: 1})
+		return
+	}
+	http.Redirect(w, r, "/html", http.StatusSeeOther)
+}
+
+func (h *Handler) editFromForm(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	t, err := h.todos.Get(r.Context(), id)
+	if err == nil {
+		in := service.TodoInput{
+			Title:           r.PostFormValue("title"),
+			List:            r.PostFormValue("list"),
+			Completed:       t.Completed,
+			DueAt:           t.DueAt,
+			Tags:            t.Tags,
+			Notes:           t.Notes,
+			EstimateMinutes: t.EstimateMinutes,
+			Fields:          t.Fields,
+			Geofence:        t.Geofence,
+		}
+		_, err = h.todos.Update(r.Context(), id, in)
+	}
+	var ve *service.ValidationError
+	if errors.As(err, &ve) {
+		h.formError(w, r, ve.In(i18n.FromContext(r.Context())), todosPage{Page: pageParam(r), Editing: id})
+		return
+	}
+	if err != nil {
+		h.pageError(w, r, err)
+		return
+	}
+	h.backToList(w, r, id)
+}
+
+func (h *Handler) toggleFromForm(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	t, err := h.todos.Get(r.Context(), id)
+	if err == nil {
+		_, err = h.todos.SetCompleted(r.Context(), id, !t.Completed)
+	}
+	if err != nil {
+		h.pageError(w, r, err)
+		return
+	}
+	h.backToList(w, r, id)
+}
+
+func (h *Handler) deleteFromForm(w http.ResponseWriter, r *http.Request) {
+	if err := h.todos.Delete(r.Context(), strings.TrimSpace(chi.URLParam(r, "id"))); err != nil {
+		h.pageError(w, r, err)
+		return
+	}
+	if isHTMX(r) {
+		// An empty answer swapped over the row removes it.
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, "/html?page="+strconv.Itoa(pageParam(r)), http.StatusSeeOther)
+}
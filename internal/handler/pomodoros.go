@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"github.com/go-chi/chi"
+)
+
+// pomodoro is the JSON representation of a pomodoro session.
+type pomodoro struct {
+	ID           string     `json:"id"`
+	TodoID       string     `json:"todo_id"`
+	Minutes      int        `json:"minutes"`
+	BreakMinutes int        `json:"break_minutes"`
+	State        string     `json:"state"`
+	Start        time.Time  `json:"start"`
+	End          *time.Time `json:"end"`
+}
+
+func toPomodoro(p model.Pomodoro) pomodoro {
+	return pomodoro{
+		ID:           p.ID.Hex(),
+		TodoID:       p.TodoID.Hex(),
+		Minutes:      p.Minutes,
+		BreakMinutes: p.BreakMinutes,
+		State:        p.State,
+		Start:        p.Start,
+		End:          p.End,
+	}
+}
+
+// startPomodoro starts a session on a todo. The optional body sets
+// "minutes" and "break_minutes", 25 and 5 by default.
+func (h *Handler) startPomodoro(w http.ResponseWriter, r *http.Request) {
+	var in pomodoro
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil && err != io.EOF {
+		h.badBody(w, r, err)
+		return
+	}
+	p, err := h.todos.StartPomodoro(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")), in.Minutes, in.BreakMinutes)
+	if err != nil {
+		h.fail(w, r, err, "failed to start the pomodoro")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusCreated, toPomodoro(*p))
+}
+
+// completePomodoro ends a session as done, which starts its break.
+func (h *Handler) completePomodoro(w http.ResponseWriter, r *http.Request) {
+	p, err := h.todos.CompletePomodoro(r.Context(),
+		strings.TrimSpace(chi.URLParam(r, "id")), strings.TrimSpace(chi.URLParam(r, "pid")))
+	if err != nil {
+		h.fail(w, r, err, "failed to complete the pomodoro")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusOK, toPomodoro(*p))
+}
+
+func (h *Handler) interruptPomodoro(w http.ResponseWriter, r *http.Request) {
+	p, err := h.todos.InterruptPomodoro(r.Context(),
+		strings.TrimSpace(chi.URLParam(r, "id")), strings.TrimSpace(chi.URLParam(r, "pid")))
+	if err != nil {
+		h.fail(w, r, err, "failed to interrupt the pomodoro")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusOK, toPomodoro(*p))
+}
+
+// pomodoroDay is one row of the pomodoro stats.
+type pomodoroDay struct {
+	Day          string `json:"day"`
+	Completed    int    `json:"completed"`
+	Interrupted  int    `json:"interrupted"`
+	FocusMinutes int    `json:"focus_minutes"`
+}
+
+// pomodoroStats counts sessions per day over the period given as for
+// period.
+func (h *Handler) pomodoroStats(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := h.period(w, r)
+	if !ok {
+		return
+	}
+	days, err := h.todos.PomodoroStats(r.Context(), from, to)
+	if err != nil {
+		h.fail(w, r, err, "failed to report pomodoros")
+		return
+	}
+	out := make([]pomodoroDay, 0, len(days))
+	for _, d := range days {
+		out = append(out, pomodoroDay{
+			Day:          d.Day,
+			Completed:    d.Completed,
+			Interrupted:  d.Interrupted,
+			FocusMinutes: int(d.Focus / time.Minute),
+		})
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
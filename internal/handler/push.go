@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"github.com/go-chi/chi"
+)
+
+// PushRoutes returns the router mounted at /push: the VAPID public key
+// browsers subscribe with, and subscription management.
+func (h *Handler) PushRoutes(publicKey string) http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Get("/key", func(w http.ResponseWriter, r *http.Request) {
+			h.rnd.Data(w, r, http.StatusOK, map[string]string{"public_key": publicKey})
+		})
+		r.Post("/subscriptions", h.subscribePush)
+		r.Delete("/subscriptions", h.unsubscribePush)
+	})
+	return rg
+}
+
+func (h *Handler) subscribePush(w http.ResponseWriter, r *http.Request) {
+	var sub model.PushSubscription
+	if err := json.NewDecoder(r.Body).Decode(&sub); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	if err := h.push.Subscribe(r.Context(), &sub); err != nil {
+		h.fail(w, r, err, "failed to save the subscription")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusCreated, sub)
+}
+
+// unsubscribePush takes the endpoint as a query parameter, since DELETE
+// bodies are often dropped by clients and proxies.
+func (h *Handler) unsubscribePush(w http.ResponseWriter, r *http.Request) {
+	if err := h.push.Unsubscribe(r.Context(), r.URL.Query().Get("endpoint")); err != nil {
+		h.fail(w, r, err, "failed to delete the subscription")
+		return
+	}
+	h.rnd.NoContent(w)
+}
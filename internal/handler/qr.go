@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/qr"
+	"github.com/go-chi/chi"
+)
+
+// qrScale is the width of a QR code module in pixels.
+const qrScale = 8
+
+// ShareQR serves a PNG QR code leading to the share's widget page, for
+// opening a share on a phone by scanning it.
+func (h *Handler) ShareQR(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	shared, err := h.shares.Open(r.Context(), token)
+	if err != nil {
+		h.failShare(w, r, err, "failed to open the share")
+		return
+	}
+	code, err := qr.Encode(origin(r) + "/share/" + url.PathEscape(token) + "/widget")
+	if err != nil {
+		h.fail(w, r, err, "failed to draw the QR code")
+		return
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, code.Image(qrScale)); err != nil {
+		h.fail(w, r, err, "failed to draw the QR code")
+		return
+	}
+	maxAge := widgetMaxAge
+	if exp := shared.Share.ExpiresAt; exp != nil && exp.Sub(time.Now()) < maxAge {
+		maxAge = exp.Sub(time.Now())
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge/time.Second)))
+	w.Write(buf.Bytes())
+}
+
+// origin is the scheme and host r was sent to, as the client saw them.
+func origin(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
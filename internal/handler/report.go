@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+)
+
+// defaultReportWeeks is how many weeks a report covers unless asked.
+const defaultReportWeeks = 4
+
+// reportWeek is one week of the weekly report.
+type reportWeek struct {
+	Start          string   `json:"start"`
+	Created        int      `json:"created"`
+	Completed      int      `json:"completed"`
+	Due            int      `json:"due"`
+	CarriedOver    int      `json:"carried_over"`
+	CompletionRate *float64 `json:"completion_rate"`
+}
+
+// tagCount is one of the busiest tags of a report.
+type tagCount struct {
+	Tag       string `json:"tag"`
+	Completed int    `json:"completed"`
+}
+
+// WeeklyReport summarizes the last ?weeks= weeks (4 by default), Monday
+// to Sunday, up to the one holding ?end= (YYYY-MM-DD, today by default).
+func (h *Handler) WeeklyReport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	weeks := defaultReportWeeks
+	if v := q.Get("weeks"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			h.rnd.Problem(w, r, http.StatusBadRequest, "weeks must be a number")
+			return
+		}
+		weeks = n
+	}
+	loc := tz.FromContext(r.Context())
+	end := time.Now().In(loc)
+	if v := q.Get("end"); v != "" {
+		var err error
+		if end, err = time.ParseInLocation("2006-01-02", v, loc); err != nil {
+			h.rnd.Problem(w, r, http.StatusBadRequest, "end must be a date, YYYY-MM-DD")
+			return
+		}
+	}
+	rep, err := h.todos.WeeklyReport(r.Context(), end, weeks)
+	if err != nil {
+		h.fail(w, r, err, "failed to build the report")
+		return
+	}
+	out := make([]reportWeek, 0, len(rep.Weeks))
+	for _, wk := range rep.Weeks {
+		out = append(out, reportWeek{
+			Start:          wk.Start.Format("2006-01-02"),
+			Created:        wk.Created,
+			Completed:      wk.Completed,
+			Due:            wk.Due,
+			CarriedOver:    wk.CarriedOver,
+			CompletionRate: wk.CompletionRate,
+		})
+	}
+	tags := make([]tagCount, 0, len(rep.BusiestTags))
+	for _, t := range rep.BusiestTags {
+		tags = append(tags, tagCount{Tag: t.Tag, Completed: t.Completed})
+	}
+	h.rnd.Data(w, r, http.StatusOK, render.M{"weeks": out, "busiest_tags": tags})
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+)
+
+// scheduleSlot is a todo on the timeline.
+type scheduleSlot struct {
+	Todo     todo      `json:"todo"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Overlaps bool      `json:"overlaps"`
+}
+
+// scheduleDay is the slots of the todos due on one day.
+type scheduleDay struct {
+	Day   string         `json:"day"`
+	Slots []scheduleSlot `json:"slots"`
+}
+
+// schedule returns the open todos due in the period given as for period
+// as a timeline for calendar views, each slot ending at the todo's due
+// date and starting its estimate earlier.
+func (h *Handler) schedule(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := h.period(w, r)
+	if !ok {
+		return
+	}
+	days, err := h.todos.Schedule(r.Context(), from, to)
+	if err != nil {
+		h.fail(w, r, err, "failed to lay out the schedule")
+		return
+	}
+	loc := zone(r)
+	out := make([]scheduleDay, 0, len(days))
+	for _, d := range days {
+		day := scheduleDay{Day: d.Day, Slots: make([]scheduleSlot, 0, len(d.Slots))}
+		for _, sl := range d.Slots {
+			if loc != nil {
+				sl.Start, sl.End = sl.Start.In(loc), sl.End.In(loc)
+			}
+			day.Slots = append(day.Slots, scheduleSlot{
+				Todo:     toTodo(sl.Todo, loc),
+				Start:    sl.Start,
+				End:      sl.End,
+				Overlaps: sl.Overlaps,
+			})
+		}
+		out = append(out, day)
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+	"github.com/go-chi/chi"
+)
+
+// settings is the JSON representation of a tenant's settings.
+type settings struct {
+	// Locale is empty to follow each request's Accept-Language.
+	Locale string `json:"locale"`
+	// Locales, read-only, lists the locales there are messages in.
+	Locales []string `json:"locales,omitempty"`
+	// TimeZone is an IANA zone name; empty for the server's zone.
+	TimeZone  string    `json:"time_zone"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func toSettings(st model.Settings) settings {
+	return settings{Locale: st.Locale, Locales: i18n.Locales(), TimeZone: st.TimeZone, UpdatedAt: st.UpdatedAt}
+}
+
+// Settings serves a tenant's preferences and applies them to requests.
+type Settings struct {
+	settings *service.SettingsService
+	rnd      *render.Renderer
+	log      *log.Logger
+}
+
+func NewSettings(settings *service.SettingsService, rnd *render.Renderer, logger *log.Logger) *Settings {
+	return &Settings{settings: settings, rnd: rnd, log: logger}
+}
+
+func (s *Settings) Routes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Get("/", s.get)
+	rg.Put("/", s.update)
+	return rg
+}
+
+// Localize puts the locale and time zone the tenant chose in the request
+// context, where error messages, pages and dates find them. Without a
+// locale, the one middleware.Language took from Accept-Language stays;
+// without a zone, the server's is used. The same goes when the settings
+// can't be read.
+func (s *Settings) Localize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		locale, err := s.settings.Locale(ctx)
+		if err != nil {
+			s.log.Printf("settings: %v", err)
+		}
+		if locale != "" {
+			ctx = i18n.NewContext(ctx, locale)
+		}
+		loc, err := s.settings.Location(ctx)
+		if err != nil {
+			s.log.Printf("settings: %v", err)
+		}
+		next.ServeHTTP(w, r.WithContext(tz.NewContext(ctx, loc)))
+	})
+}
+
+func (s *Settings) get(w http.ResponseWriter, r *http.Request) {
+	st, err := s.settings.Get(r.Context())
+	if err != nil {
+		status, msg := classify(r, err, "failed to fetch the settings")
+		s.rnd.Problem(w, r, status, msg)
+		return
+	}
+	s.rnd.Data(w, r, http.StatusOK, toSettings(*st))
+}
+
+func (s *Settings) update(w http.ResponseWriter, r *http.Request) {
+	var in settings
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		s.rnd.Problem(w, r, http.StatusBadRequest, "The request body is not valid JSON: %v", err)
+		return
+	}
+	st, err := s.settings.Update(r.Context(), service.SettingsInput{Locale: in.Locale, TimeZone: in.TimeZone})
+	if err != nil {
+		status, msg := classify(r, err, "failed to save the settings")
+		s.rnd.Problem(w, r, status, msg)
+		return
+	}
+	s.rnd.Data(w, r, http.StatusOK, toSettings(*st))
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"github.com/go-chi/chi"
+)
+
+// share is the JSON representation of a share link.
+type share struct {
+	ID     string `json:"id"`
+	Token  string `json:"token"`
+	TodoID string `json:"todo_id,omitempty"`
+	List   string `json:"list,omitempty"`
+	// URL is the path of the read-only view.
+	URL       string     `json:"url"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func toShare(sh model.Share) share {
+	out := share{
+		ID:        sh.ID.Hex(),
+		Token:     sh.Token,
+		List:      sh.List,
+		URL:       "/share/" + sh.Token,
+		ExpiresAt: sh.ExpiresAt,
+		CreatedAt: sh.CreatedAt,
+	}
+	if !sh.TodoID.IsZero() {
+		out.TodoID = sh.TodoID.Hex()
+	}
+	return out
+}
+
+// sharedTodo is what a share shows of a todo: no ids, links to other
+// systems or custom fields.
+type sharedTodo struct {
+	Title     string     `json:"title"`
+	Completed bool       `json:"completed"`
+	Status    string     `json:"status"`
+	DueAt     *time.Time `json:"due_at"`
+	Tags      []string   `json:"tags,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func toSharedTodo(t model.Todo) sharedTodo {
+	return sharedTodo{
+		Title:     t.Title,
+		Completed: t.Completed,
+		Status:    t.CurrentStatus(),
+		DueAt:     t.DueAt,
+		Tags:      t.Tags,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// ShareRoutes returns the router mounted at /shares, which manages share
+// links. Links to single todos are also made by POST /todo/{id}/share.
+func (h *Handler) ShareRoutes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Get("/", h.listShares)
+		r.Post("/", h.createShare)
+		r.Delete("/{id}", h.revokeShare)
+	})
+	return rg
+}
+
+// failShare is fail with a not-found message that names shares.
+func (h *Handler) failShare(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	if errors.Is(err, store.ErrNotFound) {
+		h.rnd.Problem(w, r, http.StatusNotFound, "Share not found")
+		return
+	}
+	h.fail(w, r, err, msg)
+}
+
+func (h *Handler) listShares(w http.ResponseWriter, r *http.Request) {
+	all, err := h.shares.List(r.Context())
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch shares")
+		return
+	}
+	out := make([]share, 0, len(all))
+	for _, sh := range all {
+		out = append(out, toShare(sh))
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
+
+// createShare shares the todo "todo_id" or else the list "list", until
+// the optional "expires_at".
+func (h *Handler) createShare(w http.ResponseWriter, r *http.Request) {
+	var in share
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	var sh *model.Share
+	var err error
+	if in.TodoID != "" {
+		sh, err = h.shares.ShareTodo(r.Context(), strings.TrimSpace(in.TodoID), in.ExpiresAt)
+	} else {
+		sh, err = h.shares.ShareList(r.Context(), in.List, in.ExpiresAt)
+	}
+	if err != nil {
+		h.fail(w, r, err, "failed to share")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusCreated, toShare(*sh))
+}
+
+// shareTodo shares one todo. The optional body sets "expires_at".
+func (h *Handler) shareTodo(w http.ResponseWriter, r *http.Request) {
+	var in share
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil && err != io.EOF {
+		h.badBody(w, r, err)
+		return
+	}
+	sh, err := h.shares.ShareTodo(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")), in.ExpiresAt)
+	if err != nil {
+		h.fail(w, r, err, "failed to share the todo")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusCreated, toShare(*sh))
+}
+
+func (h *Handler) revokeShare(w http.ResponseWriter, r *http.Request) {
+	if err := h.shares.Revoke(r.Context(), strings.TrimSpace(chi.URLParam(r, "id"))); err != nil {
+		h.failShare(w, r, err, "failed to revoke the share")
+		return
+	}
+	h.rnd.NoContent(w)
+}
+
+// OpenShare is the public read-only view of a share: the todo, or the
+// list and its todos. Unknown, revoked and expired tokens all answer 404.
+func (h *Handler) OpenShare(w http.ResponseWriter, r *http.Request) {
+	shared, err := h.shares.Open(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		h.failShare(w, r, err, "failed to open the share")
+		return
+	}
+	todos := make([]sharedTodo, 0, len(shared.Todos))
+	for _, t := range shared.Todos {
+		todos = append(todos, toSharedTodo(t))
+	}
+	if shared.Share.List == "" {
+		h.rnd.Data(w, r, http.StatusOK, todos[0])
+		return
+	}
+	h.rnd.Data(w, r, http.StatusOK, render.M{"list": shared.Share.List, "todos": todos})
+}
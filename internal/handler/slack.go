@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/middleware"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"github.com/go-chi/chi"
+)
+
+const slackUsage = "Usage: `/todo add <title>` or `/todo list`"
+
+// Slack answers the /todo slash command of a Slack app.
+type Slack struct {
+	todos  *service.TodoService
+	rnd    *render.Renderer
+	secret string
+	now    func() time.Time
+}
+
+func NewSlack(todos *service.TodoService, rnd *render.Renderer, signingSecret string, now func() time.Time) *Slack {
+	return &Slack{todos: todos, rnd: rnd, secret: signingSecret, now: now}
+}
+
+func (s *Slack) Routes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Use(middleware.VerifySlack(s.secret, s.now, s.rnd))
+	rg.Post("/command", s.command)
+	return rg
+}
+
+// command handles the slash command payload. Slack shows whatever text we
+// answer with, so errors are replies rather than error statuses.
+func (s *Slack) command(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		s.rnd.Problem(w, r, http.StatusBadRequest, "The request body is not a valid form")
+		return
+	}
+	verb, rest, _ := strings.Cut(strings.TrimSpace(r.PostForm.Get("text")), " ")
+	switch verb {
+	case "add":
+		t, err := s.todos.Create(r.Context(), service.TodoInput{Title: rest})
+		var ve *service.ValidationError
+		if errors.As(err, &ve) {
+			s.reply(w, ve.Message)
+			return
+		}
+		if err != nil {
+			middleware.RecordError(r, err)
+			s.reply(w, "Sorry, the todo could not be added.")
+			return
+		}
+		s.reply(w, fmt.Sprintf("Added *%s*", t.Title))
+	case "list":
+		todos, err := s.todos.List(r.Context())
+		if err != nil {
+			middleware.RecordError(r, err)
+			s.reply(w, "Sorry, the todos could not be loaded.")
+			return
+		}
+		var b strings.Builder
+		for _, t := range todos {
+			if !t.Completed {
+				fmt.Fprintf(&b, "• %s\n", t.Title)
+			}
+		}
+		if b.Len() == 0 {
+			b.WriteString("Nothing to do.")
+		}
+		s.reply(w, b.String())
+	default:
+		s.reply(w, slackUsage)
+	}
+}
+
+// reply answers only to the user who ran the command.
+func (s *Slack) reply(w http.ResponseWriter, text string) {
+	s.rnd.JSON(w, http.StatusOK, render.M{"response_type": "ephemeral", "text": text})
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi"
+)
+
+// slug is the JSON representation of a todo's short link.
+type slug struct {
+	Slug string `json:"slug"`
+	// URL is the path that leads to the todo.
+	URL string `json:"url"`
+}
+
+// createSlug gives the todo {id} a short slug, or returns the one it has.
+func (h *Handler) createSlug(w http.ResponseWriter, r *http.Request) {
+	s, err := h.todos.Slug(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.fail(w, r, err, "failed to make the short link")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusOK, slug{Slug: s, URL: "/t/" + s})
+}
+
+// OpenSlug redirects a short link to the todo it names.
+func (h *Handler) OpenSlug(w http.ResponseWriter, r *http.Request) {
+	t, err := h.todos.BySlug(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		h.fail(w, r, err, "failed to open the short link")
+		return
+	}
+	http.Redirect(w, r, "/todo/"+t.ID.Hex(), http.StatusFound)
+}
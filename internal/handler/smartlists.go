@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"github.com/go-chi/chi"
+)
+
+// smartList is the JSON representation of a smart list.
+type smartList struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Tags          []string  `json:"tags,omitempty"`
+	AnyTags       []string  `json:"tags_any,omitempty"`
+	NotTags       []string  `json:"tags_not,omitempty"`
+	List          string    `json:"list,omitempty"`
+	Completed     *bool     `json:"completed,omitempty"`
+	DueWithinDays *int      `json:"due_within_days,omitempty"`
+	Sort          string    `json:"sort,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func toSmartList(l model.SmartList) smartList {
+	return smartList{
+		ID:            l.ID.Hex(),
+		Name:          l.Name,
+		Tags:          l.Tags,
+		AnyTags:       l.AnyTags,
+		NotTags:       l.NotTags,
+		List:          l.List,
+		Completed:     l.Completed,
+		DueWithinDays: l.DueWithinDays,
+		Sort:          l.Sort,
+		CreatedAt:     l.CreatedAt,
+		UpdatedAt:     l.UpdatedAt,
+	}
+}
+
+func (l smartList) input() service.SmartListInput {
+	return service.SmartListInput{
+		Name:          l.Name,
+		Tags:          l.Tags,
+		AnyTags:       l.AnyTags,
+		NotTags:       l.NotTags,
+		List:          l.List,
+		Completed:     l.Completed,
+		DueWithinDays: l.DueWithinDays,
+		Sort:          l.Sort,
+	}
+}
+
+// SmartListRoutes returns the router mounted at /smartlists, which manages
+// saved filters and lists the todos they select.
+func (h *Handler) SmartListRoutes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Get("/", h.listSmartLists)
+		r.Post("/", h.createSmartList)
+		r.Get("/{id}", h.getSmartList)
+		r.Put("/{id}", h.updateSmartList)
+		r.Delete("/{id}", h.deleteSmartList)
+		r.Get("/{id}/todos", h.smartListTodos)
+	})
+	return rg
+}
+
+// failSmartList is fail with a not-found message that names smart lists.
+func (h *Handler) failSmartList(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	if errors.Is(err, store.ErrNotFound) {
+		h.rnd.Problem(w, r, http.StatusNotFound, "Smart list not found")
+		return
+	}
+	h.fail(w, r, err, msg)
+}
+
+func (h *Handler) listSmartLists(w http.ResponseWriter, r *http.Request) {
+	all, err := h.smartLists.List(r.Context())
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch smart lists")
+		return
+	}
+	out := make([]smartList, 0, len(all))
+	for _, l := range all {
+		out = append(out, toSmartList(l))
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
+
+func (h *Handler) getSmartList(w http.ResponseWriter, r *http.Request) {
+	l, err := h.smartLists.Get(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.failSmartList(w, r, err, "failed to fetch the smart list")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusOK, toSmartList(*l))
+}
+
+func (h *Handler) createSmartList(w http.ResponseWriter, r *http.Request) {
+	var in smartList
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	created, err := h.smartLists.Create(r.Context(), in.input())
+	if err != nil {
+		h.fail(w, r, err, "failed to save the smart list")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusCreated, toSmartList(*created))
+}
+
+func (h *Handler) updateSmartList(w http.ResponseWriter, r *http.Request) {
+	var in smartList
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	updated, err := h.smartLists.Update(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")), in.input())
+	if err != nil {
+		h.failSmartList(w, r, err, "failed to save the smart list")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusOK, toSmartList(*updated))
+}
+
+func (h *Handler) deleteSmartList(w http.ResponseWriter, r *http.Request) {
+	if err := h.smartLists.Delete(r.Context(), strings.TrimSpace(chi.URLParam(r, "id"))); err != nil {
+		h.failSmartList(w, r, err, "failed to delete the smart list")
+		return
+	}
+	h.rnd.NoContent(w)
+}
+
+// smartListTodos lists the todos the smart list selects, in its order,
+// taking ?offset= and ?limit= like GET /todo.
+func (h *Handler) smartListTodos(w http.ResponseWriter, r *http.Request) {
+	f, err := h.smartLists.Filter(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.failSmartList(w, r, err, "failed to fetch the smart list")
+		return
+	}
+	h.listTodos(w, r, f)
+}
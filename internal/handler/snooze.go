@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+// snooze is the JSON representation of a model.Snooze.
+type snooze struct {
+	At    time.Time  `json:"at"`
+	From  *time.Time `json:"from"`
+	Until time.Time  `json:"until"`
+}
+
+// snoozeTodo puts a todo off. The body gives either "for", a duration
+// such as "2h" or "30m" added to the due date (or to now, if the todo is
+// overdue or has none), or "until", the new due date.
+func (h *Handler) snoozeTodo(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		For   string     `json:"for"`
+		Until *time.Time `json:"until"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	var d time.Duration
+	if in.For != "" {
+		var err error
+		if d, err = time.ParseDuration(in.For); err != nil {
+			h.rnd.Problem(w, r, http.StatusBadRequest, `for must be a duration, such as "2h" or "30m"`)
+			return
+		}
+	}
+	t, err := h.todos.Snooze(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")), d, in.Until)
+	if err != nil {
+		h.fail(w, r, err, "failed to snooze the todo")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusOK, toTodo(*t, zone(r)))
+}
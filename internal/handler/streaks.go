@@ -0,0 +1,33 @@
+package handler
+
+import "net/http"
+
+// badge is a milestone reached.
+type badge struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+// streaks is the JSON representation of service.Streaks.
+type streaks struct {
+	Current   int     `json:"current"`
+	Longest   int     `json:"longest"`
+	Completed int     `json:"completed"`
+	Today     int     `json:"today"`
+	Badges    []badge `json:"badges"`
+}
+
+// Streaks returns the days-in-a-row completion streaks and the badges
+// earned.
+func (h *Handler) Streaks(w http.ResponseWriter, r *http.Request) {
+	st, err := h.streaks.Streaks(r.Context())
+	if err != nil {
+		h.fail(w, r, err, "failed to work out the streaks")
+		return
+	}
+	out := streaks{Current: st.Current, Longest: st.Longest, Completed: st.Completed, Today: st.Today, Badges: make([]badge, 0, len(st.Badges))}
+	for _, b := range st.Badges {
+		out.Badges = append(out.Badges, badge(b))
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"github.com/go-chi/chi"
+)
+
+// SyncRoutes returns the router mounted at /sync, the delta sync for
+// offline clients.
+func (h *Handler) SyncRoutes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Group(func(r chi.Router) {
+		r.Get("/", h.pullChanges)
+		r.Post("/", h.pushChanges)
+	})
+	return rg
+}
+
+// syncChanges is the JSON representation of a page of changes.
+type syncChanges struct {
+	Todos   []todo   `json:"todos"`
+	Deleted []string `json:"deleted"`
+	Cursor  string   `json:"cursor"`
+	More    bool     `json:"more"`
+}
+
+// pullChanges returns the todos created or updated and the ids of those
+// deleted since ?since=, the cursor of the previous pull, or every todo
+// without one. While more is true the client should pull again with the
+// new cursor. A cursor too old to catch up from answers 410.
+func (h *Handler) pullChanges(w http.ResponseWriter, r *http.Request) {
+	ch, err := h.sync.Pull(r.Context(), strings.TrimSpace(r.URL.Query().Get("since")))
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch the changes")
+		return
+	}
+	out := syncChanges{Todos: make([]todo, 0, len(ch.Todos)), Deleted: make([]string, 0, len(ch.Deleted)), Cursor: ch.Cursor, More: ch.More}
+	for _, t := range ch.Todos {
+		out.Todos = append(out.Todos, toTodo(t, zone(r)))
+	}
+	for _, id := range ch.Deleted {
+		out.Deleted = append(out.Deleted, id.Hex())
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
+
+// syncChange is one entry of a push: a todo with "op" "upsert", the
+// default, or "delete". A todo created offline carries a "client_id"
+// instead of an id.
+type syncChange struct {
+	Op       string `json:"op"`
+	ClientID string `json:"client_id"`
+	edit
+}
+
+// pushChanges applies the body's "changes" in order. It answers 200 with a
+// result per change, as for a bulk request; a deletion has no todo. Updates
+// made offline to todos changed since are settled by ?on_conflict=.
+func (h *Handler) pushChanges(w http.ResponseWriter, r *http.Request) {
+	strategy, ok := h.onConflict(w, r)
+	if !ok {
+		return
+	}
+	var in struct {
+		Changes []syncChange `json:"changes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	changes := make([]service.SyncChange, len(in.Changes))
+	for i, c := range in.Changes {
+		switch c.Op {
+		case "", "upsert", "delete":
+		default:
+			h.rnd.Problem(w, r, http.StatusBadRequest, "op must be upsert or delete")
+			return
+		}
+		changes[i] = service.SyncChange{BulkItem: c.item(strategy), ClientID: c.ClientID, Delete: c.Op == "delete"}
+	}
+	results, err := h.sync.Push(r.Context(), changes)
+	if err != nil {
+		h.fail(w, r, err, "failed to apply the changes")
+		return
+	}
+	out := make([]bulkResult, len(results))
+	for i, res := range results {
+		out[i].Index = i
+		switch {
+		case res.Err != nil:
+			out[i].Status, out[i].Error = classify(r, res.Err, "failed to save todo")
+			out[i].Conflict = toConflict(res.Err, in.Changes[i].todo, zone(r))
+			continue
+		case res.Created:
+			out[i].Status = http.StatusCreated
+		case res.Todo == nil:
+			out[i].Status = http.StatusNoContent
+			continue
+		default:
+			out[i].Status = http.StatusOK
+		}
+		t := toTodo(*res.Todo, zone(r))
+		out[i].Todo = &t
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
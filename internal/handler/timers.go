@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+	"github.com/go-chi/chi"
+)
+
+// timeEntry is the JSON representation of a tracked stretch of time.
+type timeEntry struct {
+	ID     string     `json:"id"`
+	TodoID string     `json:"todo_id"`
+	Start  time.Time  `json:"start"`
+	End    *time.Time `json:"end"`
+	// Seconds is 0 while the timer runs.
+	Seconds int64 `json:"seconds"`
+}
+
+func toTimeEntry(e model.TimeEntry) timeEntry {
+	out := timeEntry{ID: e.ID.Hex(), TodoID: e.TodoID.Hex(), Start: e.Start, End: e.End}
+	if e.End != nil {
+		out.Seconds = int64(e.End.Sub(e.Start) / time.Second)
+	}
+	return out
+}
+
+// startTimer starts tracking time against a todo. It answers 409 if a
+// timer already runs for it.
+func (h *Handler) startTimer(w http.ResponseWriter, r *http.Request) {
+	e, err := h.todos.StartTimer(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.fail(w, r, err, "failed to start the timer")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusCreated, toTimeEntry(*e))
+}
+
+// stopTimer stops a todo's timer, adding the time to its tracked_seconds.
+func (h *Handler) stopTimer(w http.ResponseWriter, r *http.Request) {
+	e, err := h.todos.StopTimer(r.Context(), strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.fail(w, r, err, "failed to stop the timer")
+		return
+	}
+	h.rnd.Data(w, r, http.StatusOK, toTimeEntry(*e))
+}
+
+// period reads a report's ?from= and ?to=, the first and last day as
+// YYYY-MM-DD in the tenant's time zone, and returns them as the start of
+// from and the end of to. It answers 400 itself if they aren't dates.
+func (h *Handler) period(w http.ResponseWriter, r *http.Request) (from, to time.Time, ok bool) {
+	q := r.URL.Query()
+	loc := tz.FromContext(r.Context())
+	from, err := time.ParseInLocation("2006-01-02", q.Get("from"), loc)
+	if err != nil {
+		h.rnd.Problem(w, r, http.StatusBadRequest, "from must be a date, YYYY-MM-DD")
+		return from, to, false
+	}
+	to, err = time.ParseInLocation("2006-01-02", q.Get("to"), loc)
+	if err != nil {
+		h.rnd.Problem(w, r, http.StatusBadRequest, "to must be a date, YYYY-MM-DD")
+		return from, to, false
+	}
+	return from, to.AddDate(0, 0, 1), true
+}
+
+// timeTotal is one row of a time report.
+type timeTotal struct {
+	Key     string `json:"key"`
+	Seconds int64  `json:"seconds"`
+}
+
+// timeReport totals tracked time per day or tag over the period given as
+// for period; ?by= is day (the default) or tag.
+func (h *Handler) timeReport(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := h.period(w, r)
+	if !ok {
+		return
+	}
+	by := r.URL.Query().Get("by")
+	if by == "" {
+		by = service.ByDay
+	}
+	totals, err := h.todos.TimeReport(r.Context(), from, to, by)
+	if err != nil {
+		h.fail(w, r, err, "failed to report tracked time")
+		return
+	}
+	out := make([]timeTotal, 0, len(totals))
+	var sum int64
+	for _, t := range totals {
+		secs := int64(t.Duration / time.Second)
+		out = append(out, timeTotal{Key: t.Key, Seconds: secs})
+		if by == service.ByDay {
+			sum += secs
+		}
+	}
+	body := render.M{"by": by, "totals": out}
+	if by == service.ByDay {
+		// By tag, time on several tags would count more than once.
+		body["total_seconds"] = sum
+	}
+	h.rnd.Data(w, r, http.StatusOK, body)
+}
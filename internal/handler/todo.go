@@ -0,0 +1,413 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+	"github.com/go-chi/chi"
+)
+
+// todo is the JSON representation of a todo.
+type todo struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+	// CompletedAt is read-only.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Status, when given, takes precedence over Completed.
+	Status string     `json:"status,omitempty"`
+	List   string     `json:"list"`
+	DueAt  *time.Time `json:"due_at"`
+	// Due is write-only: a due date in words, such as "tomorrow 5pm",
+	// which replaces DueAt.
+	Due   string   `json:"due,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	Notes string   `json:"notes,omitempty"`
+	// EstimateMinutes is 0 for no estimate.
+	EstimateMinutes int `json:"estimate_minutes,omitempty"`
+	// Fields are the custom fields of the todo's list, by name.
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// GoalID is read-only; see /goals/{id}/todos.
+	GoalID string `json:"goal_id,omitempty"`
+	// Links, only in GET /todo/{id}, relate the todo to others in both
+	// directions; see /todo/{id}/links.
+	Links []link `json:"links,omitempty"`
+	// Geofence ties the todo to a place; see GET /todo/nearby.
+	Geofence *geofence `json:"geofence,omitempty"`
+	// TrackedSeconds is read-only; timers add to it.
+	TrackedSeconds int64 `json:"tracked_seconds,omitempty"`
+	// BlockedBy is read-only: the ids of the todos blocking this one, set
+	// through /todo/{id}/blocked_by.
+	BlockedBy []string `json:"blocked_by,omitempty"`
+	// Snoozes is read-only; POST /todo/{id}/snooze adds to it.
+	Snoozes []snooze `json:"snoozes,omitempty"`
+	// Slug is read-only; POST /todo/{id}/slug sets it.
+	Slug string `json:"slug,omitempty"`
+	// External is read-only: links are made by the integrations.
+	External  map[string]string `json:"external,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	// ParsedDue, in responses, shows how Due or the title was read.
+	ParsedDue *parsedDue `json:"parsed_due,omitempty"`
+}
+
+// parsedDue is the JSON representation of a service.DueReading.
+type parsedDue struct {
+	Text      string    `json:"text"`
+	DueAt     time.Time `json:"due_at"`
+	FromTitle bool      `json:"from_title"`
+}
+
+// toTodo converts t, showing its due date in loc; nil keeps it in UTC.
+func toTodo(t model.Todo, loc *time.Location) todo {
+	if t.DueAt != nil && loc != nil {
+		due := t.DueAt.In(loc)
+		t.DueAt = &due
+	}
+	var blockedBy []string
+	for _, id := range t.BlockedBy {
+		blockedBy = append(blockedBy, id.Hex())
+	}
+	var snoozes []snooze
+	for _, sn := range t.Snoozes {
+		snoozes = append(snoozes, snooze(sn))
+	}
+	var goalID string
+	if !t.GoalID.IsZero() {
+		goalID = t.GoalID.Hex()
+	}
+	return todo{
+		ID:              t.ID.Hex(),
+		Title:           t.Title,
+		Completed:       t.Completed,
+		CompletedAt:     t.CompletedAt,
+		Status:          t.CurrentStatus(),
+		List:            t.List,
+		DueAt:           t.DueAt,
+		Tags:            t.Tags,
+		Notes:           t.Notes,
+		EstimateMinutes: t.EstimateMinutes,
+		Fields:          t.Fields,
+		Geofence:        (*geofence)(t.Geofence),
+		Slug:            t.Slug,
+		External:        t.External,
+		TrackedSeconds:  t.TrackedSeconds,
+		BlockedBy:       blockedBy,
+		Snoozes:         snoozes,
+		GoalID:          goalID,
+		CreatedAt:       t.CreatedAt,
+		UpdatedAt:       t.UpdatedAt,
+	}
+}
+
+// zone is the time zone the tenant chose to see dates in, nil if none.
+func zone(r *http.Request) *time.Location {
+	loc, _ := tz.Lookup(r.Context())
+	return loc
+}
+
+func (t todo) input() service.TodoInput {
+	return service.TodoInput{
+		Title:           t.Title,
+		List:            t.List,
+		Completed:       t.Completed,
+		Status:          t.Status,
+		DueAt:           t.DueAt,
+		Tags:            t.Tags,
+		Notes:           t.Notes,
+		EstimateMinutes: t.EstimateMinutes,
+		Fields:          t.Fields,
+		Geofence:        (*model.Geofence)(t.Geofence),
+	}
+}
+
+// interpret reads t.Due, and with ?parse_dates=true a date ending the
+// title, into in. It returns the reading to echo in the response.
+func (h *Handler) interpret(r *http.Request, t todo, in *service.TodoInput) (*parsedDue, error) {
+	fromTitle, _ := strconv.ParseBool(r.URL.Query().Get("parse_dates"))
+	rd, err := h.todos.InterpretDue(r.Context(), in, t.Due, fromTitle)
+	if err != nil || rd == nil {
+		return nil, err
+	}
+	return &parsedDue{Text: rd.Text, DueAt: rd.DueAt, FromTitle: rd.FromTitle}, nil
+}
+
+// pageQuery reads the optional ?offset= and ?limit= of a list request.
+// paged is false if neither is given.
+func pageQuery(r *http.Request) (offset, limit int, paged bool, err error) {
+	q := r.URL.Query()
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{{"offset", &offset}, {"limit", &limit}} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, false, service.NewValidationError(p.name, "%s must be a number, 0 or more", p.name)
+		}
+		*p.dst = n
+		paged = true
+	}
+	return offset, limit, paged, nil
+}
+
+// filterQuery reads the filter of a list request: ?tags= todos must carry
+// all of, ?tags_any= at least one of and ?tags_not= none of, ?list= and
+// ?field.NAME= for the value of the list's custom field NAME, and
+// ?blocked= to keep todos blocked, or not, by others. Tags are
+// separated by commas or '+', which a query string also reads as a space.
+func filterQuery(r *http.Request) store.TodoFilter {
+	q := r.URL.Query()
+	split := func(name string) []string {
+		return strings.FieldsFunc(q.Get(name), func(c rune) bool {
+			return c == ',' || c == '+' || c == ' '
+		})
+	}
+	f := store.TodoFilter{
+		Tags:    split("tags"),
+		AnyTags: split("tags_any"),
+		NotTags: split("tags_not"),
+		List:    strings.TrimSpace(q.Get("list")),
+	}
+	if b, err := strconv.ParseBool(q.Get("blocked")); err == nil {
+		f.Blocked = &b
+	}
+	for key, vs := range q {
+		if name := strings.TrimPrefix(key, "field."); name != key {
+			if f.Fields == nil {
+				f.Fields = map[string]interface{}{}
+			}
+			f.Fields[name] = vs[0]
+		}
+	}
+	return f
+}
+
+// setTotal puts the number of todos matching f in X-Total-Count and
+// returns it.
+func (h *Handler) setTotal(w http.ResponseWriter, r *http.Request, f store.TodoFilter) (int64, error) {
+	n, err := h.todos.Count(r.Context(), f)
+	if err != nil {
+		return 0, err
+	}
+	w.Header().Set("X-Total-Count", strconv.FormatInt(n, 10))
+	return n, nil
+}
+
+// countTodos answers HEAD /todo with just X-Total-Count, for badges and
+// pagination controls. It takes the same filter as fetchTodo.
+func (h *Handler) countTodos(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.setTotal(w, r, filterQuery(r)); err != nil {
+		h.fail(w, r, err, "failed to count todos")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// fetchTodo lists every todo, oldest first, or those passing the filter
+// of filterQuery. With ?offset= or ?limit= it returns that page
+// instead, with the total in X-Total-Count and the meta of the envelope.
+func (h *Handler) fetchTodo(w http.ResponseWriter, r *http.Request) {
+	h.listTodos(w, r, filterQuery(r))
+}
+
+// listTodos streams the todos matching f, paged by pageQuery.
+func (h *Handler) listTodos(w http.ResponseWriter, r *http.Request, f store.TodoFilter) {
+	offset, limit, paged, err := pageQuery(r)
+	var total int64
+	if err == nil && paged {
+		total, err = h.setTotal(w, r, f)
+	}
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch todos")
+		return
+	}
+	list := h.rnd.List(w, r, http.StatusOK)
+	if paged {
+		list.Meta(render.M{"total": total, "offset": offset, "limit": limit})
+	}
+	err = h.todos.Each(r.Context(), f, offset, limit, func(t *model.Todo) error {
+		return list.Write(toTodo(*t, zone(r)))
+	})
+	if err != nil && list.Started() {
+		// Too late for a problem response; cut the body short so the
+		// client can't mistake it for the whole list.
+		h.log.Printf("fetch todos: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+	if err != nil {
+		h.fail(w, r, err, "failed to fetch todos")
+		return
+	}
+	list.Close()
+}
+
+func (h *Handler) createTodo(w http.ResponseWriter, r *http.Request) {
+	var t todo
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	in := t.input()
+	pd, err := h.interpret(r, t, &in)
+	if err != nil {
+		h.fail(w, r, err, "failed to Insert todo into database")
+		return
+	}
+	tm, err := h.todos.Create(r.Context(), in)
+	if err != nil {
+		h.fail(w, r, err, "failed to Insert todo into database")
+		return
+	}
+	out := toTodo(*tm, zone(r))
+	out.ParsedDue = pd
+	h.rnd.Data(w, r, http.StatusCreated, out)
+}
+
+func (h *Handler) deleteTodo(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	err := h.todos.Delete(r.Context(), id)
+	if err != nil {
+		h.fail(w, r, err, "failed to Delete todo from database")
+		return
+	}
+
+	h.rnd.NoContent(w)
+}
+
+func (h *Handler) updateTodo(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	var t todo
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	in := t.input()
+	pd, err := h.interpret(r, t, &in)
+	if err != nil {
+		h.fail(w, r, err, "failed to update todo")
+		return
+	}
+	tm, err := h.todos.Update(r.Context(), id, in)
+	if err != nil {
+		h.fail(w, r, err, "failed to update todo")
+		return
+	}
+	out := toTodo(*tm, zone(r))
+	out.ParsedDue = pd
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
+
+// maxBulk caps the todos one bulk request may carry.
+const maxBulk = 1000
+
+// bulkResult reports on one todo of a bulk request, with the status a
+// single request for it would have had.
+type bulkResult struct {
+	Index    int       `json:"index"`
+	Status   int       `json:"status"`
+	Todo     *todo     `json:"todo,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	Conflict *conflict `json:"conflict,omitempty"`
+}
+
+// bulkTodos creates the todos in the body's array that have no id and
+// updates the others. It answers 200 with a result per todo, since some
+// may fail while the rest are saved. An update whose todo changed since its
+// updated_at, or its base, is settled by ?on_conflict=.
+func (h *Handler) bulkTodos(w http.ResponseWriter, r *http.Request) {
+	strategy, ok := h.onConflict(w, r)
+	if !ok {
+		return
+	}
+	var in []edit
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.badBody(w, r, err)
+		return
+	}
+	if len(in) > maxBulk {
+		h.rnd.Problem(w, r, http.StatusBadRequest, "A bulk request may hold at most %d todos", maxBulk)
+		return
+	}
+	items := make([]service.BulkItem, len(in))
+	for i, t := range in {
+		items[i] = t.item(strategy)
+	}
+	out := make([]bulkResult, len(items))
+	for i, res := range h.todos.WriteMany(r.Context(), items) {
+		out[i].Index = i
+		if res.Err != nil {
+			out[i].Status, out[i].Error = classify(r, res.Err, "failed to save todo")
+			out[i].Conflict = toConflict(res.Err, in[i].todo, zone(r))
+			continue
+		}
+		t := toTodo(*res.Todo, zone(r))
+		out[i].Todo = &t
+		out[i].Status = http.StatusOK
+		if res.Created {
+			out[i].Status = http.StatusCreated
+		}
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
+
+// searchArchive looks through archived todos: ?q= matches the title, ?list=
+// narrows to one list and ?limit= caps the results (50 by default).
+func (h *Handler) searchArchive(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := 50
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			h.rnd.Problem(w, r, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+		limit = n
+	}
+	todos, err := h.todos.SearchArchive(r.Context(), q.Get("q"), q.Get("list"), limit)
+	if err != nil {
+		h.fail(w, r, err, "failed to search the archive")
+		return
+	}
+	out := make([]todo, 0, len(todos))
+	for _, t := range todos {
+		out = append(out, toTodo(t, zone(r)))
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
+
+// searchTodos finds todos by title, forgiving typos: ?q= is the query and
+// ?limit= caps the results (20 by default).
+func (h *Handler) searchTodos(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := 20
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			h.rnd.Problem(w, r, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+		limit = n
+	}
+	todos, err := h.todos.Search(r.Context(), q.Get("q"), limit)
+	if err != nil {
+		h.fail(w, r, err, "failed to search todos")
+		return
+	}
+	out := make([]todo, 0, len(todos))
+	for _, t := range todos {
+		out = append(out, toTodo(t, zone(r)))
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+
+	"dhruvarora9/personal-todo-golang/internal/middleware"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+	"github.com/go-chi/chi"
+)
+
+// smsChannel is the notification channel phone numbers opt in under.
+const smsChannel = "sms"
+
+// stopWords are the replies carriers and Twilio treat as an opt-out.
+var stopWords = map[string]bool{
+	"STOP": true, "STOPALL": true, "UNSUBSCRIBE": true,
+	"CANCEL": true, "END": true, "QUIT": true,
+}
+
+// keywords are the other replies carriers and Twilio act on themselves;
+// they never become todos.
+var keywords = map[string]bool{
+	"START": true, "YES": true, "UNSTOP": true, "HELP": true, "INFO": true,
+}
+
+// Twilio handles incoming SMS. An opt-out keyword deletes the sender's SMS
+// integrations in every tenant. Any other text from a number with an SMS
+// integration, and so verified by its owner, becomes a todo in each tenant
+// the number is registered with; texts from other numbers are ignored.
+type Twilio struct {
+	todos        *service.TodoService
+	integrations *service.IntegrationService
+	settings     *service.SettingsService
+	rnd          *render.Renderer
+	authToken    string
+	webhookURL   string
+}
+
+func NewTwilio(todos *service.TodoService, integrations *service.IntegrationService, settings *service.SettingsService, rnd *render.Renderer, authToken, webhookURL string) *Twilio {
+	return &Twilio{todos: todos, integrations: integrations, settings: settings, rnd: rnd, authToken: authToken, webhookURL: webhookURL}
+}
+
+func (t *Twilio) Routes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Use(middleware.VerifyTwilio(t.authToken, t.webhookURL, t.rnd))
+	rg.Post("/sms", t.incoming)
+	return rg
+}
+
+func (t *Twilio) incoming(w http.ResponseWriter, r *http.Request) {
+	text := strings.TrimSpace(r.PostForm.Get("Body"))
+	from := r.PostForm.Get("From")
+	word := strings.ToUpper(text)
+	switch {
+	case stopWords[word]:
+		if err := t.unsubscribe(r, from); err != nil {
+			middleware.RecordError(r, err)
+			t.rnd.Problem(w, r, http.StatusInternalServerError, "failed to opt out")
+			return
+		}
+		// Twilio itself confirms the opt-out.
+		t.reply(w, "")
+	case keywords[word] || text == "":
+		t.reply(w, "")
+	default:
+		t.reply(w, t.addTodos(r, from, text))
+	}
+}
+
+// addTodos adds a todo from text in each tenant from is registered with,
+// reading its due date in that tenant's zone, and returns the replies.
+func (t *Twilio) addTodos(r *http.Request, from, text string) string {
+	tenants, err := t.integrations.Tenants(r.Context(), smsChannel, from)
+	if err != nil {
+		middleware.RecordError(r, err)
+		return "Sorry, the todo could not be added."
+	}
+	var replies []string
+	for _, id := range tenants {
+		ctx := tenant.NewContext(r.Context(), id)
+		loc, err := t.settings.Location(ctx)
+		if err != nil {
+			middleware.RecordError(r, err)
+		}
+		msg, err := t.addTodo(tz.NewContext(ctx, loc), text)
+		if err != nil {
+			middleware.RecordError(r, err)
+			msg = "Sorry, the todo could not be added."
+		}
+		replies = append(replies, msg)
+	}
+	return strings.Join(replies, "\n")
+}
+
+// unsubscribe deletes from's SMS integrations in each tenant that has one.
+// The webhook is mounted outside tenancy, and the dispatcher texts through
+// every tenant's integrations, so all of them must go.
+func (t *Twilio) unsubscribe(r *http.Request, from string) error {
+	tenants, err := t.integrations.Tenants(r.Context(), smsChannel, from)
+	if err != nil {
+		return err
+	}
+	for _, id := range tenants {
+		if err := t.integrations.Unsubscribe(tenant.NewContext(r.Context(), id), smsChannel, from); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addTodo adds a todo from text in the tenant ctx acts for, reading a due
+// date off its end, and returns the reply.
+func (t *Twilio) addTodo(ctx context.Context, text string) (string, error) {
+	in := service.TodoInput{Title: text}
+	if _, err := t.todos.InterpretDue(ctx, &in, "", true); err != nil {
+		return "", err
+	}
+	todo, err := t.todos.Create(ctx, in)
+	var ve *service.ValidationError
+	if errors.As(err, &ve) {
+		return ve.Message, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	msg := "Added: " + todo.Title
+	if todo.DueAt != nil {
+		msg += ", due " + todo.DueAt.In(tz.FromContext(ctx)).Format("Mon Jan 2 15:04")
+	}
+	return msg, nil
+}
+
+// reply answers with TwiML that texts msg back, or sends nothing if msg is
+// empty.
+func (t *Twilio) reply(w http.ResponseWriter, msg string) {
+	w.Header().Set("Content-Type", "text/xml")
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><Response>`)
+	if msg != "" {
+		b.WriteString("<Message>")
+		xml.EscapeText(&b, []byte(msg))
+		b.WriteString("</Message>")
+	}
+	b.WriteString("</Response>")
+	w.Write([]byte(b.String()))
+}
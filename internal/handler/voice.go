@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+	"dhruvarora9/personal-todo-golang/internal/middleware"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+	"github.com/go-chi/chi"
+)
+
+// Voice intents, named alike on both platforms. AddTodo and CompleteTodo
+// take the todo in a slot or parameter called "todo".
+const (
+	intentAdd      = "AddTodo"
+	intentList     = "ListTodos"
+	intentComplete = "CompleteTodo"
+	intentSlot     = "todo"
+)
+
+// voiceListed caps the todos ListTodos reads out.
+const voiceListed = 5
+
+const voiceHelp = "You can say: add buy milk tomorrow, what's on my list, or complete buy milk."
+
+// Voice fulfils the intents of an Alexa skill and a Dialogflow agent on
+// the default tenant, replying with something to say.
+type Voice struct {
+	todos   *service.TodoService
+	rnd     *render.Renderer
+	token   string
+	skillID string
+}
+
+func NewVoice(todos *service.TodoService, rnd *render.Renderer, token, alexaSkillID string) *Voice {
+	return &Voice{todos: todos, rnd: rnd, token: token, skillID: alexaSkillID}
+}
+
+func (v *Voice) Routes() http.Handler {
+	rg := chi.NewRouter()
+	rg.Use(v.checkToken)
+	rg.Post("/alexa", v.alexa)
+	rg.Post("/google", v.google)
+	return rg
+}
+
+func (v *Voice) checkToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(v.token)) != 1 {
+			v.rnd.Problem(w, r, http.StatusUnauthorized, "The token is invalid")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// answer carries out intent with the todo named, if any, and returns what
+// to say.
+func (v *Voice) answer(ctx context.Context, intent, todo string) (string, error) {
+	locale := i18n.FromContext(ctx)
+	todo = strings.TrimSpace(todo)
+	switch intent {
+	case intentAdd:
+		if todo == "" {
+			return i18n.T(locale, "What should I add?"), nil
+		}
+		in := service.TodoInput{Title: todo}
+		if _, err := v.todos.InterpretDue(ctx, &in, "", true); err != nil {
+			return "", err
+		}
+		t, err := v.todos.Create(ctx, in)
+		if err != nil {
+			return "", err
+		}
+		if t.DueAt != nil {
+			return i18n.T(locale, "Added %s, due %s.", t.Title, t.DueAt.In(tz.FromContext(ctx)).Format("Monday, January 2 at 3:04 PM")), nil
+		}
+		return i18n.T(locale, "Added %s.", t.Title), nil
+	case intentList:
+		return v.listOpen(ctx)
+	case intentComplete:
+		if todo == "" {
+			return i18n.T(locale, "Which todo did you complete?"), nil
+		}
+		found, err := v.todos.Search(ctx, todo, voiceListed)
+		if err != nil {
+			return "", err
+		}
+		for _, t := range found {
+			if t.Completed {
+				continue
+			}
+			_, err := v.todos.SetCompleted(ctx, t.ID.Hex(), true)
+			if errors.Is(err, service.ErrBlocked) {
+				return i18n.T(locale, "%s is still waiting on another todo.", t.Title), nil
+			}
+			if err != nil {
+				return "", err
+			}
+			return i18n.T(locale, "Completed %s.", t.Title), nil
+		}
+		return i18n.T(locale, "I couldn't find an open todo called %s.", todo), nil
+	}
+	return i18n.T(locale, voiceHelp), nil
+}
+
+// listOpen reads out the open todos due first.
+func (v *Voice) listOpen(ctx context.Context) (string, error) {
+	locale := i18n.FromContext(ctx)
+	completed := false
+	f := store.TodoFilter{Completed: &completed, Sort: "due_at"}
+	n, err := v.todos.Count(ctx, f)
+	if err != nil {
+		return "", err
+	}
+	if n == 0 {
+		return i18n.T(locale, "Your list is empty."), nil
+	}
+	var open []model.Todo
+	err = v.todos.Each(ctx, f, 0, voiceListed, func(t *model.Todo) error {
+		open = append(open, *t)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(open) == 0 {
+		return i18n.T(locale, "Your list is empty."), nil
+	}
+	titles := make([]string, len(open))
+	for i, t := range open {
+		titles[i] = t.Title
+	}
+	if n == 1 {
+		return i18n.T(locale, "You have one todo: %s.", titles[0]), nil
+	}
+	last := len(titles) - 1
+	list := i18n.T(locale, "%s and %s", strings.Join(titles[:last], ", "), titles[last])
+	if n > int64(len(open)) {
+		return i18n.T(locale, "You have %d todos. The first %d are: %s.", n, len(open), list), nil
+	}
+	return i18n.T(locale, "You have %d todos. %s.", n, list), nil
+}
+
+// spokenIn returns r answering in the language the assistant speaks,
+// lang, if there are messages in it. Otherwise Accept-Language decides.
+func spokenIn(r *http.Request, lang string) *http.Request {
+	if locale := i18n.Match(lang); locale != "" {
+		return r.WithContext(i18n.NewContext(r.Context(), locale))
+	}
+	return r
+}
+
+// reply is answer with failures turned into something to say.
+func (v *Voice) reply(r *http.Request, intent, todo string) string {
+	speech, err := v.answer(r.Context(), intent, todo)
+	var ve *service.ValidationError
+	if errors.As(err, &ve) {
+		return ve.In(i18n.FromContext(r.Context()))
+	}
+	if err != nil {
+		middleware.RecordError(r, err)
+		return i18n.T(i18n.FromContext(r.Context()), "Sorry, something went wrong.")
+	}
+	return speech
+}
+
+// alexaRequest is the part of an Alexa skill request the skill reads.
+type alexaRequest struct {
+	Session struct {
+		Application struct {
+			ApplicationID string `json:"applicationId"`
+		} `json:"application"`
+	} `json:"session"`
+	Request struct {
+		Type   string `json:"type"`
+		Locale string `json:"locale"`
+		Intent struct {
+			Name  string `json:"name"`
+			Slots map[string]struct {
+				Value string `json:"value"`
+			} `json:"slots"`
+		} `json:"intent"`
+	} `json:"request"`
+}
+
+// alexa answers an Alexa skill request. A launch asks what to do and keeps
+// the session open; an intent is answered and ends it.
+func (v *Voice) alexa(w http.ResponseWriter, r *http.Request) {
+	var in alexaRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&in); err != nil {
+		v.rnd.Problem(w, r, http.StatusBadRequest, "The request body is not valid JSON: %v", err)
+		return
+	}
+	if v.skillID != "" && in.Session.Application.ApplicationID != v.skillID {
+		v.rnd.Problem(w, r, http.StatusForbidden, "The request is for another skill")
+		return
+	}
+	r = spokenIn(r, in.Request.Locale)
+	speech, end := i18n.T(i18n.FromContext(r.Context()), voiceHelp), false
+	switch in.Request.Type {
+	case "SessionEndedRequest":
+		v.rnd.JSON(w, http.StatusOK, render.M{"version": "1.0", "response": render.M{}})
+		return
+	case "IntentRequest":
+		switch name := in.Request.Intent.Name; name {
+		case "AMAZON.StopIntent", "AMAZON.CancelIntent":
+			speech, end = i18n.T(i18n.FromContext(r.Context()), "Goodbye."), true
+		case "AMAZON.HelpIntent":
+		default:
+			speech, end = v.reply(r, name, in.Request.Intent.Slots[intentSlot].Value), true
+		}
+	}
+	v.rnd.JSON(w, http.StatusOK, render.M{
+		"version": "1.0",
+		"response": render.M{
+			"outputSpeech":     render.M{"type": "PlainText", "text": speech},
+			"shouldEndSession": end,
+		},
+	})
+}
+
+// dialogflowRequest is the part of a Dialogflow ES fulfillment request the
+// agent reads.
+type dialogflowRequest struct {
+	QueryResult struct {
+		Intent struct {
+			DisplayName string `json:"displayName"`
+		} `json:"intent"`
+		Parameters   map[string]interface{} `json:"parameters"`
+		LanguageCode string                 `json:"languageCode"`
+	} `json:"queryResult"`
+}
+
+// google answers a Dialogflow ES fulfillment request, as sent for a Google
+// Action built on Dialogflow.
+func (v *Voice) google(w http.ResponseWriter, r *http.Request) {
+	var in dialogflowRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&in); err != nil {
+		v.rnd.Problem(w, r, http.StatusBadRequest, "The request body is not valid JSON: %v", err)
+		return
+	}
+	r = spokenIn(r, in.QueryResult.LanguageCode)
+	todo, _ := in.QueryResult.Parameters[intentSlot].(string)
+	speech := v.reply(r, in.QueryResult.Intent.DisplayName, todo)
+	v.rnd.JSON(w, http.StatusOK, render.M{"fulfillmentText": speech})
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+// widgetMaxAge is how long caches may keep a widget, and so how long a
+// revoked share can still show in one.
+const widgetMaxAge = 5 * time.Minute
+
+// widgetPage is the data of the widget template.
+type widgetPage struct {
+	Title string
+	Todos []sharedTodo
+}
+
+// ShareWidget serves a share as a small self-contained HTML page for an
+// iframe or, with ?format=json, as JSON any site may fetch. Responses
+// carry an ETag and may be cached until the share expires, for at most
+// widgetMaxAge.
+func (h *Handler) ShareWidget(w http.ResponseWriter, r *http.Request) {
+	shared, err := h.shares.Open(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		h.failShare(w, r, err, "failed to open the share")
+		return
+	}
+	page := widgetPage{Title: shared.Share.List, Todos: make([]sharedTodo, 0, len(shared.Todos))}
+	// The tag changes when a todo is added, removed or edited.
+	sum := sha256.New()
+	fmt.Fprintf(sum, "%s\x00%s\x00", shared.Share.Token, r.URL.Query().Get("format"))
+	for _, t := range shared.Todos {
+		page.Todos = append(page.Todos, toSharedTodo(t))
+		binary.Write(sum, binary.BigEndian, t.UpdatedAt.UnixNano())
+	}
+	if shared.Share.List == "" {
+		page.Title = shared.Todos[0].Title
+	}
+	etag := fmt.Sprintf(`"%x"`, sum.Sum(nil)[:16])
+
+	maxAge := widgetMaxAge
+	if exp := shared.Share.ExpiresAt; exp != nil && exp.Sub(time.Now()) < maxAge {
+		maxAge = exp.Sub(time.Now())
+	}
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge/time.Second)))
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if r.URL.Query().Get("format") == "json" {
+		h.rnd.Data(w, r, http.StatusOK, page.Todos)
+		return
+	}
+	if err := h.rnd.HTML(w, r, http.StatusOK, "widget.tpl", page); err != nil {
+		h.log.Printf("share widget: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// defaultCapacity is the minutes of work a day holds when the request
+// doesn't say.
+const defaultCapacity = 8 * 60
+
+// workloadDay is one row of the workload view.
+type workloadDay struct {
+	Day         string `json:"day"`
+	Minutes     int    `json:"minutes"`
+	Todos       int    `json:"todos"`
+	Unestimated int    `json:"unestimated"`
+	// Over is set when Minutes exceeds the capacity.
+	Over bool `json:"over"`
+}
+
+// workload sums the estimates of open todos per due day over the period
+// given as for period, flagging days over ?capacity_minutes= (480 by
+// default).
+func (h *Handler) workload(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := h.period(w, r)
+	if !ok {
+		return
+	}
+	capacity := defaultCapacity
+	if v := r.URL.Query().Get("capacity_minutes"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			h.rnd.Problem(w, r, http.StatusBadRequest, "capacity_minutes must be a positive number")
+			return
+		}
+		capacity = n
+	}
+	days, err := h.todos.Workload(r.Context(), from, to)
+	if err != nil {
+		h.fail(w, r, err, "failed to work out the workload")
+		return
+	}
+	out := make([]workloadDay, 0, len(days))
+	for _, d := range days {
+		out = append(out, workloadDay{
+			Day:         d.Day,
+			Minutes:     d.Minutes,
+			Todos:       d.Todos,
+			Unestimated: d.Unestimated,
+			Over:        d.Minutes > capacity,
+		})
+	}
+	h.rnd.Data(w, r, http.StatusOK, out)
+}
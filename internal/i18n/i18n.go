@@ -0,0 +1,141 @@
+// Package i18n translates the messages people read: API errors, pages and
+// email. Messages are written in English in the code; a catalog per
+// locale, catalogs/<locale>.json, maps each English message, or the fmt
+// format it is made from, to its translation. Messages a catalog lacks
+// stay in English.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Default is the locale of the messages in the code.
+const Default = "en"
+
+//go:embed catalogs/*.json
+var files embed.FS
+
+// catalogs maps each locale but Default to its translations.
+var catalogs = func() map[string]map[string]string {
+	names, err := files.ReadDir("catalogs")
+	if err != nil {
+		panic(err)
+	}
+	out := map[string]map[string]string{}
+	for _, f := range names {
+		b, err := files.ReadFile("catalogs/" + f.Name())
+		if err != nil {
+			panic(err)
+		}
+		var c map[string]string
+		if err := json.Unmarshal(b, &c); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", f.Name(), err))
+		}
+		out[strings.TrimSuffix(f.Name(), path.Ext(f.Name()))] = c
+	}
+	return out
+}()
+
+// Locales lists the locales there are messages in.
+func Locales() []string {
+	out := []string{Default}
+	for l := range catalogs {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Match returns the first of tags, language tags such as "de-AT", whose
+// locale or language there are messages in; "" if there is none.
+func Match(tags ...string) string {
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+		if tag == "" {
+			continue
+		}
+		lang, _, _ := strings.Cut(tag, "-")
+		for _, l := range []string{tag, lang} {
+			if _, ok := catalogs[l]; ok || l == Default {
+				return l
+			}
+		}
+	}
+	return ""
+}
+
+// MatchAccept is Match for the languages an Accept-Language header
+// lists, such as "de-CH, de;q=0.9, en;q=0.5", tried by their weight.
+func MatchAccept(header string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		q := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			var err error
+			if q, err = strconv.ParseFloat(params[2:], 64); err != nil {
+				continue
+			}
+		}
+		if tag == "" || tag == "*" || q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag, q})
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.tag
+	}
+	return Match(names...)
+}
+
+// T translates msg into locale and, given args, formats it with them.
+func T(locale, msg string, args ...interface{}) string {
+	if t, ok := catalogs[locale][msg]; ok {
+		msg = t
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Funcs returns the template functions that translate into locale: t,
+// which is T, and locale, which returns locale itself. The result suits
+// both text/template and html/template.
+func Funcs(locale string) map[string]interface{} {
+	return map[string]interface{}{
+		"t": func(msg string, args ...interface{}) string {
+			return T(locale, msg, args...)
+		},
+		"locale": func() string { return locale },
+	}
+}
+
+type contextKey struct{}
+
+// NewContext returns ctx carrying locale.
+func NewContext(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale ctx carries, Default if none.
+func FromContext(ctx context.Context) string {
+	if l, ok := ctx.Value(contextKey{}).(string); ok && l != "" {
+		return l
+	}
+	return Default
+}
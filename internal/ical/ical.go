@@ -0,0 +1,143 @@
+// Package ical reads and writes the parts of iCalendar (RFC 5545) that todo
+// imports and CalDAV need: content lines, text escaping and times.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/nldate"
+)
+
+// Prop is one content line, such as DUE;TZID=Europe/Paris:20240310T170000.
+type Prop struct {
+	// Name is upper case.
+	Name string
+	// Params are keyed by upper-case name.
+	Params map[string]string
+	Value  string
+}
+
+// Scan calls fn with each content line of r, unfolded.
+func Scan(r io.Reader, fn func(Prop)) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	var line string
+	flush := func() {
+		if line == "" {
+			return
+		}
+		head, value, _ := strings.Cut(line, ":")
+		parts := strings.Split(head, ";")
+		p := Prop{Name: strings.ToUpper(parts[0]), Params: map[string]string{}, Value: value}
+		for _, param := range parts[1:] {
+			k, v, _ := strings.Cut(param, "=")
+			p.Params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+		fn(p)
+	}
+	for sc.Scan() {
+		l := strings.TrimRight(sc.Text(), "\r")
+		if strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t") {
+			line += l[1:]
+			continue
+		}
+		flush()
+		line = l
+	}
+	flush()
+	return sc.Err()
+}
+
+// Text is the value unescaped.
+func (p Prop) Text() string {
+	return unescaper.Replace(p.Value)
+}
+
+// List is the value split at unescaped commas, as in CATEGORIES.
+func (p Prop) List() []string {
+	var out []string
+	for _, v := range strings.Split(strings.ReplaceAll(p.Value, `\,`, "\x00"), ",") {
+		out = append(out, unescaper.Replace(strings.ReplaceAll(v, "\x00", `\,`)))
+	}
+	return out
+}
+
+var unescaper = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+var escaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`)
+
+// Escape escapes s as a TEXT value.
+func Escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// Due reads a DATE or DATE-TIME value as a due time; a date alone is due
+// at nldate.DefaultHour. Times without a zone are read in loc, or in the
+// TZID parameter's zone if Go knows it. It is nil if the value is not a
+// time.
+func (p Prop) Due(loc *time.Location) *time.Time {
+	if tz, ok := p.Params["TZID"]; ok {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+	var t time.Time
+	var err error
+	switch v := p.Value; {
+	case strings.HasSuffix(v, "Z"):
+		t, err = time.Parse("20060102T150405Z", v)
+	case len(v) == 8:
+		t, err = time.ParseInLocation("20060102", v, loc)
+		t = t.Add(nldate.DefaultHour * time.Hour)
+	default:
+		t, err = time.ParseInLocation("20060102T150405", v, loc)
+	}
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// UTC formats t as a UTC DATE-TIME value.
+func UTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// Writer writes content lines, folding those longer than 75 octets and
+// ending each with CRLF. The first error sticks and is returned by Err.
+type Writer struct {
+	w   io.Writer
+	err error
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Line writes name:value; value must already be escaped if it is text.
+func (w *Writer) Line(name, value string) {
+	if w.err != nil {
+		return
+	}
+	line := name + ":" + value
+	var b strings.Builder
+	n := 0
+	for _, r := range line {
+		size := len(string(r))
+		if n+size > 75 {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+	_, w.err = io.WriteString(w.w, b.String())
+}
+
+func (w *Writer) Err() error {
+	return w.err
+}
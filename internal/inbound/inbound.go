@@ -0,0 +1,209 @@
+// Package inbound turns email into todos: it gives each tenant an
+// unguessable address and reads the messages sent to it.
+package inbound
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+)
+
+// defaultLocal is the local part the default tenant's address starts with.
+const defaultLocal = "todo"
+
+// maxNotes caps the notes taken from a body, in bytes.
+const maxNotes = 10000
+
+// Addresses makes and reads the tenants' addresses.
+type Addresses struct {
+	domain string
+	secret []byte
+}
+
+func NewAddresses(domain, secret string) *Addresses {
+	return &Addresses{domain: strings.ToLower(domain), secret: []byte(secret)}
+}
+
+func (a *Addresses) token(tenantID string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(tenantID))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// Address returns the address of tenantID, "" for the default tenant.
+func (a *Addresses) Address(tenantID string) string {
+	local := tenantID
+	if local == "" {
+		local = defaultLocal
+	}
+	return local + "+" + a.token(tenantID) + "@" + a.domain
+}
+
+// Tenant returns the tenant whose address addr is.
+func (a *Addresses) Tenant(addr string) (string, bool) {
+	if p, err := mail.ParseAddress(addr); err == nil {
+		addr = p.Address
+	}
+	local, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(addr)), "@")
+	if !ok || domain != a.domain {
+		return "", false
+	}
+	name, token, ok := strings.Cut(local, "+")
+	if !ok {
+		return "", false
+	}
+	// A tenant may be called "todo" too; the token tells them apart.
+	if name == defaultLocal && hmac.Equal([]byte(token), []byte(a.token(""))) {
+		return "", true
+	}
+	if tenant.Valid(name) && hmac.Equal([]byte(token), []byte(a.token(name))) {
+		return name, true
+	}
+	return "", false
+}
+
+// FirstTenant returns the tenant of the first of recipients that is an
+// address of one.
+func (a *Addresses) FirstTenant(recipients []string) (string, bool) {
+	for _, r := range recipients {
+		list, err := mail.ParseAddressList(r)
+		if err != nil {
+			list = []*mail.Address{{Address: r}}
+		}
+		for _, addr := range list {
+			if id, ok := a.Tenant(addr.Address); ok {
+				return id, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ErrEmpty is returned by Todo for a message with neither subject nor
+// body.
+var ErrEmpty = errors.New("inbound: the message has no subject or text")
+
+var replyPrefix = regexp.MustCompile(`(?i)^\s*((re|fwd?|aw|wg)\s*:\s*)+`)
+
+// Todo is the todo a message with subject and plain-text body asks for:
+// the subject, without reply and forward prefixes, is its title and the
+// body, without its signature, its notes. A message without a subject
+// takes its title from the body's first line.
+func Todo(subject, body string) (service.TodoInput, error) {
+	title := strings.TrimSpace(replyPrefix.ReplaceAllString(subject, ""))
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	if i := strings.Index(body, "\n-- \n"); i >= 0 {
+		body = body[:i]
+	}
+	body = strings.TrimSpace(body)
+	if title == "" {
+		title, body, _ = strings.Cut(body, "\n")
+		title, body = strings.TrimSpace(title), strings.TrimSpace(body)
+	}
+	if title == "" {
+		return service.TodoInput{}, ErrEmpty
+	}
+	if len(body) > maxNotes {
+		body = body[:maxNotes]
+		for !utf8.ValidString(body) {
+			body = body[:len(body)-1]
+		}
+	}
+	return service.TodoInput{Title: title, Notes: body}, nil
+}
+
+// Message is what Parse reads from a raw message.
+type Message struct {
+	Subject string
+	// Text is the first text/plain part, decoded; empty if there is none.
+	Text string
+}
+
+var words = &mime.WordDecoder{}
+
+// Parse reads a raw RFC 5322 message. Text in charsets other than UTF-8
+// and ASCII is taken as is.
+func Parse(r io.Reader) (*Message, error) {
+	m, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := words.DecodeHeader(m.Header.Get("Subject"))
+	if err != nil {
+		subject = m.Header.Get("Subject")
+	}
+	text, err := plainText(m.Header.Get("Content-Type"), m.Header.Get("Content-Transfer-Encoding"), m.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{Subject: subject, Text: text}, nil
+}
+
+// plainText finds the first text/plain part in r, searching multiparts
+// depth first.
+func plainText(contentType, encoding string, r io.Reader) (string, error) {
+	mediaType, params := "text/plain", map[string]string{}
+	if contentType != "" {
+		var err error
+		if mediaType, params, err = mime.ParseMediaType(contentType); err != nil {
+			return "", nil // unreadable parts are skipped
+		}
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		mr := multipart.NewReader(r, params["boundary"])
+		for {
+			p, err := mr.NextPart()
+			if err == io.EOF {
+				return "", nil
+			}
+			if err != nil {
+				return "", err
+			}
+			// NextPart has already undone quoted-printable.
+			text, err := plainText(p.Header.Get("Content-Type"), p.Header.Get("Content-Transfer-Encoding"), p)
+			if err != nil || text != "" {
+				return text, err
+			}
+		}
+	case mediaType == "text/plain":
+		if strings.EqualFold(strings.TrimSpace(encoding), "base64") {
+			r = base64.NewDecoder(base64.StdEncoding, &unwrap{r: r})
+		}
+		b, err := io.ReadAll(io.LimitReader(r, 4*maxNotes))
+		return string(b), err
+	}
+	return "", nil
+}
+
+// unwrap drops the line breaks of wrapped base64.
+type unwrap struct {
+	r io.Reader
+}
+
+func (u *unwrap) Read(p []byte) (int, error) {
+	n, err := u.r.Read(p)
+	j := 0
+	for _, c := range p[:n] {
+		if c != '\r' && c != '\n' {
+			p[j] = c
+			j++
+		}
+	}
+	if j == 0 && n > 0 && err == nil {
+		return u.Read(p)
+	}
+	return j, err
+}
@@ -0,0 +1,247 @@
+// Package jira mirrors todos to issues in a Jira project and pulls their
+// status back, so work tracked in Jira can be planned here.
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/jobs"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	// Integration is the key issue keys are stored under in Todo.External.
+	Integration = "jira"
+	pushJob     = "jira.push"
+	// Jira status categories; every workflow status belongs to one.
+	categoryDone = "done"
+	categoryNew  = "new"
+)
+
+// Store is the persistence the connector needs.
+type Store interface {
+	GetTodo(ctx context.Context, id bson.ObjectID) (*model.Todo, error)
+	SetExternalID(ctx context.Context, id bson.ObjectID, integration, externalID string) error
+	GetTodoByExternalID(ctx context.Context, integration, externalID string) (*model.Todo, error)
+}
+
+type Connector struct {
+	c      config.Jira
+	store  Store
+	todos  *service.TodoService
+	client *http.Client
+	log    *log.Logger
+}
+
+func New(c config.Jira, s Store, todos *service.TodoService, logger *log.Logger) *Connector {
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/")
+	return &Connector{c: c, store: s, todos: todos, client: &http.Client{Timeout: 15 * time.Second}, log: logger}
+}
+
+// call sends body (if any) as JSON to the Jira REST API and decodes the
+// answer into out (if any).
+func (j *Connector) call(ctx context.Context, method, path string, body, out interface{}) error {
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, j.c.BaseURL+"/rest/api/2"+path, rd)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(j.c.Email, j.c.APIToken)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("jira: %s %s: %s: %s", method, path, resp.Status, bytes.TrimSpace(b))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Subscribe mirrors every created or changed todo through a retried job.
+// Deleting a todo leaves its issue alone; Jira history is not ours to
+// remove.
+func (j *Connector) Subscribe(bus *events.Bus, queue *jobs.Queue) {
+	queue.Register(pushJob, func(ctx context.Context, payload json.RawMessage) error {
+		var id string
+		if err := json.Unmarshal(payload, &id); err != nil {
+			return err
+		}
+		return j.Push(ctx, id)
+	})
+	bus.Subscribe("jira", func(e events.Event) {
+		// Like every connector, Jira serves the default tenant only.
+		if e.Tenant != "" || e.Type != events.TodoCreated && e.Type != events.TodoUpdated {
+			return
+		}
+		if _, err := queue.Enqueue(pushJob, e.TodoID); err != nil {
+			j.log.Printf("jira: queueing %s: %v", e.TodoID, err)
+		}
+	})
+}
+
+type issueFields struct {
+	Summary string `json:"summary"`
+	Status  *struct {
+		StatusCategory struct {
+			Key string `json:"key"`
+		} `json:"statusCategory"`
+	} `json:"status,omitempty"`
+}
+
+// Push creates the todo's issue, or updates its summary and moves it to a
+// done or to-do status to match the todo.
+func (j *Connector) Push(ctx context.Context, todoID string) error {
+	oid, err := store.ParseID(todoID)
+	if err != nil {
+		return err
+	}
+	t, err := j.store.GetTodo(ctx, oid)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	key := t.External[Integration]
+	if key == "" {
+		var created struct {
+			Key string `json:"key"`
+		}
+		err := j.call(ctx, http.MethodPost, "/issue", map[string]interface{}{
+			"fields": map[string]interface{}{
+				"project":   map[string]string{"key": j.c.Project},
+				"issuetype": map[string]string{"name": j.c.IssueType},
+				"summary":   t.Title,
+			},
+		}, &created)
+		if err != nil {
+			return err
+		}
+		if err := j.store.SetExternalID(ctx, t.ID, Integration, created.Key); err != nil {
+			return err
+		}
+		key = created.Key
+	} else {
+		err := j.call(ctx, http.MethodPut, "/issue/"+url.PathEscape(key), map[string]interface{}{
+			"fields": map[string]string{"summary": t.Title},
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	var issue struct {
+		Fields issueFields `json:"fields"`
+	}
+	if err := j.call(ctx, http.MethodGet, "/issue/"+url.PathEscape(key)+"?fields=status", nil, &issue); err != nil {
+		return err
+	}
+	isDone := issue.Fields.Status != nil && issue.Fields.Status.StatusCategory.Key == categoryDone
+	if isDone == t.Completed {
+		return nil
+	}
+	want := categoryNew
+	if t.Completed {
+		want = categoryDone
+	}
+	return j.transition(ctx, key, want)
+}
+
+// transition moves the issue through the first available transition into a
+// status of the given category.
+func (j *Connector) transition(ctx context.Context, key, category string) error {
+	var list struct {
+		Transitions []struct {
+			ID string `json:"id"`
+			To struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	path := "/issue/" + url.PathEscape(key) + "/transitions"
+	if err := j.call(ctx, http.MethodGet, path, nil, &list); err != nil {
+		return err
+	}
+	for _, tr := range list.Transitions {
+		if tr.To.StatusCategory.Key == category {
+			return j.call(ctx, http.MethodPost, path, map[string]interface{}{
+				"transition": map[string]string{"id": tr.ID},
+			}, nil)
+		}
+	}
+	j.log.Printf("jira: %s has no transition to a %q status", key, category)
+	return nil
+}
+
+// Pull completes or reopens todos whose issues changed status in the
+// lookback window.
+func (j *Connector) Pull(ctx context.Context) error {
+	jql := fmt.Sprintf(`project = "%s" AND updated >= "-%dm"`, j.c.Project, int(j.c.Lookback.Minutes()))
+	q := url.Values{"jql": {jql}, "fields": {"status"}, "maxResults": {"100"}}
+	for start := 0; ; {
+		q.Set("startAt", fmt.Sprint(start))
+		var res struct {
+			Total  int `json:"total"`
+			Issues []struct {
+				Key    string      `json:"key"`
+				Fields issueFields `json:"fields"`
+			} `json:"issues"`
+		}
+		if err := j.call(ctx, http.MethodGet, "/search?"+q.Encode(), nil, &res); err != nil {
+			return err
+		}
+		for _, is := range res.Issues {
+			t, err := j.store.GetTodoByExternalID(ctx, Integration, is.Key)
+			if errors.Is(err, store.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			done := is.Fields.Status != nil && is.Fields.Status.StatusCategory.Key == categoryDone
+			if done != t.Completed {
+				if _, err := j.todos.SyncCompleted(ctx, t.ID.Hex(), done); err != nil {
+					return err
+				}
+			}
+		}
+		start += len(res.Issues)
+		if len(res.Issues) == 0 || start >= res.Total {
+			return nil
+		}
+	}
+}
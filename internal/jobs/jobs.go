@@ -0,0 +1,304 @@
+// Package jobs runs background work (deliveries, emails, imports) on an
+// in-process worker pool, retrying failures with exponential backoff.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	mrand "math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+)
+
+// States a job moves through.
+const (
+	StateQueued   = "queued"
+	StateRunning  = "running"
+	StateRetrying = "retrying"
+	StateDone     = "done"
+	StateFailed   = "failed"
+)
+
+// ErrNotFound is returned for unknown job ids.
+var ErrNotFound = errors.New("jobs: not found")
+
+// ErrFull is returned when more jobs are waiting than the queue holds.
+var ErrFull = errors.New("jobs: queue is full")
+
+// queueSize is how many jobs may wait for a worker at once.
+const queueSize = 1024
+
+// HandlerFunc does the work for one kind of job. Returning an error schedules
+// a retry until the job runs out of attempts.
+type HandlerFunc func(ctx context.Context, payload json.RawMessage) error
+
+// Job is a snapshot of a unit of background work.
+type Job struct {
+	ID          string          `json:"id"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	State       string          `json:"state"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	NextRunAt   time.Time       `json:"next_run_at,omitempty"`
+}
+
+type Queue struct {
+	mu          sync.Mutex
+	handlers    map[string]HandlerFunc
+	jobs        map[string]*Job
+	finished    []string // ids of done and failed jobs, oldest first
+	ready       chan string
+	workers     int
+	maxAttempts int
+	backoff     time.Duration
+	timeout     time.Duration
+	history     int
+	log         *log.Logger
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func New(c config.Jobs, logger *log.Logger) *Queue {
+	return &Queue{
+		handlers:    map[string]HandlerFunc{},
+		jobs:        map[string]*Job{},
+		ready:       make(chan string, queueSize),
+		workers:     c.Workers,
+		maxAttempts: c.MaxAttempts,
+		backoff:     c.Backoff,
+		timeout:     c.Timeout,
+		history:     c.History,
+		log:         logger,
+		stop:        make(chan struct{}),
+	}
+}
+
+// Register sets the handler for jobs of the given kind. It must be called
+// before Start.
+func (q *Queue) Register(kind string, h HandlerFunc) {
+	q.handlers[kind] = h
+}
+
+// Enqueue schedules a job; payload is stored as JSON. It fails with ErrFull
+// rather than wait when the queue is full.
+func (q *Queue) Enqueue(kind string, payload interface{}) (string, error) {
+	if _, ok := q.handlers[kind]; !ok {
+		return "", fmt.Errorf("jobs: no handler for %q", kind)
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	id := make([]byte, 8)
+	rand.Read(id)
+	now := time.Now()
+	j := &Job{
+		ID:          hex.EncodeToString(id),
+		Kind:        kind,
+		Payload:     b,
+		State:       StateQueued,
+		MaxAttempts: q.maxAttempts,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	q.mu.Lock()
+	q.jobs[j.ID] = j
+	q.mu.Unlock()
+	if !q.offer(j.ID) {
+		q.mu.Lock()
+		delete(q.jobs, j.ID)
+		q.mu.Unlock()
+		return "", ErrFull
+	}
+	return j.ID, nil
+}
+
+// push queues id for a worker, waiting for room; retries use it.
+func (q *Queue) push(id string) {
+	select {
+	case q.ready <- id:
+	case <-q.stop:
+	}
+}
+
+// offer queues id for a worker if there is room, reporting whether there
+// was.
+func (q *Queue) offer(id string) bool {
+	select {
+	case q.ready <- id:
+		return true
+	default:
+		return false
+	}
+}
+
+// Start launches the workers. They run until Stop is called.
+func (q *Queue) Start() {
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+}
+
+// Stop tells the workers to finish their current job and waits for them, or
+// for ctx to expire. Jobs still queued are dropped.
+func (q *Queue) Stop(ctx context.Context) error {
+	close(q.stop)
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for {
+		select {
+		case <-q.stop:
+			return
+		case id := <-q.ready:
+			q.run(id)
+		}
+	}
+}
+
+func (q *Queue) run(id string) {
+	q.mu.Lock()
+	j, ok := q.jobs[id]
+	if !ok {
+		q.mu.Unlock()
+		return
+	}
+	j.State = StateRunning
+	j.Attempts++
+	j.UpdatedAt = time.Now()
+	j.NextRunAt = time.Time{}
+	h, payload := q.handlers[j.Kind], j.Payload
+	q.mu.Unlock()
+
+	err := q.call(h, payload)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j.UpdatedAt = time.Now()
+	if err == nil {
+		j.State, j.LastError = StateDone, ""
+		q.finish(j.ID)
+		return
+	}
+	j.LastError = err.Error()
+	if j.Attempts >= j.MaxAttempts {
+		j.State = StateFailed
+		q.log.Printf("jobs: %s %s failed after %d attempts: %v", j.Kind, j.ID, j.Attempts, err)
+		q.finish(j.ID)
+		return
+	}
+	wait := q.delay(j.Attempts)
+	j.State = StateRetrying
+	j.NextRunAt = j.UpdatedAt.Add(wait)
+	time.AfterFunc(wait, func() { q.push(id) })
+}
+
+// call runs h with a context ending after the queue's timeout, turning a
+// panic into an ordinary failure.
+func (q *Queue) call(h HandlerFunc, payload json.RawMessage) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), q.timeout)
+	defer cancel()
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			err = fmt.Errorf("panic: %v", rvr)
+		}
+	}()
+	return h(ctx, payload)
+}
+
+// delay is the backoff before retry number attempt: it doubles every time and
+// is jittered by up to half so failed jobs don't retry in lockstep.
+func (q *Queue) delay(attempt int) time.Duration {
+	d := float64(q.backoff) * math.Pow(2, float64(attempt-1))
+	return time.Duration(d/2 + mrand.Float64()*d/2)
+}
+
+// finish records a job as finished and forgets the oldest finished jobs
+// beyond the history limit. q.mu must be held.
+func (q *Queue) finish(id string) {
+	q.finished = append(q.finished, id)
+	for len(q.finished) > q.history {
+		delete(q.jobs, q.finished[0])
+		q.finished = q.finished[1:]
+	}
+}
+
+// List returns the known jobs, newest first, optionally only those in state.
+func (q *Queue) List(state string) []Job {
+	q.mu.Lock()
+	out := make([]Job, 0, len(q.jobs))
+	for _, j := range q.jobs {
+		if state == "" || j.State == state {
+			out = append(out, *j)
+		}
+	}
+	q.mu.Unlock()
+	sort.Slice(out, func(a, b int) bool { return out[a].CreatedAt.After(out[b].CreatedAt) })
+	return out
+}
+
+func (q *Queue) Get(id string) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j, ok := q.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return *j, nil
+}
+
+// Retry requeues a failed job with a fresh set of attempts. Like Enqueue, it
+// fails with ErrFull when the queue is full, leaving the job failed.
+func (q *Queue) Retry(id string) (Job, error) {
+	q.mu.Lock()
+	j, ok := q.jobs[id]
+	if !ok {
+		q.mu.Unlock()
+		return Job{}, ErrNotFound
+	}
+	if j.State != StateFailed {
+		q.mu.Unlock()
+		return *j, fmt.Errorf("jobs: job is %s, only failed jobs can be retried", j.State)
+	}
+	if !q.offer(id) {
+		q.mu.Unlock()
+		return *j, ErrFull
+	}
+	for i, f := range q.finished {
+		if f == id {
+			q.finished = append(q.finished[:i], q.finished[i+1:]...)
+			break
+		}
+	}
+	j.State, j.Attempts, j.UpdatedAt = StateQueued, 0, time.Now()
+	snapshot := *j
+	q.mu.Unlock()
+	return snapshot, nil
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	chimw "github.com/go-chi/chi/middleware"
+)
+
+type accessEntry struct {
+	Time      time.Time `json:"time"`
+	RemoteIP  string    `json:"remote_ip"`
+	Tenant    string    `json:"tenant"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Proto     string    `json:"proto"`
+	Status    int       `json:"status"`
+	Bytes     int       `json:"bytes"`
+	LatencyMS float64   `json:"latency_ms"`
+	Referer   string    `json:"referer"`
+	UserAgent string    `json:"user_agent"`
+}
+
+// AccessLogger writes one line per request in the configured format.
+type AccessLogger struct {
+	mu     sync.Mutex
+	out    io.Writer
+	format string
+	skip   map[string]bool
+	ips    *IPResolver
+	log    *log.Logger
+}
+
+// NewAccessLogger writes access log lines to out; logger gets the errors.
+func NewAccessLogger(c config.AccessLog, ips *IPResolver, out io.Writer, logger *log.Logger) *AccessLogger {
+	skip := map[string]bool{}
+	for _, p := range c.SkipPaths {
+		skip[p] = true
+	}
+	return &AccessLogger{out: out, format: c.Format, skip: skip, ips: ips, log: logger}
+}
+
+type tenantKey struct{}
+
+// recordTenant notes id as the tenant of r for the access log, which runs
+// before the tenant is known.
+func recordTenant(r *http.Request, id string) {
+	if p, ok := r.Context().Value(tenantKey{}).(*string); ok {
+		*p = id
+	}
+}
+
+// Handler logs each request but those to the skipped paths, once it has
+// been answered.
+func (l *AccessLogger) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.skip[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		var id string
+		r = r.WithContext(context.WithValue(r.Context(), tenantKey{}, &id))
+		ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+		defer func() {
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if id == "" {
+				id = "-"
+			}
+			l.write(accessEntry{
+				Time:      start,
+				RemoteIP:  l.ips.ClientIP(r).String(),
+				Tenant:    id,
+				Method:    r.Method,
+				Path:      r.URL.RequestURI(),
+				Proto:     r.Proto,
+				Status:    status,
+				Bytes:     ww.BytesWritten(),
+				LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+				Referer:   r.Referer(),
+				UserAgent: r.UserAgent(),
+			})
+		}()
+		next.ServeHTTP(ww, r)
+	})
+}
+
+func (l *AccessLogger) write(e accessEntry) {
+	var line []byte
+	switch l.format {
+	case "json":
+		b, err := json.Marshal(e)
+		if err != nil {
+			l.log.Printf("access log: %v", err)
+			return
+		}
+		line = append(b, '\n')
+	case "combined":
+		line = []byte(fmt.Sprintf("%s - %s [%s] %q %d %d %q %q\n",
+			e.RemoteIP, e.Tenant, e.Time.Format("02/Jan/2006:15:04:05 -0700"),
+			e.Method+" "+e.Path+" "+e.Proto, e.Status, e.Bytes, e.Referer, e.UserAgent))
+	default:
+		line = []byte(fmt.Sprintf("%s %s %q from %s - %d %dB in %.3fms\n",
+			e.Time.Format("2006/01/02 15:04:05"), e.Tenant, e.Method+" "+e.Path+" "+e.Proto,
+			e.RemoteIP, e.Status, e.Bytes, e.LatencyMS))
+	}
+	l.mu.Lock()
+	l.out.Write(line)
+	l.mu.Unlock()
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"dhruvarora9/personal-todo-golang/internal/render"
+)
+
+// RequireAdmin only lets through requests carrying the configured admin token
+// as a bearer token. With no token configured the admin API is switched off.
+func RequireAdmin(token string, rnd *render.Renderer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				http.NotFound(w, r)
+				return
+			}
+			got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				rnd.Problem(w, r, http.StatusUnauthorized, "A valid admin token is required")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// IPResolver works out the real client address of a request. Forwarding
+// headers are only believed when the direct peer is one of the trusted
+// proxies; otherwise anyone could claim any address.
+type IPResolver struct {
+	trusted []*net.IPNet
+}
+
+// NewIPResolver trusts forwarding headers from the given proxies.
+func NewIPResolver(trustedProxies []string) (*IPResolver, error) {
+	nets, err := parseCIDRs(trustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("trusted_proxies: %w", err)
+	}
+	return &IPResolver{trusted: nets}, nil
+}
+
+// parseCIDRs accepts both CIDR blocks and bare addresses.
+func parseCIDRs(list []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(list))
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %q", s)
+			}
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			s = fmt.Sprintf("%s/%d", ip, bits)
+		}
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (res *IPResolver) isTrusted(ip net.IP) bool {
+	return ip != nil && containsIP(res.trusted, ip)
+}
+
+// ClientIP returns the address of the client that made r. When the request
+// came through trusted proxies, X-Forwarded-For is walked from the right and
+// the first hop we don't trust is taken as the client. A peer on a unix
+// socket, whose RemoteAddr is "@" or empty, can only be a local process
+// such as the reverse proxy, so it is trusted and stands for loopback.
+func (res *IPResolver) ClientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	local := ip == nil
+	if local {
+		ip = net.IPv4(127, 0, 0, 1)
+	}
+	if !local && !res.isTrusted(ip) {
+		return ip
+	}
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := net.ParseIP(strings.TrimSpace(hops[i]))
+			if hop == nil {
+				break
+			}
+			ip = hop
+			if !res.isTrusted(hop) {
+				return hop
+			}
+		}
+		return ip
+	}
+	if xrip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); xrip != nil {
+		return xrip
+	}
+	return ip
+}
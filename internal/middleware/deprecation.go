@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+)
+
+// DeprecationStats counts the requests to one deprecated part of the API.
+type DeprecationStats struct {
+	Path      string     `json:"path"`
+	Param     string     `json:"param,omitempty"`
+	Since     time.Time  `json:"since"`
+	Sunset    *time.Time `json:"sunset,omitempty"`
+	Successor string     `json:"successor,omitempty"`
+	Requests  int64      `json:"requests"`
+	LastUsed  *time.Time `json:"last_used,omitempty"`
+}
+
+type deprecation struct {
+	config.Deprecation
+	requests int64
+	lastUsed time.Time
+}
+
+// Deprecations tells clients which of their requests use deprecated routes
+// or parameters, with the Deprecation (RFC 9745), Sunset (RFC 8594) and
+// Link headers, and counts those requests so an operator can see who still
+// has to move before a sunset.
+type Deprecations struct {
+	mu   sync.Mutex
+	deps []*deprecation
+	now  func() time.Time
+}
+
+// NewDeprecations returns a Deprecations for the deprecations in c, taking
+// the time of each request from now.
+func NewDeprecations(c []config.Deprecation, now func() time.Time) *Deprecations {
+	d := &Deprecations{now: now}
+	for _, dep := range c {
+		d.deps = append(d.deps, &deprecation{Deprecation: dep})
+	}
+	return d
+}
+
+// matches reports whether r uses dep: its path is dep.Path or below it and,
+// if dep names a parameter, its query carries it.
+func (dep *deprecation) matches(r *http.Request) bool {
+	p := strings.TrimSuffix(dep.Path, "/")
+	if r.URL.Path != p && !strings.HasPrefix(r.URL.Path, p+"/") {
+		return false
+	}
+	return dep.Param == "" || r.URL.Query().Has(dep.Param)
+}
+
+// Handler marks the responses to requests using a deprecation, and counts
+// them, before passing the requests to next.
+func (d *Deprecations) Handler(next http.Handler) http.Handler {
+	if len(d.deps) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := d.now()
+		d.mu.Lock()
+		for _, dep := range d.deps {
+			if !dep.matches(r) {
+				continue
+			}
+			dep.requests++
+			dep.lastUsed = now
+			setDeprecated(w.Header(), dep, r)
+		}
+		d.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func setDeprecated(h http.Header, dep *deprecation, r *http.Request) {
+	h.Set("Deprecation", "@"+strconv.FormatInt(dep.Since.Unix(), 10))
+	if !dep.Sunset.IsZero() {
+		h.Set("Sunset", dep.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if succ := dep.Successor; succ != "" {
+		// A successor path stands in for Path, keeping the rest.
+		if strings.HasPrefix(succ, "/") {
+			succ = strings.TrimSuffix(succ, "/") + strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(dep.Path, "/"))
+		}
+		h.Add("Link", "<"+succ+`>; rel="successor-version"`)
+	}
+	if dep.Docs != "" {
+		h.Add("Link", "<"+dep.Docs+`>; rel="deprecation"`)
+	}
+}
+
+// Stats returns the requests counted for each deprecation, in the order
+// they were configured.
+func (d *Deprecations) Stats() []DeprecationStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]DeprecationStats, 0, len(d.deps))
+	for _, dep := range d.deps {
+		s := DeprecationStats{
+			Path:      dep.Path,
+			Param:     dep.Param,
+			Since:     dep.Since,
+			Successor: dep.Successor,
+			Requests:  dep.requests,
+		}
+		if !dep.Sunset.IsZero() {
+			sunset := dep.Sunset
+			s.Sunset = &sunset
+		}
+		if !dep.lastUsed.IsZero() {
+			last := dep.lastUsed
+			s.LastUsed = &last
+		}
+		out = append(out, s)
+	}
+	return out
+}
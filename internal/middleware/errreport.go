@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/version"
+	chimw "github.com/go-chi/chi/middleware"
+)
+
+// ErrorSink receives the errors an operator should hear about: handler panics
+// and requests that ended with a 5xx status.
+type ErrorSink interface {
+	Report(r *http.Request, err error, stack []byte)
+}
+
+// NewErrorSink reports to Sentry when a DSN is configured and to the log
+// otherwise.
+func NewErrorSink(c config.ErrorReporting, logger *log.Logger) (ErrorSink, error) {
+	if c.SentryDSN == "" {
+		return logSink{log: logger}, nil
+	}
+	return newSentrySink(c.SentryDSN, c.Environment, logger)
+}
+
+type logSink struct {
+	log *log.Logger
+}
+
+func (s logSink) Report(r *http.Request, err error, stack []byte) {
+	s.log.Printf("error: %s %s: %v\n%s", r.Method, r.URL.Path, err, stack)
+}
+
+// sentrySink posts events to Sentry's store endpoint. Delivery happens in the
+// background and failures are only logged; reporting must never slow down or
+// break the request that triggered it.
+type sentrySink struct {
+	endpoint    string
+	auth        string
+	environment string
+	client      *http.Client
+	log         *log.Logger
+}
+
+func newSentrySink(dsn, environment string, logger *log.Logger) (*sentrySink, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sentry_dsn: %w", err)
+	}
+	project := strings.TrimPrefix(u.Path, "/")
+	if u.User == nil || project == "" {
+		return nil, fmt.Errorf("sentry_dsn: expected https://<key>@<host>/<project>")
+	}
+	return &sentrySink{
+		endpoint: fmt.Sprintf("%s://%s/api/%s/store/", u.Scheme, u.Host, project),
+		auth: fmt.Sprintf("Sentry sentry_version=7, sentry_client=todo/%s, sentry_key=%s",
+			version.Version, u.User.Username()),
+		environment: environment,
+		client:      &http.Client{Timeout: 5 * time.Second},
+		log:         logger,
+	}, nil
+}
+
+func (s *sentrySink) Report(r *http.Request, err error, stack []byte) {
+	id := make([]byte, 16)
+	rand.Read(id)
+	event := map[string]interface{}{
+		"event_id":    hex.EncodeToString(id),
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		"level":       "error",
+		"platform":    "go",
+		"release":     version.Version,
+		"environment": s.environment,
+		"exception": map[string]interface{}{
+			"values": []map[string]interface{}{{
+				"type":  fmt.Sprintf("%T", err),
+				"value": err.Error(),
+			}},
+		},
+		"request": map[string]interface{}{
+			"method":       r.Method,
+			"url":          r.URL.String(),
+			"query_string": r.URL.RawQuery,
+			"headers": map[string]string{
+				"User-Agent": r.UserAgent(),
+				"Referer":    r.Referer(),
+			},
+		},
+	}
+	if len(stack) > 0 {
+		event["extra"] = map[string]string{"stack": string(stack)}
+	}
+	go s.send(event)
+}
+
+func (s *sentrySink) send(event map[string]interface{}) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		s.log.Printf("sentry: %v", err)
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(b))
+	if err != nil {
+		s.log.Printf("sentry: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", s.auth)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.log.Printf("sentry: %v", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		s.log.Printf("sentry: unexpected status %s", resp.Status)
+	}
+}
+
+type errorKey struct{}
+
+// RecordError attaches err to the request so that, if the response ends up
+// with a 5xx status, the sink gets the real cause rather than just the code.
+func RecordError(r *http.Request, err error) {
+	if p, ok := r.Context().Value(errorKey{}).(*error); ok {
+		*p = err
+	}
+}
+
+// ReportErrors recovers handler panics and reports them, together with any
+// request that finished with a 5xx status, to sink.
+func ReportErrors(sink ErrorSink, rnd *render.Renderer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var cause error
+			r = r.WithContext(context.WithValue(r.Context(), errorKey{}, &cause))
+			ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+			defer func() {
+				if rvr := recover(); rvr != nil {
+					if rvr == http.ErrAbortHandler {
+						panic(rvr)
+					}
+					sink.Report(r, fmt.Errorf("panic: %v", rvr), debug.Stack())
+					if ww.Status() == 0 {
+						rnd.Problem(ww, r, http.StatusInternalServerError, "Internal server error")
+					}
+					return
+				}
+				if ww.Status() >= 500 {
+					if cause == nil {
+						cause = fmt.Errorf("%s %s returned %d", r.Method, r.URL.Path, ww.Status())
+					}
+					sink.Report(r, cause, nil)
+				}
+			}()
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"dhruvarora9/personal-todo-golang/internal/render"
+)
+
+// VerifyGitHub rejects webhook deliveries whose X-Hub-Signature-256 doesn't
+// match the body signed with the webhook secret.
+func VerifyGitHub(secret string, rnd *render.Renderer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(io.LimitReader(r.Body, 25<<20))
+			if err != nil {
+				rnd.Problem(w, r, http.StatusBadRequest, "The request body could not be read")
+				return
+			}
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(body)
+			want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+			if !hmac.Equal([]byte(want), []byte(r.Header.Get("X-Hub-Signature-256"))) {
+				rnd.Problem(w, r, http.StatusUnauthorized, "The request signature is invalid")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
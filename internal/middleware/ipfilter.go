@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/render"
+)
+
+// IPFilter rejects clients outside the allow list or inside the deny list.
+type IPFilter struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+	ips   *IPResolver
+	rnd   *render.Renderer
+}
+
+// NewIPFilter returns a filter for the CIDRs in c, failing on a bad one.
+func NewIPFilter(c config.IPFilter, ips *IPResolver, rnd *render.Renderer) (*IPFilter, error) {
+	allow, err := parseCIDRs(c.Allow)
+	if err != nil {
+		return nil, fmt.Errorf("ip_filter.allow: %w", err)
+	}
+	deny, err := parseCIDRs(c.Deny)
+	if err != nil {
+		return nil, fmt.Errorf("ip_filter.deny: %w", err)
+	}
+	return &IPFilter{allow: allow, deny: deny, ips: ips, rnd: rnd}, nil
+}
+
+func (f *IPFilter) permitted(ip net.IP) bool {
+	if ip == nil || containsIP(f.deny, ip) {
+		return false
+	}
+	return len(f.allow) == 0 || containsIP(f.allow, ip)
+}
+
+// Handler answers 403 to clients the filter does not permit.
+func (f *IPFilter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !f.permitted(f.ips.ClientIP(r)) {
+			f.rnd.Problem(w, r, http.StatusForbidden, "Access denied")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
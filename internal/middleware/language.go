@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+)
+
+// Language puts the locale the client asks for in Accept-Language in the
+// request context, for messages and pages to be written in. Tenants that
+// chose a locale override it later on.
+func Language(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Language")
+		locale := i18n.MatchAccept(strings.Join(r.Header.Values("Accept-Language"), ","))
+		if locale == "" {
+			locale = i18n.Default
+		}
+		next.ServeHTTP(w, r.WithContext(i18n.NewContext(r.Context(), locale)))
+	})
+}
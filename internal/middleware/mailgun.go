@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/render"
+)
+
+// mailgunMaxAge is how old a Mailgun webhook may be.
+const mailgunMaxAge = 15 * time.Minute
+
+// VerifyMailgun rejects webhook posts whose signature field doesn't match
+// their timestamp and token signed with the webhook signing key, or that
+// are too old. It leaves the form parsed.
+func VerifyMailgun(key string, now func() time.Time, rnd *render.Renderer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseMultipartForm(25 << 20); err != nil && err != http.ErrNotMultipart {
+				rnd.Problem(w, r, http.StatusBadRequest, "The request body is not a valid form")
+				return
+			}
+			ts := r.PostForm.Get("timestamp")
+			sec, err := strconv.ParseInt(ts, 10, 64)
+			if err != nil || now().Sub(time.Unix(sec, 0)).Abs() > mailgunMaxAge {
+				rnd.Problem(w, r, http.StatusUnauthorized, "The request timestamp is missing or too old")
+				return
+			}
+			mac := hmac.New(sha256.New, []byte(key))
+			mac.Write([]byte(ts + r.PostForm.Get("token")))
+			want := hex.EncodeToString(mac.Sum(nil))
+			if !hmac.Equal([]byte(want), []byte(r.PostForm.Get("signature"))) {
+				rnd.Problem(w, r, http.StatusUnauthorized, "The request signature is invalid")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/render"
+)
+
+// Maintenance, while enabled, turns away every request that could change data
+// so migrations and restores can run against a quiet database. Reads and the
+// admin API keep working.
+type Maintenance struct {
+	mu         sync.RWMutex
+	enabled    bool
+	message    string
+	retryAfter time.Duration
+	rnd        *render.Renderer
+}
+
+// NewMaintenance returns the mode as c starts it; Set changes it later.
+func NewMaintenance(c config.Maintenance, rnd *render.Renderer) *Maintenance {
+	return &Maintenance{
+		enabled:    c.Enabled,
+		message:    c.Message,
+		retryAfter: c.RetryAfter,
+		rnd:        rnd,
+	}
+}
+
+// State returns the current settings.
+func (m *Maintenance) State() (enabled bool, message string, retryAfter time.Duration) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled, m.message, m.retryAfter
+}
+
+// Set switches maintenance mode on or off. An empty message or zero
+// retryAfter keeps the current value.
+func (m *Maintenance) Set(enabled bool, message string, retryAfter time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enabled = enabled
+	if message != "" {
+		m.message = message
+	}
+	if retryAfter > 0 {
+		m.retryAfter = retryAfter
+	}
+}
+
+func isReadOnly(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// Handler answers 503 with Retry-After to writes while the mode is on.
+func (m *Maintenance) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isReadOnly(r.Method) || strings.HasPrefix(r.URL.Path, "/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		enabled, message, retryAfter := m.State()
+		if !enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+		m.rnd.Problem(w, r, http.StatusServiceUnavailable, message)
+	})
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/render"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter is a token bucket limiter: each key may burst up to Burst
+// requests and then gets RPS new tokens per second.
+type RateLimiter struct {
+	mu        sync.Mutex
+	rps       float64
+	burst     float64
+	keyBy     string
+	ips       *IPResolver
+	rnd       *render.Renderer
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewRateLimiter returns a limiter keyed by client IP, or global, as c says.
+func NewRateLimiter(c config.RateLimit, ips *IPResolver, rnd *render.Renderer) *RateLimiter {
+	return &RateLimiter{
+		rps:       c.RPS,
+		burst:     float64(c.Burst),
+		keyBy:     c.KeyBy,
+		ips:       ips,
+		rnd:       rnd,
+		buckets:   map[string]*bucket{},
+		lastSweep: time.Now(),
+	}
+}
+
+// take spends one token from key's bucket. It returns whether the request is
+// allowed, the tokens left and how long until the next token is available.
+func (l *RateLimiter) take(key string, now time.Time) (bool, float64, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rps)
+	b.last = now
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.rps * float64(time.Second))
+		return false, b.tokens, wait
+	}
+	b.tokens--
+	return true, b.tokens, 0
+}
+
+// sweep drops buckets that have been idle long enough to refill completely,
+// so one-off clients don't pile up in memory.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	full := time.Duration(l.burst / l.rps * float64(time.Second))
+	for k, b := range l.buckets {
+		if now.Sub(b.last) > full {
+			delete(l.buckets, k)
+		}
+	}
+	l.lastSweep = now
+}
+
+func (l *RateLimiter) key(r *http.Request) string {
+	if l.keyBy == "global" {
+		return ""
+	}
+	return l.ips.ClientIP(r).String()
+}
+
+// Handler answers 429 with Retry-After once the client's bucket is empty.
+func (l *RateLimiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, left, wait := l.take(l.key(r), time.Now())
+		reset := (l.burst - left) / l.rps
+
+		h := w.Header()
+		h.Set("X-RateLimit-Limit", strconv.Itoa(int(l.burst)))
+		h.Set("X-RateLimit-Remaining", strconv.Itoa(int(left)))
+		h.Set("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(reset))))
+		if !ok {
+			h.Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			l.rnd.Problem(w, r, http.StatusTooManyRequests, "Too many requests, slow down")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
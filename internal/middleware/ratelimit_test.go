@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/render"
+)
+
+func TestRateLimiterTake(t *testing.T) {
+	// A burst of 2, then one token every 2 seconds.
+	l := NewRateLimiter(config.RateLimit{RPS: 0.5, Burst: 2}, nil, nil)
+	start := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		at       time.Duration
+		wantOK   bool
+		wantLeft float64
+		wantWait time.Duration
+	}{
+		{0, true, 1, 0},
+		{0, true, 0, 0},
+		{0, false, 0, 2 * time.Second},
+		{time.Second, false, 0.5, time.Second},
+		{2 * time.Second, true, 0, 0},
+		{2500 * time.Millisecond, false, 0.25, 1500 * time.Millisecond},
+		// Idle time refills no more than the burst.
+		{30 * time.Second, true, 1, 0},
+	}
+	for _, tt := range tests {
+		ok, left, wait := l.take("192.0.2.1", start.Add(tt.at))
+		if ok != tt.wantOK || left != tt.wantLeft || wait != tt.wantWait {
+			t.Errorf("take at %v = %v, %v, %v, want %v, %v, %v", tt.at, ok, left, wait, tt.wantOK, tt.wantLeft, tt.wantWait)
+		}
+	}
+}
+
+func TestRateLimiterHeaders(t *testing.T) {
+	// One token every 1000 seconds, shared by every client.
+	l := NewRateLimiter(config.RateLimit{RPS: 0.001, Burst: 1, KeyBy: "global"}, nil, render.New(nil, render.EnvelopeData))
+	h := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	tests := []struct {
+		wantStatus     int
+		wantRemaining  string
+		wantReset      string
+		wantRetryAfter string
+	}{
+		{http.StatusOK, "0", "1000", ""},
+		{http.StatusTooManyRequests, "0", "1000", "1000"},
+	}
+	for i, tt := range tests {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/todo", nil))
+		got := w.Header()
+		if w.Code != tt.wantStatus || got.Get("X-RateLimit-Remaining") != tt.wantRemaining ||
+			got.Get("X-RateLimit-Reset") != tt.wantReset || got.Get("Retry-After") != tt.wantRetryAfter {
+			t.Errorf("request %d = %d, remaining %q, reset %q, Retry-After %q, want %d, %q, %q, %q", i+1,
+				w.Code, got.Get("X-RateLimit-Remaining"), got.Get("X-RateLimit-Reset"), got.Get("Retry-After"),
+				tt.wantStatus, tt.wantRemaining, tt.wantReset, tt.wantRetryAfter)
+		}
+	}
+}
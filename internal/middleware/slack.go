@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/render"
+)
+
+// slackMaxSkew is how old a signed Slack request may be before it is
+// treated as a replay.
+const slackMaxSkew = 5 * time.Minute
+
+// VerifySlack rejects requests that aren't signed with the Slack app's
+// signing secret (X-Slack-Signature over "v0:timestamp:body").
+func VerifySlack(secret string, now func() time.Time, rnd *render.Renderer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ts := r.Header.Get("X-Slack-Request-Timestamp")
+			sec, err := strconv.ParseInt(ts, 10, 64)
+			if err != nil || now().Sub(time.Unix(sec, 0)).Abs() > slackMaxSkew {
+				rnd.Problem(w, r, http.StatusUnauthorized, "The request timestamp is missing or too old")
+				return
+			}
+			body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+			if err != nil {
+				rnd.Problem(w, r, http.StatusBadRequest, "The request body could not be read")
+				return
+			}
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write([]byte("v0:" + ts + ":"))
+			mac.Write(body)
+			want := "v0=" + hex.EncodeToString(mac.Sum(nil))
+			if !hmac.Equal([]byte(want), []byte(r.Header.Get("X-Slack-Signature"))) {
+				rnd.Problem(w, r, http.StatusUnauthorized, "The request signature is invalid")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+)
+
+// Tenancy puts the tenant each request names, by header or subdomain as c
+// says, into its context and notes it for the access log. Requests without
+// a valid tenant are refused rather than falling back to the default one.
+func Tenancy(c config.Tenancy, rnd *render.Renderer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var id string
+			switch c.Mode {
+			case "header":
+				id = strings.TrimSpace(r.Header.Get(c.Header))
+			case "subdomain":
+				id = subdomain(r.Host, c.Domain)
+			}
+			if !tenant.Valid(id) {
+				rnd.Problem(w, r, http.StatusBadRequest, "The request does not name a valid tenant")
+				return
+			}
+			recordTenant(r, id)
+			next.ServeHTTP(w, r.WithContext(tenant.NewContext(r.Context(), id)))
+		})
+	}
+}
+
+// subdomain returns the label host has in front of domain, or "" if host
+// isn't a direct subdomain of it.
+func subdomain(host, domain string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	suffix := "." + strings.ToLower(domain)
+	if !strings.HasSuffix(host, suffix) {
+		return ""
+	}
+	label := strings.TrimSuffix(host, suffix)
+	if strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"sort"
+
+	"dhruvarora9/personal-todo-golang/internal/render"
+)
+
+// VerifyTwilio rejects webhook requests without a valid X-Twilio-Signature.
+// Twilio signs the URL it was configured with, so that URL is passed in
+// rather than rebuilt from a request that may have come through a proxy.
+func VerifyTwilio(authToken, webhookURL string, rnd *render.Renderer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseForm(); err != nil {
+				rnd.Problem(w, r, http.StatusBadRequest, "The request body is not a valid form")
+				return
+			}
+			// The signed string is the URL followed by every POST
+			// parameter name and value, sorted by name.
+			keys := make([]string, 0, len(r.PostForm))
+			for k := range r.PostForm {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			mac := hmac.New(sha1.New, []byte(authToken))
+			mac.Write([]byte(webhookURL))
+			for _, k := range keys {
+				for _, v := range r.PostForm[k] {
+					mac.Write([]byte(k + v))
+				}
+			}
+			want := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+			if !hmac.Equal([]byte(want), []byte(r.Header.Get("X-Twilio-Signature"))) {
+				rnd.Problem(w, r, http.StatusUnauthorized, "The request signature is invalid")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
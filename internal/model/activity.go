@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Activity is one entry of the activity feed: something that happened to
+// a todo.
+type Activity struct {
+	ID       bson.ObjectID `bson:"_id,omitempty"`
+	TenantID string        `bson:"tenant_id,omitempty"`
+	// Type is the event type, such as todo.completed.
+	Type   string `bson:"type"`
+	TodoID string `bson:"todo_id"`
+	// Title and List are the todo's as of the event; a deletion leaves
+	// them empty.
+	Title string    `bson:"title,omitempty"`
+	List  string    `bson:"list,omitempty"`
+	At    time.Time `bson:"at"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Custom field types.
+const (
+	FieldText   = "text"
+	FieldNumber = "number"
+	// FieldDate values are stored as YYYY-MM-DD, which sorts and compares
+	// as the dates do.
+	FieldDate   = "date"
+	FieldSelect = "select"
+)
+
+// FieldTypes lists every custom field type.
+var FieldTypes = []string{FieldText, FieldNumber, FieldDate, FieldSelect}
+
+// FieldDef defines a custom field the todos on one list may set, kept in
+// Todo.Fields under Name.
+type FieldDef struct {
+	ID       bson.ObjectID `bson:"_id,omitempty"`
+	TenantID string        `bson:"tenant_id,omitempty"`
+	// List is the list the field belongs to; empty for the default list.
+	List string `bson:"list"`
+	Name string `bson:"name"`
+	Type string `bson:"type"`
+	// Options are the values a select field allows.
+	Options   []string  `bson:"options,omitempty"`
+	CreatedAt time.Time `bson:"created_at"`
+}
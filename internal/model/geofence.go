@@ -0,0 +1,24 @@
+package model
+
+// Geofence is a circle on the map: a todo carrying one is brought up when
+// its owner is inside it.
+type Geofence struct {
+	Lat          float64 `bson:"lat"`
+	Lng          float64 `bson:"lng"`
+	RadiusMeters int     `bson:"radius_m"`
+}
+
+// GeoPoint is a GeoJSON point, which MongoDB can index for distance
+// queries.
+type GeoPoint struct {
+	Type        string     `bson:"type"`
+	Coordinates [2]float64 `bson:"coordinates"`
+}
+
+// Point is the centre of g as a GeoPoint, nil if g is.
+func (g *Geofence) Point() *GeoPoint {
+	if g == nil {
+		return nil
+	}
+	return &GeoPoint{Type: "Point", Coordinates: [2]float64{g.Lng, g.Lat}}
+}
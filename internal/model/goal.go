@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Goal is an outcome todos work towards, such as "Ship v1 by March". Its
+// progress is worked out from the todos attached to it.
+type Goal struct {
+	ID          bson.ObjectID `bson:"_id,omitempty"`
+	TenantID    string        `bson:"tenant_id,omitempty"`
+	Name        string        `bson:"name"`
+	Description string        `bson:"description,omitempty"`
+	DueAt       *time.Time    `bson:"due_at,omitempty"`
+	CreatedAt   time.Time     `bson:"created_at"`
+	UpdatedAt   time.Time     `bson:"updated_at"`
+}
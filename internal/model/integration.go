@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Integration routes todo events to a destination: a notification channel
+// (webhook, slack, email, ...) and an address on it, such as a URL.
+type Integration struct {
+	ID       bson.ObjectID `bson:"_id,omitempty"`
+	TenantID string        `bson:"tenant_id,omitempty"`
+	Name     string        `bson:"name"`
+	Channel  string        `bson:"channel"`
+	Address  string        `bson:"address"`
+	// Events limits it to these event types; empty means all.
+	Events []string `bson:"events"`
+	// List limits it to todos on one list; empty means every list.
+	List string `bson:"list,omitempty"`
+	// Template, a text/template executed with the event, replaces the
+	// channel's default message or payload.
+	Template  string    `bson:"template,omitempty"`
+	Enabled   bool      `bson:"enabled"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// Wants reports whether the integration covers an event of the given type
+// about a todo on list.
+func (in Integration) Wants(eventType, list string) bool {
+	if !in.Enabled || (in.List != "" && in.List != list) {
+		return false
+	}
+	if len(in.Events) == 0 {
+		return true
+	}
+	for _, e := range in.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// GoogleAccount is the connected Google Calendar account.
+type GoogleAccount struct {
+	RefreshToken string    `bson:"refresh_token"`
+	AccessToken  string    `bson:"access_token"`
+	Expiry       time.Time `bson:"expiry"`
+	// SyncToken resumes the incremental event listing where the last
+	// sync stopped.
+	SyncToken   string    `bson:"sync_token"`
+	ConnectedAt time.Time `bson:"connected_at"`
+}
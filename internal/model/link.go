@@ -0,0 +1,20 @@
+package model
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+
+// Link types, read from the todo holding the link: it relates to,
+// duplicates or follows the other.
+const (
+	LinkRelatesTo  = "relates_to"
+	LinkDuplicates = "duplicates"
+	LinkFollows    = "follows"
+)
+
+// LinkTypes lists every link type.
+var LinkTypes = []string{LinkRelatesTo, LinkDuplicates, LinkFollows}
+
+// Link relates a todo to another.
+type Link struct {
+	Type   string        `bson:"type"`
+	TodoID bson.ObjectID `bson:"todo_id"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Pomodoro states.
+const (
+	PomodoroRunning     = "running"
+	PomodoroCompleted   = "completed"
+	PomodoroInterrupted = "interrupted"
+)
+
+// Pomodoro is one focused work session on a todo, followed by a break.
+type Pomodoro struct {
+	ID           bson.ObjectID `bson:"_id,omitempty"`
+	TenantID     string        `bson:"tenant_id,omitempty"`
+	TodoID       bson.ObjectID `bson:"todo_id"`
+	Minutes      int           `bson:"minutes"`
+	BreakMinutes int           `bson:"break_minutes"`
+	State        string        `bson:"state"`
+	Start        time.Time     `bson:"start"`
+	End          *time.Time    `bson:"end,omitempty"`
+	// Running is set while State is running; a todo has at most one
+	// running session.
+	Running bool `bson:"running,omitempty"`
+}
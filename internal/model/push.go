@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// PushSubscription is a browser's Web Push subscription, in the shape
+// PushSubscription.toJSON() produces. Endpoint identifies it.
+type PushSubscription struct {
+	Endpoint  string    `bson:"_id" json:"endpoint"`
+	TenantID  string    `bson:"tenant_id,omitempty" json:"-"`
+	Keys      PushKeys  `bson:"keys" json:"keys"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+type PushKeys struct {
+	P256dh string `bson:"p256dh" json:"p256dh"`
+	Auth   string `bson:"auth" json:"auth"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Settings are a tenant's preferences. A tenant that never saved any has
+// the zero value.
+type Settings struct {
+	TenantID string `bson:"tenant_id,omitempty"`
+	// Locale is the language messages, pages and email are in; empty
+	// means the one each request asks for, and English for email.
+	Locale string `bson:"locale,omitempty"`
+	// TimeZone is the IANA name of the zone dates are read and shown in;
+	// empty means the server's.
+	TimeZone  string    `bson:"time_zone,omitempty"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Share gives anyone holding its token a read-only view of one todo or,
+// when TodoID is zero, of a list.
+type Share struct {
+	ID       bson.ObjectID `bson:"_id,omitempty"`
+	TenantID string        `bson:"tenant_id,omitempty"`
+	// Token is random and unguessable; it is the share's public name.
+	Token  string        `bson:"token"`
+	TodoID bson.ObjectID `bson:"todo_id,omitempty"`
+	List   string        `bson:"list,omitempty"`
+	// ExpiresAt, if set, is when the share stops working; the database
+	// deletes it soon after.
+	ExpiresAt *time.Time `bson:"expires_at,omitempty"`
+	CreatedAt time.Time  `bson:"created_at"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// SmartList is a saved, named filter and sort over the todos, such as
+// "This week" or "Waiting on others". Its todos are worked out each time
+// it is read.
+type SmartList struct {
+	ID       bson.ObjectID `bson:"_id,omitempty"`
+	TenantID string        `bson:"tenant_id,omitempty"`
+	Name     string        `bson:"name"`
+	// Tags, AnyTags and NotTags select todos carrying all, any and none
+	// of them.
+	Tags    []string `bson:"tags,omitempty"`
+	AnyTags []string `bson:"any_tags,omitempty"`
+	NotTags []string `bson:"not_tags,omitempty"`
+	List    string   `bson:"list,omitempty"`
+	// Completed, if set, keeps only todos done or not done.
+	Completed *bool `bson:"completed,omitempty"`
+	// DueWithinDays, if set, keeps only todos due within that many days
+	// of the time the list is read, overdue ones included.
+	DueWithinDays *int      `bson:"due_within_days,omitempty"`
+	Sort          string    `bson:"sort,omitempty"`
+	CreatedAt     time.Time `bson:"created_at"`
+	UpdatedAt     time.Time `bson:"updated_at"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Snooze records a todo being put off.
+type Snooze struct {
+	At time.Time `bson:"at"`
+	// From is the due date before, nil if the todo had none.
+	From  *time.Time `bson:"from,omitempty"`
+	Until time.Time  `bson:"until"`
+}
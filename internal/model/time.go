@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// TimeEntry is one stretch of time tracked against a todo.
+type TimeEntry struct {
+	ID       bson.ObjectID `bson:"_id,omitempty"`
+	TenantID string        `bson:"tenant_id,omitempty"`
+	TodoID   bson.ObjectID `bson:"todo_id"`
+	// Tags are the todo's when the timer started, for reports by tag.
+	Tags  []string   `bson:"tags,omitempty"`
+	Start time.Time  `bson:"start"`
+	End   *time.Time `bson:"end,omitempty"`
+	// Running is set until the timer stops; a todo has at most one
+	// running entry.
+	Running bool `bson:"running,omitempty"`
+}
+
+// Duration is how long the entry ran, up to now if it still runs.
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	if e.End != nil {
+		return e.End.Sub(e.Start)
+	}
+	return now.Sub(e.Start)
+}
@@ -0,0 +1,92 @@
+// Package model defines the documents kept in the database.
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Statuses a todo moves through, as on a kanban board.
+const (
+	StatusBacklog    = "backlog"
+	StatusTodo       = "todo"
+	StatusInProgress = "in_progress"
+	StatusDone       = "done"
+	StatusCancelled  = "cancelled"
+)
+
+// Statuses lists every status in board order.
+var Statuses = []string{StatusBacklog, StatusTodo, StatusInProgress, StatusDone, StatusCancelled}
+
+type Todo struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	Title     string        `bson:"title"`
+	Completed bool          `bson:"completed"`
+	// CompletedAt is when the todo was last completed; nil while open and
+	// for todos completed before it was recorded.
+	CompletedAt *time.Time `bson:"completed_at,omitempty"`
+	// Status is one of Statuses; Completed is set exactly when it is
+	// done. Todos saved before statuses existed have none; see
+	// CurrentStatus.
+	Status string `bson:"status,omitempty"`
+	// List groups todos; empty means the default list.
+	List  string     `bson:"list,omitempty"`
+	DueAt *time.Time `bson:"due_at,omitempty"`
+	// Tags are lowercase and unique.
+	Tags []string `bson:"tags,omitempty"`
+	// Notes are free text about the todo.
+	Notes string `bson:"notes,omitempty"`
+	// EstimateMinutes is the expected effort; 0 means no estimate.
+	EstimateMinutes int `bson:"estimate_minutes,omitempty"`
+	// Fields holds the values of the custom fields of the todo's list, by
+	// name: strings for text, select and date fields, float64 for numbers.
+	Fields map[string]interface{} `bson:"fields,omitempty"`
+	// BlockedBy are the todos that must be done before this one can be.
+	BlockedBy []bson.ObjectID `bson:"blocked_by,omitempty"`
+	// TrackedSeconds is the time tracked against the todo by stopped
+	// timers.
+	TrackedSeconds int64 `bson:"tracked_seconds,omitempty"`
+	// GoalID is the goal the todo is attached to, if any.
+	GoalID bson.ObjectID `bson:"goal_id,omitempty"`
+	// Links relate the todo to others; the others' links to it are found
+	// by query.
+	Links []Link `bson:"links,omitempty"`
+	// Geofence, if set, is where the todo is to be done.
+	Geofence *Geofence `bson:"geofence,omitempty"`
+	// Location is the centre of Geofence; the store keeps it in step.
+	Location *GeoPoint `bson:"location,omitempty"`
+	// Snoozes are the latest times the todo was put off, oldest first.
+	Snoozes []Snooze `bson:"snoozes,omitempty"`
+	// GoogleEventID links the todo to its Google Calendar event.
+	GoogleEventID string `bson:"google_event_id,omitempty"`
+	// Slug is the todo's short name in links, such as /t/k7q2xm; most
+	// todos have none until one is asked for.
+	Slug string `bson:"slug,omitempty"`
+	// External holds the todo's ID in other systems, by integration name
+	// (for example "github": "owner/repo#12").
+	External map[string]string `bson:"external,omitempty"`
+	// Trigrams index the title for typo-tolerant search; the store keeps
+	// them in step with Title.
+	Trigrams []string `bson:"trigrams,omitempty"`
+	// TenantID is empty for the default tenant.
+	TenantID string `bson:"tenant_id,omitempty"`
+	// Version counts the writes to the editable fields, so a write made
+	// against a copy can tell whether the todo changed since. Todos saved
+	// before it existed have none, which reads as 0.
+	Version   int64     `bson:"version"`
+	CreatedAt time.Time `bson:"createAt"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// CurrentStatus is the todo's status, worked out from Completed for todos
+// saved without one.
+func (t Todo) CurrentStatus() string {
+	switch {
+	case t.Status != "":
+		return t.Status
+	case t.Completed:
+		return StatusDone
+	}
+	return StatusTodo
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Tombstone records that a todo was deleted or archived, so that sync
+// clients holding it learn it is gone.
+type Tombstone struct {
+	// TodoID is the deleted todo's id, which a todo never reuses.
+	TodoID    bson.ObjectID `bson:"_id"`
+	TenantID  string        `bson:"tenant_id,omitempty"`
+	DeletedAt time.Time     `bson:"deleted_at"`
+}
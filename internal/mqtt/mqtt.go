@@ -0,0 +1,285 @@
+// Package mqtt publishes todo events to an MQTT broker, so home automation
+// can react when a chore is added or done. It speaks just enough MQTT
+// 3.1.1 to publish: CONNECT, PUBLISH at QoS 0 or 1, and DISCONNECT.
+package mqtt
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/events"
+)
+
+// defaultTopics are published when the config names none.
+var defaultTopics = map[string]string{
+	events.TodoCreated:   "todos/created",
+	events.TodoCompleted: "todos/completed",
+}
+
+const timeout = 10 * time.Second
+
+// Publisher keeps one connection to the broker, opened on the first event
+// and again after it breaks.
+type Publisher struct {
+	c      config.MQTT
+	topics map[string]string
+	log    *log.Logger
+
+	mu     sync.Mutex
+	conn   net.Conn
+	r      *bufio.Reader
+	nextID uint16
+}
+
+// New returns a publisher for c, failing on topics for unknown events.
+func New(c config.MQTT, logger *log.Logger) (*Publisher, error) {
+	topics := c.Topics
+	if len(topics) == 0 {
+		topics = defaultTopics
+	}
+	for event := range topics {
+		if !known(event) {
+			return nil, fmt.Errorf("mqtt.topics: unknown event type %q", event)
+		}
+	}
+	return &Publisher{c: c, topics: topics, log: logger}, nil
+}
+
+func known(event string) bool {
+	for _, t := range events.Types {
+		if t == event {
+			return true
+		}
+	}
+	return false
+}
+
+// message is the JSON payload of a publish.
+type message struct {
+	Type   string    `json:"type"`
+	TodoID string    `json:"todo_id"`
+	Title  string    `json:"title,omitempty"`
+	List   string    `json:"list,omitempty"`
+	Done   bool      `json:"done"`
+	At     time.Time `json:"at"`
+}
+
+// Subscribe publishes the default tenant's events that have a topic, as
+// every connector serves the default tenant only.
+func (p *Publisher) Subscribe(bus *events.Bus) {
+	bus.Subscribe("mqtt", func(e events.Event) {
+		topic, ok := p.topics[e.Type]
+		if !ok || e.Tenant != "" {
+			return
+		}
+		m := message{Type: e.Type, TodoID: e.TodoID, At: e.At}
+		if e.Todo != nil {
+			m.Title, m.List, m.Done = e.Todo.Title, e.Todo.List, e.Todo.Completed
+		}
+		payload, err := json.Marshal(m)
+		if err == nil {
+			err = p.Publish(topic, payload)
+		}
+		if err != nil {
+			p.log.Printf("mqtt: publishing %s for %s: %v", e.Type, e.TodoID, err)
+		}
+	})
+}
+
+// Publish sends payload to topic, reconnecting once if the connection has
+// broken.
+func (p *Publisher) Publish(topic string, payload []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for attempt := 0; ; attempt++ {
+		err := p.publish(topic, payload)
+		if err == nil {
+			return nil
+		}
+		p.drop()
+		if attempt == 1 {
+			return err
+		}
+	}
+}
+
+// Close disconnects from the broker.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		return nil
+	}
+	p.conn.SetWriteDeadline(time.Now().Add(timeout))
+	_, err := p.conn.Write([]byte{0xe0, 0})
+	p.drop()
+	return err
+}
+
+func (p *Publisher) drop() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn, p.r = nil, nil
+	}
+}
+
+func (p *Publisher) publish(topic string, payload []byte) error {
+	if p.conn == nil {
+		if err := p.connect(); err != nil {
+			return err
+		}
+	}
+	header := byte(0x30) | byte(p.c.QoS)<<1
+	if p.c.Retain {
+		header |= 0x01
+	}
+	body := appendString(nil, topic)
+	var id uint16
+	if p.c.QoS > 0 {
+		p.nextID++
+		if p.nextID == 0 {
+			p.nextID = 1
+		}
+		id = p.nextID
+		body = binary.BigEndian.AppendUint16(body, id)
+	}
+	body = append(body, payload...)
+	p.conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := p.conn.Write(packet(header, body)); err != nil {
+		return err
+	}
+	if p.c.QoS == 0 {
+		return nil
+	}
+	kind, ack, err := p.read()
+	if err != nil {
+		return err
+	}
+	if kind != 0x40 || len(ack) != 2 || binary.BigEndian.Uint16(ack) != id {
+		return errors.New("expected PUBACK")
+	}
+	return nil
+}
+
+// connect opens a clean session without keep-alive, so an idle connection
+// is only found broken when the next publish fails.
+func (p *Publisher) connect() error {
+	conn, err := dial(p.c.Broker)
+	if err != nil {
+		return err
+	}
+	var flags byte = 0x02 // clean session
+	if p.c.Username != "" {
+		flags |= 0x80
+	}
+	if p.c.Password != "" {
+		flags |= 0x40
+	}
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags, 0, 0) // 3.1.1, flags, keep-alive off
+	body = appendString(body, p.c.ClientID)
+	if p.c.Username != "" {
+		body = appendString(body, p.c.Username)
+	}
+	if p.c.Password != "" {
+		body = appendString(body, p.c.Password)
+	}
+	p.conn, p.r = conn, bufio.NewReader(conn)
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write(packet(0x10, body)); err != nil {
+		return err
+	}
+	kind, ack, err := p.read()
+	if err != nil {
+		return err
+	}
+	if kind != 0x20 || len(ack) != 2 {
+		return errors.New("expected CONNACK")
+	}
+	if ack[1] != 0 {
+		return fmt.Errorf("broker refused the connection (code %d)", ack[1])
+	}
+	return nil
+}
+
+func dial(broker string) (net.Conn, error) {
+	addr, secure := broker, false
+	if u, err := url.Parse(broker); err == nil && u.Host != "" {
+		addr = u.Host
+		secure = u.Scheme == "ssl" || u.Scheme == "tls" || u.Scheme == "mqtts"
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		port := "1883"
+		if secure {
+			port = "8883"
+		}
+		addr = net.JoinHostPort(strings.Trim(addr, "[]"), port)
+	}
+	d := &net.Dialer{Timeout: timeout}
+	if secure {
+		return tls.DialWithDialer(d, "tcp", addr, nil)
+	}
+	return d.Dial("tcp", addr)
+}
+
+// read reads one packet, returning its type (the header's upper bits) and
+// body.
+func (p *Publisher) read() (byte, []byte, error) {
+	header, err := p.r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	var n, shift int
+	for {
+		b, err := p.r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(b&0x7f) << shift
+		if b&0x80 == 0 {
+			break
+		}
+		if shift += 7; shift > 21 {
+			return 0, nil, errors.New("malformed packet length")
+		}
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(p.r, body); err != nil {
+		return 0, nil, err
+	}
+	return header & 0xf0, body, nil
+}
+
+// packet frames body under header with its remaining length.
+func packet(header byte, body []byte) []byte {
+	out := []byte{header}
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		if n /= 128; n > 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(out, body...)
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
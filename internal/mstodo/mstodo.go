@@ -0,0 +1,200 @@
+// Package mstodo reads Microsoft To Do (Outlook Tasks) lists, either from a
+// Microsoft Graph export or pulled live with a Graph access token, into
+// todos for people moving off Microsoft's app.
+package mstodo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/nldate"
+	"dhruvarora9/personal-todo-golang/internal/service"
+)
+
+const graphURL = "https://graph.microsoft.com/v1.0"
+
+// ImportantTag is the tag given to tasks marked important.
+const ImportantTag = "important"
+
+// ErrUnauthorized is returned by Pull when Graph rejects the access token.
+var ErrUnauthorized = errors.New("mstodo: access token rejected")
+
+// List is a Graph todoTaskList with its tasks, as an export holds it.
+type List struct {
+	DisplayName       string `json:"displayName"`
+	WellknownListName string `json:"wellknownListName"`
+	ID                string `json:"id"`
+	Tasks             []Task `json:"tasks"`
+}
+
+// Task is a Graph todoTask.
+type Task struct {
+	Title          string          `json:"title"`
+	Status         string          `json:"status"`
+	Importance     string          `json:"importance"`
+	Categories     []string        `json:"categories"`
+	DueDateTime    *DateTime       `json:"dueDateTime"`
+	ChecklistItems []ChecklistItem `json:"checklistItems"`
+}
+
+// DateTime is a Graph dateTimeTimeZone.
+type DateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+// ChecklistItem is one step of a task.
+type ChecklistItem struct {
+	DisplayName string `json:"displayName"`
+	IsChecked   bool   `json:"isChecked"`
+}
+
+// statuses maps Graph task statuses to ours.
+var statuses = map[string]string{
+	"notStarted":      model.StatusTodo,
+	"inProgress":      model.StatusInProgress,
+	"completed":       model.StatusDone,
+	"waitingOnOthers": model.StatusTodo,
+	"deferred":        model.StatusBacklog,
+}
+
+// Tasks converts lists into import tasks. The default "Tasks" list becomes
+// the default list, and steps become todos that block their task.
+func Tasks(lists []List) []service.ImportTask {
+	var out []service.ImportTask
+	for _, l := range lists {
+		name := strings.TrimSpace(l.DisplayName)
+		if l.WellknownListName == "defaultList" {
+			name = ""
+		}
+		for _, t := range l.Tasks {
+			it := service.ImportTask{TodoInput: service.TodoInput{
+				Title:  t.Title,
+				List:   name,
+				Status: statuses[t.Status],
+				DueAt:  t.DueDateTime.due(),
+			}}
+			for _, c := range t.Categories {
+				if tag := service.TagSlug(c); tag != "" {
+					it.Tags = append(it.Tags, tag)
+				}
+			}
+			if t.Importance == "high" {
+				it.Tags = append(it.Tags, ImportantTag)
+			}
+			for _, c := range t.ChecklistItems {
+				it.Steps = append(it.Steps, service.TodoInput{Title: c.DisplayName, Completed: c.IsChecked})
+			}
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// due reads a due date, which To Do keeps as midnight of the day, as that
+// day at nldate.DefaultHour. Windows zone names Go doesn't know are read
+// as UTC.
+func (d *DateTime) due() *time.Time {
+	if d == nil || d.DateTime == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(d.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05", strings.SplitN(d.DateTime, ".", 2)[0], loc)
+	if err != nil {
+		return nil
+	}
+	t = time.Date(t.Year(), t.Month(), t.Day(), nldate.DefaultHour, 0, 0, 0, loc)
+	return &t
+}
+
+// Client pulls lists from Microsoft Graph.
+type Client struct {
+	http *http.Client
+}
+
+func NewClient() *Client {
+	return &Client{http: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Pull fetches every list of the account that token belongs to, with its
+// tasks and their steps.
+func (c *Client) Pull(ctx context.Context, token string) ([]List, error) {
+	var lists []List
+	err := c.each(ctx, token, graphURL+"/me/todo/lists", func(raw json.RawMessage) error {
+		var l List
+		if err := json.Unmarshal(raw, &l); err != nil {
+			return err
+		}
+		lists = append(lists, l)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range lists {
+		u := graphURL + "/me/todo/lists/" + url.PathEscape(lists[i].ID) + "/tasks?$expand=checklistItems"
+		err := c.each(ctx, token, u, func(raw json.RawMessage) error {
+			var t Task
+			if err := json.Unmarshal(raw, &t); err != nil {
+				return err
+			}
+			lists[i].Tasks = append(lists[i].Tasks, t)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lists, nil
+}
+
+// each calls fn with every item of the Graph collection at u, following
+// @odata.nextLink across pages.
+func (c *Client) each(ctx context.Context, token, u string, fn func(json.RawMessage) error) error {
+	for u != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return err
+		}
+		var page struct {
+			Value    []json.RawMessage `json:"value"`
+			NextLink string            `json:"@odata.nextLink"`
+		}
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			err = ErrUnauthorized
+		case resp.StatusCode != http.StatusOK:
+			b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			err = fmt.Errorf("mstodo: GET %s: %s: %s", u, resp.Status, b)
+		default:
+			err = json.NewDecoder(resp.Body).Decode(&page)
+		}
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		for _, raw := range page.Value {
+			if err := fn(raw); err != nil {
+				return err
+			}
+		}
+		u = page.NextLink
+	}
+	return nil
+}
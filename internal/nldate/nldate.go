@@ -0,0 +1,348 @@
+// Package nldate understands the everyday English people use for due
+// dates: "tomorrow 5pm", "friday", "in 3 days", "mar 10 at noon".
+//
+// Times are read in the location of the reference time passed in. A date
+// without a time is due at DefaultHour; a time without a date is due at its
+// next occurrence.
+package nldate
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHour is the hour a date given without a time is due.
+const DefaultHour = 9
+
+// tonightHour is when "tonight" is due.
+const tonightHour = 20
+
+// maxAhead bounds how far ahead "in N units" may reach.
+const maxAhead = 10 * 366 * 24 * time.Hour
+
+// units are the units of "in N units", by the longest each can be.
+var units = map[string]time.Duration{
+	"minute": time.Minute, "min": time.Minute, "hour": time.Hour,
+	"day": 24 * time.Hour, "week": 7 * 24 * time.Hour, "month": 31 * 24 * time.Hour,
+}
+
+// ErrNotUnderstood is returned by Parse for text it can't read as a date.
+var ErrNotUnderstood = errors.New("nldate: not a date")
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday,
+	"wednesday": time.Wednesday, "thursday": time.Thursday, "friday": time.Friday,
+	"saturday": time.Saturday,
+}
+
+// shortWeekdays are only accepted by Parse: in a title, "sun" and "wed"
+// are more often words than days.
+var shortWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wed": time.Wednesday, "thu": time.Thursday, "thur": time.Thursday,
+	"thurs": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January, "feb": time.February,
+	"february": time.February, "mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April, "may": time.May, "jun": time.June,
+	"june": time.June, "jul": time.July, "july": time.July, "aug": time.August,
+	"august": time.August, "sep": time.September, "sept": time.September,
+	"september": time.September, "oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November, "dec": time.December,
+	"december": time.December,
+}
+
+// Parse reads all of s as a due date relative to now.
+func Parse(s string, now time.Time) (time.Time, error) {
+	toks := tokens(s)
+	p := parser{toks: toks, now: now, short: true}
+	t, n := p.expr(0)
+	if n == 0 || n != len(toks) {
+		return time.Time{}, ErrNotUnderstood
+	}
+	return t, nil
+}
+
+// Extract looks for a due date at the end of title, as in "call mom
+// friday". It returns the title without it, the date and the words it was
+// read from; ok is false, and title is returned as is, if there is none.
+func Extract(title string, now time.Time) (rest string, due time.Time, phrase string, ok bool) {
+	words := strings.Fields(title)
+	toks := make([]string, len(words))
+	for i, w := range words {
+		toks[i] = normalize(w)
+	}
+	// The earliest start that reads to the end takes the longest phrase.
+	// The first word is never part of it, so a title is never emptied.
+	for i := 1; i < len(toks); i++ {
+		p := parser{toks: toks, now: now}
+		t, n := p.expr(i)
+		if n > 0 && i+n == len(toks) {
+			return strings.Join(words[:i], " "), t, strings.Join(words[i:], " "), true
+		}
+	}
+	return title, time.Time{}, "", false
+}
+
+// tokens splits s into normalized words.
+func tokens(s string) []string {
+	var out []string
+	for _, w := range strings.Fields(s) {
+		if w = normalize(w); w != "" {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// normalize lowercases w and trims the punctuation around it.
+func normalize(w string) string {
+	return strings.Trim(strings.ToLower(w), ",.;!?()\"'")
+}
+
+// parser reads one date expression from toks, relative to now. short
+// allows abbreviated weekdays.
+type parser struct {
+	toks  []string
+	now   time.Time
+	short bool
+	// tonight is set once date reads "tonight", which is due later than
+	// other days.
+	tonight bool
+}
+
+func (p *parser) tok(i int) string {
+	if i < len(p.toks) {
+		return p.toks[i]
+	}
+	return ""
+}
+
+// expr reads a date expression starting at i and returns the time and how
+// many tokens it took, 0 if there is none. It is an optional lead-in
+// ("on", "by", "due"), then a date, a time or both in either order.
+func (p *parser) expr(i int) (time.Time, int) {
+	start := i
+	switch p.tok(i) {
+	case "on", "by", "due", "at":
+		i++
+	}
+	if t, n := p.relative(i); n > 0 {
+		return t, i + n - start
+	}
+	day, hasDay, dn := p.date(i)
+	i += dn
+	hour, min, hasTime, tn := p.clock(i)
+	i += tn
+	if !hasDay && hasTime {
+		// "5pm tomorrow"
+		if day, hasDay, dn = p.date(i); hasDay {
+			i += dn
+		}
+	}
+	switch {
+	case hasDay && hasTime:
+		return at(day, hour, min), i - start
+	case hasDay && p.tonight:
+		return at(day, tonightHour, 0), i - start
+	case hasDay:
+		return at(day, DefaultHour, 0), i - start
+	case hasTime:
+		t := at(p.now, hour, min)
+		if !t.After(p.now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, i - start
+	}
+	return time.Time{}, 0
+}
+
+// relative reads "in N units" and "next week" or "next month".
+func (p *parser) relative(i int) (time.Time, int) {
+	if p.tok(i) == "next" {
+		switch p.tok(i + 1) {
+		case "week":
+			return at(p.now.AddDate(0, 0, 7), DefaultHour, 0), 2
+		case "month":
+			return at(p.now.AddDate(0, 1, 0), DefaultHour, 0), 2
+		}
+		return time.Time{}, 0
+	}
+	if p.tok(i) != "in" {
+		return time.Time{}, 0
+	}
+	n, err := strconv.Atoi(p.tok(i + 1))
+	if p.tok(i+1) == "a" || p.tok(i+1) == "an" {
+		n, err = 1, nil
+	}
+	if err != nil || n < 0 {
+		return time.Time{}, 0
+	}
+	unit := strings.TrimSuffix(p.tok(i+2), "s")
+	if longest, ok := units[unit]; !ok || time.Duration(n) > maxAhead/longest {
+		return time.Time{}, 0
+	}
+	switch unit {
+	case "minute", "min":
+		return p.now.Add(time.Duration(n) * time.Minute), 3
+	case "hour":
+		return p.now.Add(time.Duration(n) * time.Hour), 3
+	case "day":
+		return at(p.now.AddDate(0, 0, n), DefaultHour, 0), 3
+	case "week":
+		return at(p.now.AddDate(0, 0, 7*n), DefaultHour, 0), 3
+	case "month":
+		return at(p.now.AddDate(0, n, 0), DefaultHour, 0), 3
+	}
+	return time.Time{}, 0
+}
+
+// date reads a day: a relative word, a weekday, an ISO date or a month and
+// day. "next friday" and "this friday" both mean the coming one. ok is
+// false if there is none at i.
+func (p *parser) date(i int) (day time.Time, ok bool, n int) {
+	w := p.tok(i)
+	switch w {
+	case "today":
+		return p.now, true, 1
+	case "tonight":
+		p.tonight = true
+		return p.now, true, 1
+	case "tomorrow", "tmrw":
+		return p.now.AddDate(0, 0, 1), true, 1
+	case "next", "this":
+		if d, ok := p.weekday(p.tok(i + 1)); ok {
+			return p.nextWeekday(d), true, 2
+		}
+		return time.Time{}, false, 0
+	}
+	if d, ok := p.weekday(w); ok {
+		return p.nextWeekday(d), true, 1
+	}
+	if t, err := time.ParseInLocation("2006-01-02", w, p.now.Location()); err == nil {
+		return t, true, 1
+	}
+	// "mar 10", "march 10th", "10 march", each optionally with a year.
+	if m, ok := months[w]; ok {
+		if d, ok := dayOfMonth(p.tok(i + 1)); ok {
+			return p.withYear(m, d, i+2, 2)
+		}
+	}
+	if d, ok := dayOfMonth(w); ok {
+		if m, ok := months[p.tok(i+1)]; ok {
+			return p.withYear(m, d, i+2, 2)
+		}
+	}
+	return time.Time{}, false, 0
+}
+
+func (p *parser) weekday(w string) (time.Weekday, bool) {
+	if d, ok := weekdays[w]; ok {
+		return d, true
+	}
+	if p.short {
+		d, ok := shortWeekdays[w]
+		return d, ok
+	}
+	return 0, false
+}
+
+// nextWeekday is the first d after today.
+func (p *parser) nextWeekday(d time.Weekday) time.Time {
+	ahead := (int(d) - int(p.now.Weekday()) + 7) % 7
+	if ahead == 0 {
+		ahead = 7
+	}
+	return p.now.AddDate(0, 0, ahead)
+}
+
+// withYear completes a month and day with the year at i, if there is one,
+// or else the year in which it next comes round. A day the month doesn't
+// have, such as "feb 30", is no date.
+func (p *parser) withYear(m time.Month, d, i, n int) (time.Time, bool, int) {
+	loc := p.now.Location()
+	if y, err := strconv.Atoi(p.tok(i)); err == nil && y >= 1000 && y <= 9999 {
+		t := time.Date(y, m, d, 0, 0, 0, 0, loc)
+		if t.Day() != d {
+			return time.Time{}, false, 0
+		}
+		return t, true, n + 1
+	}
+	today := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, loc)
+	// Feb 29 may be up to eight years away.
+	for y := p.now.Year(); y <= p.now.Year()+8; y++ {
+		t := time.Date(y, m, d, 0, 0, 0, 0, loc)
+		if t.Day() == d && !t.Before(today) {
+			return t, true, n
+		}
+	}
+	return time.Time{}, false, 0
+}
+
+// dayOfMonth reads "10" or "10th".
+func dayOfMonth(w string) (int, bool) {
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		w = strings.TrimSuffix(w, suffix)
+	}
+	d, err := strconv.Atoi(w)
+	return d, err == nil && d >= 1 && d <= 31
+}
+
+// clock reads a time of day: "5pm", "5 pm", "5:30pm", "17:00", "noon",
+// "midnight", optionally after "at". A bare number is not a time.
+func (p *parser) clock(i int) (hour, min int, ok bool, n int) {
+	if p.tok(i) == "at" {
+		i++
+		n++
+	}
+	w := p.tok(i)
+	switch w {
+	case "noon":
+		return 12, 0, true, n + 1
+	case "midnight":
+		return 0, 0, true, n + 1
+	}
+	suffix := ""
+	for _, s := range []string{"am", "pm"} {
+		if strings.HasSuffix(w, s) {
+			w, suffix = strings.TrimSuffix(w, s), s
+		}
+	}
+	if suffix == "" && (p.tok(i+1) == "am" || p.tok(i+1) == "pm") {
+		suffix = p.tok(i + 1)
+		n++
+	}
+	hs, ms, colon := strings.Cut(w, ":")
+	if !colon && suffix == "" {
+		return 0, 0, false, 0
+	}
+	hour, err := strconv.Atoi(hs)
+	if err != nil {
+		return 0, 0, false, 0
+	}
+	if colon {
+		if min, err = strconv.Atoi(ms); err != nil || len(ms) != 2 || min > 59 {
+			return 0, 0, false, 0
+		}
+	}
+	switch {
+	case suffix != "" && (hour < 1 || hour > 12):
+		return 0, 0, false, 0
+	case suffix == "" && hour > 23:
+		return 0, 0, false, 0
+	case suffix == "pm" && hour != 12:
+		hour += 12
+	case suffix == "am" && hour == 12:
+		hour = 0
+	}
+	return hour, min, true, n + 1
+}
+
+// at is day at hour:min in day's location.
+func at(day time.Time, hour, min int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, min, 0, 0, day.Location())
+}
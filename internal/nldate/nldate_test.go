@@ -0,0 +1,99 @@
+package nldate
+
+import (
+	"testing"
+	"time"
+)
+
+// now is Wednesday 2026-10-14 at 15:30.
+var now = time.Date(2026, time.October, 14, 15, 30, 0, 0, time.UTC)
+
+func date(y int, m time.Month, d, hour, min int) time.Time {
+	return time.Date(y, m, d, hour, min, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"today", date(2026, time.October, 14, 9, 0)},
+		{"tonight", date(2026, time.October, 14, 20, 0)},
+		{"tomorrow", date(2026, time.October, 15, 9, 0)},
+		{"tomorrow 5pm", date(2026, time.October, 15, 17, 0)},
+		{"5pm tomorrow", date(2026, time.October, 15, 17, 0)},
+		{"friday", date(2026, time.October, 16, 9, 0)},
+		{"fri at 8:15am", date(2026, time.October, 16, 8, 15)},
+		{"wednesday", date(2026, time.October, 21, 9, 0)},
+		{"next friday", date(2026, time.October, 16, 9, 0)},
+		{"in 3 days", date(2026, time.October, 17, 9, 0)},
+		{"in 2 hours", date(2026, time.October, 14, 17, 30)},
+		{"in a week", date(2026, time.October, 21, 9, 0)},
+		{"next month", date(2026, time.November, 14, 9, 0)},
+		{"noon", date(2026, time.October, 15, 12, 0)},
+		{"17:00", date(2026, time.October, 14, 17, 0)},
+		{"12am", date(2026, time.October, 15, 0, 0)},
+		{"mar 10 at noon", date(2027, time.March, 10, 12, 0)},
+		{"10th december", date(2026, time.December, 10, 9, 0)},
+		{"oct 14", date(2026, time.October, 14, 9, 0)},
+		{"feb 29", date(2028, time.February, 29, 9, 0)},
+		{"feb 28 2030", date(2030, time.February, 28, 9, 0)},
+		{"2026-12-24", date(2026, time.December, 24, 9, 0)},
+		{"by friday 9pm", date(2026, time.October, 16, 21, 0)},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, now)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"someday",
+		"feb 30",
+		"apr 31 2027",
+		"feb 29 2027",
+		"13pm",
+		"25:00",
+		"5:7pm",
+		"in -1 days",
+		"in 100000000000 days",
+		"in 9999999999999 minutes",
+		"in 3 fortnights",
+		"friday banana",
+	} {
+		if got, err := Parse(in, now); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", in, got)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		title, rest, phrase string
+		want                time.Time
+		ok                  bool
+	}{
+		{"call mom friday", "call mom", "friday", date(2026, time.October, 16, 9, 0), true},
+		{"pay rent by tomorrow 5pm", "pay rent", "by tomorrow 5pm", date(2026, time.October, 15, 17, 0), true},
+		{"dinner at 7pm", "dinner", "at 7pm", date(2026, time.October, 14, 19, 0), true},
+		// Short weekdays are words in titles.
+		{"plan the sat", "plan the sat", "", time.Time{}, false},
+		{"tomorrow", "tomorrow", "", time.Time{}, false},
+		{"buy milk", "buy milk", "", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		rest, due, phrase, ok := Extract(tt.title, now)
+		if rest != tt.rest || phrase != tt.phrase || ok != tt.ok || !due.Equal(tt.want) {
+			t.Errorf("Extract(%q) = %q, %v, %q, %v; want %q, %v, %q, %v",
+				tt.title, rest, due, phrase, ok, tt.rest, tt.want, tt.phrase, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+)
+
+// Embed colours by event type; anything else is grey.
+var discordColors = map[string]int{
+	events.TodoCreated:   0x5865f2,
+	events.TodoCompleted: 0x57f287,
+	events.TodoDeleted:   0xed4245,
+}
+
+// DiscordNotifier posts an embed to a Discord webhook URL (the address).
+// Combined with a list on the integration, each list can have its own
+// channel.
+type DiscordNotifier struct {
+	client *http.Client
+}
+
+func NewDiscordNotifier() *DiscordNotifier {
+	return &DiscordNotifier{client: publicClient(10 * time.Second)}
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Color       int            `json:"color"`
+	Timestamp   time.Time      `json:"timestamp"`
+	Footer      *discordFooter `json:"footer,omitempty"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+func (n *DiscordNotifier) Send(ctx context.Context, e events.Event, t Target) error {
+	color, ok := discordColors[e.Type]
+	if !ok {
+		color = 0x99aab5
+	}
+	embed := discordEmbed{Title: headline(e, t.Locale), Description: title(e, t.Locale), Color: color, Timestamp: e.At}
+	if e.Todo != nil && e.Todo.List != "" {
+		embed.Footer = &discordFooter{Text: e.Todo.List}
+	}
+	var payload interface{} = map[string][]discordEmbed{"embeds": {embed}}
+	if t.Template != "" {
+		content, err := message(e, t, "")
+		if err != nil {
+			return err
+		}
+		payload = map[string]string{"content": content}
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, n.client, t.Address, b)
+}
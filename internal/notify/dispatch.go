@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+	"dhruvarora9/personal-todo-golang/internal/jobs"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+)
+
+const jobKind = "notify"
+
+// IntegrationSource gives the dispatcher the configured integrations.
+type IntegrationSource interface {
+	ListIntegrations(ctx context.Context) ([]model.Integration, error)
+}
+
+// SettingsSource gives the dispatcher the tenant's settings, whose locale
+// notifications are written in.
+type SettingsSource interface {
+	GetSettings(ctx context.Context) (*model.Settings, error)
+}
+
+// Dispatcher turns bus events into one background job per matching
+// integration, so slow or failing channels are retried without holding up
+// anything else.
+type Dispatcher struct {
+	registry     *Registry
+	integrations IntegrationSource
+	settings     SettingsSource
+	queue        *jobs.Queue
+	log          *log.Logger
+}
+
+type delivery struct {
+	Event    events.Event `json:"event"`
+	Channel  string       `json:"channel"`
+	Address  string       `json:"address"`
+	Template string       `json:"template,omitempty"`
+	Locale   string       `json:"locale,omitempty"`
+	TimeZone string       `json:"time_zone,omitempty"`
+}
+
+// NewDispatcher registers the delivery job with queue and subscribes to bus.
+func NewDispatcher(reg *Registry, integrations IntegrationSource, settings SettingsSource, queue *jobs.Queue, bus *events.Bus, logger *log.Logger) *Dispatcher {
+	d := &Dispatcher{registry: reg, integrations: integrations, settings: settings, queue: queue, log: logger}
+	queue.Register(jobKind, d.deliver)
+	bus.Subscribe("notify", d.handle)
+	return d
+}
+
+func (d *Dispatcher) handle(e events.Event) {
+	ctx := tenant.NewContext(context.Background(), e.Tenant)
+	all, err := d.integrations.ListIntegrations(ctx)
+	if err != nil {
+		d.log.Printf("notify: loading integrations: %v", err)
+		return
+	}
+	st, err := d.settings.GetSettings(ctx)
+	if err != nil {
+		d.log.Printf("notify: loading settings: %v", err)
+		st = &model.Settings{}
+	}
+	locale := st.Locale
+	if locale == "" {
+		locale = i18n.Default
+	}
+	var list string
+	if e.Todo != nil {
+		list = e.Todo.List
+	}
+	for _, in := range all {
+		if !in.Wants(e.Type, list) {
+			continue
+		}
+		// Digests go only where asked for by name, not to every
+		// integration taking all events.
+		if e.Type == events.DailyDigest && len(in.Events) == 0 {
+			continue
+		}
+		job := delivery{Event: e, Channel: in.Channel, Address: in.Address, Template: in.Template, Locale: locale, TimeZone: st.TimeZone}
+		if _, err := d.queue.Enqueue(jobKind, job); err != nil {
+			d.log.Printf("notify: queueing %s for %s: %v", e.Type, in.Name, err)
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, payload json.RawMessage) error {
+	var job delivery
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	n, ok := d.registry.Get(job.Channel)
+	if !ok {
+		return fmt.Errorf("notify: unknown channel %q", job.Channel)
+	}
+	// Channels that look things up, like webpush's subscriptions, do so
+	// for the event's tenant.
+	ctx = tenant.NewContext(ctx, job.Event.Tenant)
+	loc, err := tz.Load(job.TimeZone)
+	if err != nil {
+		d.log.Printf("notify: %v", err)
+		loc = time.Local
+	}
+	t := Target{Channel: job.Channel, Address: job.Address, Template: job.Template, Locale: job.Locale, Location: loc}
+	return n.Send(ctx, job.Event, t)
+}
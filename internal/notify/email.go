@@ -0,0 +1,191 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"text/template"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// The templates are parsed once with English messages and cloned for each
+// mail to translate into its locale.
+var (
+	textTemplate = template.Must(template.New("email.txt.tpl").Funcs(i18n.Funcs(i18n.Default)).ParseFS(templates, "templates/email.txt.tpl"))
+	htmlTemplate = htmltemplate.Must(htmltemplate.New("email.html.tpl").Funcs(i18n.Funcs(i18n.Default)).ParseFS(templates, "templates/email.html.tpl"))
+)
+
+// EmailNotifier sends notifications as multipart plain text and HTML mail
+// through an SMTP server.
+type EmailNotifier struct {
+	c config.SMTP
+}
+
+func NewEmailNotifier(c config.SMTP) *EmailNotifier {
+	return &EmailNotifier{c: c}
+}
+
+type emailData struct {
+	Headline string
+	Title    string
+	Address  string
+	At       time.Time
+	// Overdue, DueToday and Shared list the todo titles of a digest.
+	Overdue, DueToday, Shared []string
+	// Body is the integration's template output, which is sent as the
+	// only (plain text) part instead of the built-in templates.
+	Body string
+}
+
+func (n *EmailNotifier) Send(ctx context.Context, e events.Event, t Target) error {
+	data := emailData{Headline: headline(e, t.Locale), Title: title(e, t.Locale), Address: t.Address, At: e.At}
+	if t.Location != nil {
+		data.At = e.At.In(t.Location)
+	}
+	if d := e.Digest; d != nil {
+		for _, t := range d.Overdue {
+			data.Overdue = append(data.Overdue, t.Title)
+		}
+		for _, t := range d.DueToday {
+			data.DueToday = append(data.DueToday, t.Title)
+		}
+		for _, t := range d.Shared {
+			data.Shared = append(data.Shared, t.Title)
+		}
+	}
+	body, err := message(e, t, "")
+	if err != nil {
+		return err
+	}
+	data.Body = body
+	msg, err := n.compose(t.Address, t.Locale, data)
+	if err != nil {
+		return err
+	}
+	return n.send(ctx, t.Address, msg)
+}
+
+// send delivers msg like smtp.SendMail, but gives up when ctx is done.
+func (n *EmailNotifier) send(ctx context.Context, to string, msg []byte) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(n.c.Host, strconv.Itoa(n.c.Port)))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, n.c.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: n.c.Host}); err != nil {
+			return err
+		}
+	}
+	if n.c.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", n.c.Username, n.c.Password, n.c.Host)); err != nil {
+			return err
+		}
+	}
+	from, err := mail.ParseAddress(n.c.From)
+	if err != nil {
+		return fmt.Errorf("smtp.from: %w", err)
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (n *EmailNotifier) compose(to, locale string, data emailData) ([]byte, error) {
+	text, err := textTemplate.Clone()
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmlTemplate.Clone()
+	if err != nil {
+		return nil, err
+	}
+	text.Funcs(i18n.Funcs(locale))
+	html.Funcs(i18n.Funcs(locale))
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "From: %s\r\n", n.c.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", data.Headline))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+
+	parts := []struct {
+		contentType string
+		execute     func(*quotedprintable.Writer) error
+	}{
+		{"text/plain", func(w *quotedprintable.Writer) error { return text.Execute(w, data) }},
+		{"text/html", func(w *quotedprintable.Writer) error { return html.Execute(w, data) }},
+	}
+	if data.Body != "" {
+		parts = parts[:1]
+		parts[0].execute = func(w *quotedprintable.Writer) error {
+			_, err := io.WriteString(w, data.Body)
+			return err
+		}
+	}
+	for _, p := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if err := p.execute(qw); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
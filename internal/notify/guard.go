@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// errPrivateAddress is returned for connections to addresses tenants must
+// not reach through their integrations.
+var errPrivateAddress = errors.New("notify: refusing to connect to a private address")
+
+// publicClient returns a client for URLs tenants choose, such as webhook
+// addresses. It refuses to connect to loopback, private and link-local
+// addresses, which would reach the server itself, its database or cloud
+// metadata. The check runs on the address actually dialled, after DNS, so
+// a name resolving to one of them is refused as well; redirects are
+// dialled, and checked, the same way. No proxy is used.
+func publicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: refusePrivate}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// refusePrivate is a net.Dialer Control that fails the dial of address
+// unless it is a public one.
+func refusePrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !public(ip) {
+		return fmt.Errorf("%w: %s", errPrivateAddress, host)
+	}
+	return nil
+}
+
+// public reports whether ip is reachable on the internet, as opposed to
+// the host, its network or a link.
+func public(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || sharedAddress.Contains(ip))
+}
+
+// sharedAddress is the carrier-grade NAT range of RFC 6598, private in all
+// but name.
+var sharedAddress = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
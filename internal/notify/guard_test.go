@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRefusePrivate(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool // allowed
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:4700:4700::1111]:443", true},
+		{"100.128.0.1:80", true},
+		{"127.0.0.1:80", false},
+		{"[::1]:80", false},
+		{"[::ffff:127.0.0.1]:80", false},
+		{"10.1.2.3:80", false},
+		{"172.16.0.1:80", false},
+		{"192.168.1.1:80", false},
+		{"[fd00::1]:80", false},
+		{"169.254.169.254:80", false},
+		{"[fe80::1]:80", false},
+		{"0.0.0.0:80", false},
+		{"100.64.0.1:80", false},
+		{"100.127.255.254:80", false},
+		{"224.0.0.251:5353", false},
+		// Control only ever sees resolved addresses; a name is refused.
+		{"localhost:80", false},
+	}
+	for _, tt := range tests {
+		err := refusePrivate("tcp", tt.address, nil)
+		if tt.want && err != nil {
+			t.Errorf("refusePrivate(%q) = %v, want nil", tt.address, err)
+		}
+		if !tt.want && !errors.Is(err, errPrivateAddress) {
+			t.Errorf("refusePrivate(%q) = %v, want errPrivateAddress", tt.address, err)
+		}
+	}
+}
+
+func TestPublicClientRefusesLoopback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	resp, err := publicClient(time.Second).Get(srv.URL)
+	if err == nil {
+		resp.Body.Close()
+	}
+	if !errors.Is(err, errPrivateAddress) {
+		t.Errorf("Get(%s) = %v, want errPrivateAddress", srv.URL, err)
+	}
+}
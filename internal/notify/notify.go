@@ -0,0 +1,173 @@
+// Package notify sends todo events to people through pluggable channels.
+// Each channel (log, webhook, ...) is a Notifier registered under a name;
+// the configured integrations decide which events go to which channel and
+// address.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+)
+
+// Target is where a notification goes: the channel's idea of an address,
+// such as a URL or an email address. Template, when set, replaces the
+// channel's default message. The default messages are in Locale, with
+// times in Location.
+type Target struct {
+	Channel  string
+	Address  string
+	Template string
+	Locale   string
+	Location *time.Location
+}
+
+type Notifier interface {
+	Send(ctx context.Context, e events.Event, t Target) error
+}
+
+// headlines give each event type its subject line and opening sentence.
+var headlines = map[string]string{
+	events.TodoCreated:     "A todo was added",
+	events.TodoUpdated:     "A todo was changed",
+	events.TodoCompleted:   "A todo was completed",
+	events.TodoDeleted:     "A todo was deleted",
+	events.PomodoroBreak:   "Pomodoro done, time for a break",
+	events.DailyDigest:     "Your todos for today",
+	events.StreakMilestone: "New badge earned",
+}
+
+func headline(e events.Event, locale string) string {
+	if h, ok := headlines[e.Type]; ok {
+		return i18n.T(locale, h)
+	}
+	return e.Type
+}
+
+// title is the todo's title, or its ID when the event carries no todo. For
+// a digest it is a count of what it holds, and for a milestone the badge.
+func title(e events.Event, locale string) string {
+	if d := e.Digest; d != nil {
+		if len(d.Shared) > 0 {
+			return i18n.T(locale, "%d overdue, %d due today, %d shared", len(d.Overdue), len(d.DueToday), len(d.Shared))
+		}
+		return i18n.T(locale, "%d overdue, %d due today", len(d.Overdue), len(d.DueToday))
+	}
+	if e.Badge != "" {
+		return e.Badge
+	}
+	if e.Todo != nil {
+		return e.Todo.Title
+	}
+	return e.TodoID
+}
+
+// message is t's template executed with e, or def when there is none.
+func message(e events.Event, t Target, def string) (string, error) {
+	if t.Template == "" {
+		return def, nil
+	}
+	tpl, err := template.New("message").Parse(t.Template)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tpl.Execute(&b, e); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Registry maps channel names to notifiers.
+type Registry struct {
+	notifiers map[string]Notifier
+}
+
+func NewRegistry() *Registry {
+	return &Registry{notifiers: map[string]Notifier{}}
+}
+
+func (r *Registry) Register(channel string, n Notifier) {
+	r.notifiers[channel] = n
+}
+
+func (r *Registry) Get(channel string) (Notifier, bool) {
+	n, ok := r.notifiers[channel]
+	return n, ok
+}
+
+// Channels lists the registered channel names.
+func (r *Registry) Channels() []string {
+	names := make([]string, 0, len(r.notifiers))
+	for name := range r.notifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LogNotifier writes notifications to the log; useful to try out
+// integrations without setting up a real channel.
+type LogNotifier struct {
+	Log *log.Logger
+}
+
+func (n LogNotifier) Send(ctx context.Context, e events.Event, t Target) error {
+	msg, err := message(e, t, fmt.Sprintf("%s %s", e.Type, e.TodoID))
+	if err != nil {
+		return err
+	}
+	n.Log.Printf("notify: %s (to %q)", msg, t.Address)
+	return nil
+}
+
+// WebhookNotifier POSTs the event as JSON to the target address, or the
+// target's template output if it has one. Private addresses are refused;
+// see publicClient.
+type WebhookNotifier struct {
+	Client *http.Client
+}
+
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{Client: publicClient(10 * time.Second)}
+}
+
+func (n *WebhookNotifier) Send(ctx context.Context, e events.Event, t Target) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	body, err := message(e, t, string(b))
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, n.Client, t.Address, []byte(body))
+}
+
+// postJSON posts body and treats anything but a 2xx answer as a failure.
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s answered %s", url, resp.Status)
+	}
+	return nil
+}
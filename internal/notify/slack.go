@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+)
+
+const slackPostMessage = "https://slack.com/api/chat.postMessage"
+
+// SlackNotifier posts to Slack. An address starting with https:// is an
+// incoming webhook URL; anything else is a channel ID posted to with the
+// bot token.
+type SlackNotifier struct {
+	token  string
+	client *http.Client
+}
+
+func NewSlackNotifier(botToken string) *SlackNotifier {
+	return &SlackNotifier{token: botToken, client: publicClient(10 * time.Second)}
+}
+
+func (n *SlackNotifier) Send(ctx context.Context, e events.Event, t Target) error {
+	text, err := message(e, t, fmt.Sprintf("%s: *%s*", headline(e, t.Locale), title(e, t.Locale)))
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(t.Address, "https://") {
+		b, err := json.Marshal(map[string]string{"text": text})
+		if err != nil {
+			return err
+		}
+		return postJSON(ctx, n.client, t.Address, b)
+	}
+	if n.token == "" {
+		return errors.New("slack: posting to a channel needs slack.bot_token")
+	}
+	return n.postMessage(ctx, t.Address, text)
+}
+
+// postMessage calls chat.postMessage, which reports failures in the body
+// rather than the status code.
+func (n *SlackNotifier) postMessage(ctx context.Context, channel, text string) error {
+	b, err := json.Marshal(map[string]string{"channel": channel, "text": text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, slackPostMessage, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+n.token)
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var out struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("slack: %s: %w", resp.Status, err)
+	}
+	if !out.OK {
+		return fmt.Errorf("slack: %s", out.Error)
+	}
+	return nil
+}
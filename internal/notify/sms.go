@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/events"
+)
+
+const twilioAPI = "https://api.twilio.com/2010-04-01/Accounts/"
+
+// SMSNotifier texts the address (an E.164 phone number) through Twilio. At
+// most MaxPerHour messages go out per hour across all numbers; anything
+// over the cap is dropped rather than retried, since a late text is worse
+// than none.
+type SMSNotifier struct {
+	c      config.Twilio
+	client *http.Client
+	log    *log.Logger
+	now    func() time.Time
+
+	mu   sync.Mutex
+	sent []time.Time
+}
+
+func NewSMSNotifier(c config.Twilio, logger *log.Logger, now func() time.Time) *SMSNotifier {
+	return &SMSNotifier{c: c, client: &http.Client{Timeout: 10 * time.Second}, log: logger, now: now}
+}
+
+// allow records a send if the hourly cap permits it.
+func (n *SMSNotifier) allow() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	now := n.now()
+	recent := n.sent[:0]
+	for _, t := range n.sent {
+		if now.Sub(t) < time.Hour {
+			recent = append(recent, t)
+		}
+	}
+	n.sent = recent
+	if len(n.sent) >= n.c.MaxPerHour {
+		return false
+	}
+	n.sent = append(n.sent, now)
+	return true
+}
+
+func (n *SMSNotifier) Send(ctx context.Context, e events.Event, t Target) error {
+	text, err := message(e, t, fmt.Sprintf("%s: %s", headline(e, t.Locale), title(e, t.Locale)))
+	if err != nil {
+		return err
+	}
+	if !n.allow() {
+		n.log.Printf("sms: hourly cap of %d reached, dropping %s to %s", n.c.MaxPerHour, e.Type, t.Address)
+		return nil
+	}
+	form := url.Values{
+		"From": {n.c.From},
+		"To":   {t.Address},
+		"Body": {text},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		twilioAPI+url.PathEscape(n.c.AccountSID)+"/Messages.json", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(n.c.AccountSID, n.c.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var out struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&out)
+		return fmt.Errorf("twilio: %s: %s", resp.Status, out.Message)
+	}
+	return nil
+}
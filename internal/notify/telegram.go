@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/telegram"
+)
+
+// TelegramNotifier messages a chat; the address is the chat ID, which the
+// bot stores when a chat links itself.
+type TelegramNotifier struct {
+	api *telegram.Client
+}
+
+func NewTelegramNotifier(api *telegram.Client) *TelegramNotifier {
+	return &TelegramNotifier{api: api}
+}
+
+func (n *TelegramNotifier) Send(ctx context.Context, e events.Event, t Target) error {
+	text, err := message(e, t, fmt.Sprintf("%s: %s", headline(e, t.Locale), title(e, t.Locale)))
+	if err != nil {
+		return err
+	}
+	return n.api.SendMessage(ctx, t.Address, text)
+}
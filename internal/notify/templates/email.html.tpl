@@ -0,0 +1,25 @@
+<!DOCTYPE html>
+<html lang="{{locale}}">
+<body style="font-family: sans-serif; color: #333;">
+  <p>{{.Headline}}</p>
+  <p style="font-size: 1.2em;"><strong>{{.Title}}</strong></p>
+  {{if .Overdue}}
+  <p>{{t "Overdue:"}}</p>
+  <ul>{{range .Overdue}}<li>{{.}}</li>{{end}}</ul>
+  {{end}}
+  {{if .DueToday}}
+  <p>{{t "Due today:"}}</p>
+  <ul>{{range .DueToday}}<li>{{.}}</li>{{end}}</ul>
+  {{end}}
+  {{if .Shared}}
+  <p>{{t "Shared since yesterday:"}}</p>
+  <ul>{{range .Shared}}<li>{{.}}</li>{{end}}</ul>
+  {{end}}
+  <p style="color: #777;">{{.At.Format "Mon, 02 Jan 2006 15:04 MST"}}</p>
+  <hr>
+  <p style="color: #777; font-size: 0.9em;">
+    {{t "You get this email because an integration sends todo events to %s." .Address}}
+    {{t "Change that under /integrations."}}
+  </p>
+</body>
+</html>
@@ -0,0 +1,17 @@
+{{.Headline}}
+
+  {{.Title}}
+{{if .Overdue}}
+{{t "Overdue:"}}
+{{range .Overdue}}  - {{.}}
+{{end}}{{end}}{{if .DueToday}}
+{{t "Due today:"}}
+{{range .DueToday}}  - {{.}}
+{{end}}{{end}}{{if .Shared}}
+{{t "Shared since yesterday:"}}
+{{range .Shared}}  - {{.}}
+{{end}}{{end}}
+{{.At.Format "Mon, 02 Jan 2006 15:04 MST"}}
+
+{{t "You get this email because an integration sends todo events to %s." .Address}}
+{{t "Change that under /integrations."}}
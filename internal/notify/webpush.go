@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	webpush "github.com/SherClockHolmes/webpush-go"
+)
+
+// PushSubscriptions lists and prunes browser subscriptions.
+type PushSubscriptions interface {
+	ListPushSubscriptions(ctx context.Context) ([]model.PushSubscription, error)
+	DeletePushSubscription(ctx context.Context, endpoint string) error
+}
+
+// WebPushNotifier pushes to every subscribed browser, so the address is
+// unused. Subscriptions the push service reports as gone are deleted.
+type WebPushNotifier struct {
+	c    config.WebPush
+	subs PushSubscriptions
+}
+
+func NewWebPushNotifier(c config.WebPush, subs PushSubscriptions) *WebPushNotifier {
+	return &WebPushNotifier{c: c, subs: subs}
+}
+
+func (n *WebPushNotifier) Send(ctx context.Context, e events.Event, t Target) error {
+	subs, err := n.subs.ListPushSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := message(e, t, title(e, t.Locale))
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(map[string]string{"title": headline(e, t.Locale), "body": body})
+	if err != nil {
+		return err
+	}
+	// Keep pushing to the rest when one browser fails; the job is retried
+	// as a whole, which may repeat a notification on the others.
+	var failed int
+	var last error
+	for _, sub := range subs {
+		if err := n.push(ctx, sub, payload); err != nil {
+			failed, last = failed+1, err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("webpush: %d of %d pushes failed, last: %w", failed, len(subs), last)
+	}
+	return nil
+}
+
+func (n *WebPushNotifier) push(ctx context.Context, sub model.PushSubscription, payload []byte) error {
+	resp, err := webpush.SendNotificationWithContext(ctx, payload, &webpush.Subscription{
+		Endpoint: sub.Endpoint,
+		Keys:     webpush.Keys{P256dh: sub.Keys.P256dh, Auth: sub.Keys.Auth},
+	}, &webpush.Options{
+		Subscriber:      n.c.Subject,
+		VAPIDPublicKey:  n.c.PublicKey,
+		VAPIDPrivateKey: n.c.PrivateKey,
+		TTL:             24 * 60 * 60,
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		if err := n.subs.DeletePushSubscription(ctx, sub.Endpoint); err != nil && !errors.Is(err, store.ErrNotFound) {
+			return err
+		}
+		return nil
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("webpush: %s answered %s", sub.Endpoint, resp.Status)
+	}
+	return nil
+}
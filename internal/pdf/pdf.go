@@ -0,0 +1,188 @@
+// Package pdf writes plain PDF documents: pages of text in the standard
+// Helvetica fonts with lines and boxes, enough for a printable list. Text
+// outside Windows-1252 is drawn as '?'.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Paper sizes in points.
+var (
+	A4     = Size{595, 842}
+	Letter = Size{612, 792}
+)
+
+type Size struct {
+	Width, Height float64
+}
+
+// Font is one of the two fonts every PDF reader has.
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+)
+
+// Document collects pages to write.
+type Document struct {
+	Size  Size
+	Title string
+	pages []*Page
+}
+
+func New(size Size, title string) *Document {
+	return &Document{Size: size, Title: title}
+}
+
+// AddPage starts a new page and returns it.
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Page is drawn on in points, from the bottom left corner.
+type Page struct {
+	content bytes.Buffer
+}
+
+// Text draws s with its baseline starting at x, y.
+func (p *Page) Text(x, y float64, f Font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td (%s) Tj ET\n", f+1, num(size), num(x), num(y), escape(s))
+}
+
+// Gray sets the colour of what is drawn next, from 0 for black to 1 for
+// white.
+func (p *Page) Gray(level float64) {
+	fmt.Fprintf(&p.content, "%s g %s G\n", num(level), num(level))
+}
+
+// Rect outlines a w by h rectangle whose bottom left corner is x, y.
+func (p *Page) Rect(x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "0.8 w %s %s %s %s re S\n", num(x), num(y), num(w), num(h))
+}
+
+// Line draws a line through points, given as x, y pairs.
+func (p *Page) Line(points ...float64) {
+	p.content.WriteString("1.2 w ")
+	for i := 0; i+1 < len(points); i += 2 {
+		op := "l"
+		if i == 0 {
+			op = "m"
+		}
+		fmt.Fprintf(&p.content, "%s %s %s ", num(points[i]), num(points[i+1]), op)
+	}
+	p.content.WriteString("S\n")
+}
+
+// WriteTo writes the document.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var b bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// Objects 1 to 4 are fixed; each page then takes two, itself and its
+	// content.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = strconv.Itoa(5+2*i) + " 0 R"
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(d.pages), num(d.Size.Width), num(d.Size.Height)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, p := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.Bytes()))
+	}
+	object(fmt.Sprintf("<< /Title (%s) /Producer (personal-todo) >>", escape(d.Title)))
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, len(offsets), xref)
+	return b.WriteTo(w)
+}
+
+// Width is how wide s is in f at size. Bold text is measured with the
+// regular widths, widened a little.
+func Width(f Font, size float64, s string) float64 {
+	var units int
+	for _, c := range encode(s) {
+		if c >= 32 && c < 127 {
+			units += widths[c-32]
+		} else {
+			units += 556
+		}
+	}
+	w := float64(units) * size / 1000
+	if f == Bold {
+		w *= 1.08
+	}
+	return w
+}
+
+// widths are the Helvetica widths of the characters from ' ' to '~', in
+// thousandths of the font size.
+var widths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// cp1252 maps the characters Windows-1252 puts in 0x80 to 0x9f.
+var cp1252 = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// encode turns s into Windows-1252, which WinAnsiEncoding is.
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, c := range s {
+		switch b, ok := cp1252[c]; {
+		case ok:
+			out = append(out, b)
+		case c < 0x80 && c >= ' ', c >= 0xa0 && c <= 0xff:
+			out = append(out, byte(c))
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// escape makes s a PDF string literal's contents.
+func escape(s string) string {
+	var b strings.Builder
+	for _, c := range encode(s) {
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// num formats f to two decimals, without trailing zeros.
+func num(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 2, 64)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
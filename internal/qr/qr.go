@@ -0,0 +1,429 @@
+// Package qr draws QR codes for the short texts the server hands out, such
+// as share links. It encodes bytes at error correction level M in versions
+// 1 to 10, which hold up to 213 bytes.
+package qr
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+// ErrTooLong is returned by Encode for a text beyond the largest version.
+var ErrTooLong = errors.New("qr: the text is too long")
+
+// version describes the level M blocks of one version: blocks1 blocks of
+// data1 data codewords, then blocks2 of one more, each with ec error
+// correction codewords.
+type version struct {
+	ec, blocks1, data1, blocks2 int
+	// align is where the alignment patterns' centres lie on each axis.
+	align []int
+}
+
+var versions = []version{
+	1:  {10, 1, 16, 0, nil},
+	2:  {16, 1, 28, 0, []int{6, 18}},
+	3:  {26, 1, 44, 0, []int{6, 22}},
+	4:  {18, 2, 32, 0, []int{6, 26}},
+	5:  {24, 2, 43, 0, []int{6, 30}},
+	6:  {16, 4, 27, 0, []int{6, 34}},
+	7:  {18, 4, 31, 0, []int{6, 22, 38}},
+	8:  {22, 2, 38, 2, []int{6, 24, 42}},
+	9:  {22, 3, 36, 2, []int{6, 26, 46}},
+	10: {26, 4, 43, 1, []int{6, 28, 50}},
+}
+
+func (v version) dataCodewords() int {
+	return v.blocks1*v.data1 + v.blocks2*(v.data1+1)
+}
+
+// Code is a QR code: Size by Size modules, without the quiet zone.
+type Code struct {
+	Size    int
+	modules []bool
+	// function marks the modules of the fixed patterns, which data and
+	// masks leave alone.
+	function []bool
+}
+
+// Encode makes the smallest QR code that holds text.
+func Encode(text string) (*Code, error) {
+	n := len(text)
+	for ver := 1; ver < len(versions); ver++ {
+		v := versions[ver]
+		countBits := 8
+		if ver >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*n > 8*v.dataCodewords() {
+			continue
+		}
+		var b bitBuffer
+		b.append(0x4, 4) // byte mode
+		b.append(n, countBits)
+		for i := 0; i < n; i++ {
+			b.append(int(text[i]), 8)
+		}
+		c := newCode(ver)
+		c.drawCodewords(interleave(v, b.codewords(v.dataCodewords())))
+		c.applyBestMask()
+		return c, nil
+	}
+	return nil, ErrTooLong
+}
+
+// Black reports whether the module in column x of row y is dark.
+func (c *Code) Black(x, y int) bool {
+	return c.modules[y*c.Size+x]
+}
+
+// Image draws c with each module scale pixels wide, inside the four module
+// quiet zone readers need.
+func (c *Code) Image(scale int) image.Image {
+	const quiet = 4
+	side := (c.Size + 2*quiet) * scale
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.Black(x, y) {
+				continue
+			}
+			for py := (y + quiet) * scale; py < (y+quiet+1)*scale; py++ {
+				for px := (x + quiet) * scale; px < (x+quiet+1)*scale; px++ {
+					img.SetColorIndex(px, py, 1)
+				}
+			}
+		}
+	}
+	return img
+}
+
+func newCode(ver int) *Code {
+	size := 17 + 4*ver
+	c := &Code{Size: size, modules: make([]bool, size*size), function: make([]bool, size*size)}
+	for i := 0; i < size; i++ {
+		c.set(6, i, i%2 == 0)
+		c.set(i, 6, i%2 == 0)
+	}
+	c.drawFinder(3, 3)
+	c.drawFinder(size-4, 3)
+	c.drawFinder(3, size-4)
+	align := versions[ver].align
+	last := len(align) - 1
+	for i, y := range align {
+		for j, x := range align {
+			// The corners with finders get no alignment pattern.
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.set(x+dx, y+dy, ring(dx, dy) != 1)
+				}
+			}
+		}
+	}
+	// Reserve the format areas; applyBestMask fills them in.
+	c.drawFormat(0)
+	if ver >= 7 {
+		rem := ver
+		for i := 0; i < 12; i++ {
+			rem = rem<<1 ^ (rem>>11)*0x1f25
+		}
+		bits := ver<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := bits>>i&1 == 1
+			a, b := size-11+i%3, i/3
+			c.set(a, b, dark)
+			c.set(b, a, dark)
+		}
+	}
+	return c
+}
+
+// set places a module of a fixed pattern.
+func (c *Code) set(x, y int, dark bool) {
+	c.modules[y*c.Size+x] = dark
+	c.function[y*c.Size+x] = true
+}
+
+// drawFinder draws a finder pattern centred on x, y with its separator.
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			px, py := x+dx, y+dy
+			if px < 0 || px >= c.Size || py < 0 || py >= c.Size {
+				continue
+			}
+			d := ring(dx, dy)
+			c.set(px, py, d != 2 && d != 4)
+		}
+	}
+}
+
+// drawFormat writes both copies of the format information for level M
+// and mask, and the dark module.
+func (c *Code) drawFormat(mask int) {
+	data := mask // level M is 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+	for i := 0; i <= 5; i++ {
+		c.set(8, i, bit(i))
+	}
+	c.set(8, 7, bit(6))
+	c.set(8, 8, bit(7))
+	c.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		c.set(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.set(8, c.Size-15+i, bit(i))
+	}
+	c.set(8, c.Size-8, true)
+}
+
+// drawCodewords lays data out in the zigzag of two-module columns that
+// runs up and down from the bottom right corner.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if c.function[y*c.Size+x] || i >= len(data)*8 {
+					continue
+				}
+				c.modules[y*c.Size+x] = data[i>>3]>>(7-i&7)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// masks are the eight data mask conditions, by mask number.
+var masks = [8]func(x, y int) bool{
+	func(x, y int) bool { return (x+y)%2 == 0 },
+	func(x, y int) bool { return y%2 == 0 },
+	func(x, y int) bool { return x%3 == 0 },
+	func(x, y int) bool { return (x+y)%3 == 0 },
+	func(x, y int) bool { return (x/3+y/2)%2 == 0 },
+	func(x, y int) bool { return x*y%2+x*y%3 == 0 },
+	func(x, y int) bool { return (x*y%2+x*y%3)%2 == 0 },
+	func(x, y int) bool { return ((x+y)%2+x*y%3)%2 == 0 },
+}
+
+// applyMask flips the data modules mask selects; applying it twice
+// undoes it.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.function[y*c.Size+x] && masks[mask](x, y) {
+				c.modules[y*c.Size+x] = !c.modules[y*c.Size+x]
+			}
+		}
+	}
+}
+
+// applyBestMask applies the mask that leaves the fewest patterns that
+// confuse readers.
+func (c *Code) applyBestMask() {
+	best, lowest := 0, -1
+	for mask := range masks {
+		c.applyMask(mask)
+		c.drawFormat(mask)
+		if p := c.penalty(); lowest < 0 || p < lowest {
+			best, lowest = mask, p
+		}
+		c.applyMask(mask)
+	}
+	c.applyMask(best)
+	c.drawFormat(best)
+}
+
+// penalty scores c by the four rules of ISO/IEC 18004 section 7.8.3.
+func (c *Code) penalty() int {
+	n := c.Size
+	at := func(x, y int) bool { return c.modules[y*n+x] }
+	score, dark := 0, 0
+	finder := []bool{true, false, true, true, true, false, true}
+	for i := 0; i < n; i++ {
+		for _, line := range [2]func(j int) bool{
+			func(j int) bool { return at(j, i) },
+			func(j int) bool { return at(i, j) },
+		} {
+			run := 1
+			for j := 1; j <= n; j++ {
+				if j < n && line(j) == line(j-1) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += run - 2
+				}
+				run = 1
+			}
+			for j := 0; j+7 <= n; j++ {
+				found := true
+				for k, d := range finder {
+					if line(j+k) != d {
+						found = false
+						break
+					}
+				}
+				if found && (light(line, j-4, j, n) || light(line, j+7, j+11, n)) {
+					score += 40
+				}
+			}
+		}
+		for j := 0; j < n; j++ {
+			if at(j, i) {
+				dark++
+			}
+			if i+1 < n && j+1 < n && at(j, i) == at(j+1, i) && at(j, i) == at(j, i+1) && at(j, i) == at(j+1, i+1) {
+				score += 3
+			}
+		}
+	}
+	total := n * n
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	return score + k*10
+}
+
+// light reports whether modules from to to of line are all light, taking
+// those outside the code as light.
+func light(line func(int) bool, from, to, n int) bool {
+	for j := from; j < to; j++ {
+		if j >= 0 && j < n && line(j) {
+			return false
+		}
+	}
+	return true
+}
+
+// bitBuffer collects the data bit stream.
+type bitBuffer struct {
+	bits []bool
+}
+
+func (b *bitBuffer) append(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		b.bits = append(b.bits, v>>i&1 == 1)
+	}
+}
+
+// codewords ends the stream and pads it to n codewords.
+func (b *bitBuffer) codewords(n int) []byte {
+	end := 8*n - len(b.bits)
+	if end > 4 {
+		end = 4
+	}
+	b.append(0, end)
+	b.append(0, (8-len(b.bits)%8)%8)
+	for pad := 0xec; len(b.bits) < 8*n; pad ^= 0xec ^ 0x11 {
+		b.append(pad, 8)
+	}
+	out := make([]byte, n)
+	for i, bit := range b.bits {
+		if bit {
+			out[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return out
+}
+
+// interleave splits data into v's blocks, adds each block's error
+// correction and interleaves the result.
+func interleave(v version, data []byte) []byte {
+	var blocks, ecs [][]byte
+	for i := 0; i < v.blocks1+v.blocks2; i++ {
+		size := v.data1
+		if i >= v.blocks1 {
+			size++
+		}
+		block := data[:size]
+		data = data[size:]
+		blocks = append(blocks, block)
+		ecs = append(ecs, reedSolomon(block, v.ec))
+	}
+	var out []byte
+	for i := 0; i <= v.data1; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				out = append(out, block[i])
+			}
+		}
+	}
+	for i := 0; i < v.ec; i++ {
+		for _, ec := range ecs {
+			out = append(out, ec[i])
+		}
+	}
+	return out
+}
+
+// reedSolomon returns the n error correction codewords of data.
+func reedSolomon(data []byte, n int) []byte {
+	// The generator polynomial, highest degree first without its leading 1.
+	gen := make([]byte, n)
+	gen[n-1] = 1
+	root := byte(1)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			gen[j] = mul(gen[j], root)
+			if j+1 < n {
+				gen[j] ^= gen[j+1]
+			}
+		}
+		root = mul(root, 2)
+	}
+	rem := make([]byte, n)
+	for _, d := range data {
+		factor := d ^ rem[0]
+		copy(rem, rem[1:])
+		rem[n-1] = 0
+		for j := range rem {
+			rem[j] ^= mul(gen[j], factor)
+		}
+	}
+	return rem
+}
+
+// mul multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func mul(x, y byte) byte {
+	var z byte
+	for i := 7; i >= 0; i-- {
+		hi := z >> 7
+		z = z<<1 ^ hi*0x1d
+		z ^= (y >> i & 1) * x
+	}
+	return z
+}
+
+// ring is which square around a pattern's centre dx, dy lies on.
+func ring(dx, dy int) int {
+	if abs(dx) > abs(dy) {
+		return abs(dx)
+	}
+	return abs(dy)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
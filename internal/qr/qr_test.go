@@ -0,0 +1,190 @@
+package qr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	// The modules, row by row with '#' for dark, match those of an
+	// independent encoder given the same mask.
+	tests := []struct {
+		text    string
+		version int
+		want    []string
+	}{
+		{
+			"https://x.io/s", 1,
+			[]string{
+				"#######...#...#######",
+				"#.....#.##.#..#.....#",
+				"#.###.#..###..#.###.#",
+				"#.###.#..#....#.###.#",
+				"#.###.#.##..#.#.###.#",
+				"#.....#...##..#.....#",
+				"#######.#.#.#.#######",
+				"..........#.#........",
+				"#.#.#.#...#.#...#..#.",
+				"...###.####.#.###...#",
+				"#.#.#.###.####..#.###",
+				"#..#.....#..#...#..#.",
+				"#####.##.#.#.#.#.#...",
+				"........#########..##",
+				"#######..#..#.#.#.###",
+				"#.....#..#..##.##..##",
+				"#.###.#.####.....#.#.",
+				"#.###.#....##.#.##.#.",
+				"#.###.#.#####...#.#.#",
+				"#.....#...#.....#..#.",
+				"#######.###.....##.##",
+			},
+		},
+		{
+			"https://todo.example.com/share/" + strings.Repeat("Ab3xY9kQ", 10), 7,
+			[]string{
+				"#######..#.##.#..###.#.....#####.#..#.#######",
+				"#.....#..#.##..#..#.....####.#.#...#..#.....#",
+				"#.###.#.####.#.###..#..####.#..###.#..#.###.#",
+				"#.###.#.#.##.##.######.#..#..###...##.#.###.#",
+				"#.###.#.#.###.#..#.########.#####.###.#.###.#",
+				"#.....#.#.#.#.###.#.#...#..#.....#....#.....#",
+				"#######.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#######",
+				"........##..#.##.####...##.##..#.............",
+				"#.#####....#.###..#.######...###.#.##.#####..",
+				"#.......###.##.####..##.#..#..#....##...#.###",
+				"#.##.#####......#.#...##..#.##..####.###.###.",
+				"....##.#.#..#..##..##.....##...###....#.###..",
+				"..#..#####.####.###.#..#...#..#.....##.#....#",
+				".#.##...#.####.....###...#...###...##..##.#.#",
+				".###.##.#..##.###..##..###.#.##.########.###.",
+				".#.#.#..#.##.##.###..#.##..##...##..#.#.####.",
+				"###..##.#.#.###...#.#....##...##...#......#.#",
+				"###....##.#.##.#.#....####...##.#..###..#..#.",
+				".###..#......#.#...##.######...###.#.#.#.#.#.",
+				"..##.....##.#..##.##...#....#..#####.....####",
+				".#.######..##.#..#########.#.#....#########.#",
+				"..###...##.##########...#..####.#..##...###..",
+				"##.##.#.#.###...##..#.#.##.#.##.....#.#.#.##.",
+				".#.##...#.#.##.#....#...#####..##.#.#...#####",
+				".###########.##.##..#####.#..###..########.##",
+				"#####...#.#######.###.#..#.#.####...###...#.#",
+				"#..#..##.##.#.#.##...#....##.....#####.#...#.",
+				".#.#.#..##..##....#....##.#....#.#.#.####.###",
+				".##.#.#.##.#.#..#..##..#.#...###.##...#.##.##",
+				".#..#..####...#...#..###....#.#..#..#.#...###",
+				".##.####..##....#.#.##.#######..#.##...#.##..",
+				"#.#.#.......#..###......#.##..#.##....##.###.",
+				"##.##.####..###..#.....##....##.......###...#",
+				"#...##.#.#..#..#.#####..##...####..###....#.#",
+				"....#.###.###..##...#.##.#.#...#######...###.",
+				".####.....#.#.#..##..##.#####.#.##.##.##.###.",
+				"#..##.#..##..#...##.#####.#..###....#####.#.#",
+				"........###..#.#.####...###..###...##...###.#",
+				"#######..##...####.##.#.#..#..#..#.##.#.#.#..",
+				"#.....#.#####.#.##..#...#...#..##..##...####.",
+				"#.###.#.####.##.#...#######..#...#.#######.#.",
+				"#.###.#.##.##.##.#..#.####...##.#..##.#.#.#.#",
+				"#.###.#.##..#.#..#...#....#.###..#####.#.#.#.",
+				"#.....#..##########.##....###..###..#..####..",
+				"#######.##..#..#...#..####...###..#..###.#.#.",
+			},
+		},
+		{
+			"https://todo.example.com/share/" + strings.Repeat("Ab3xY9kQ", 20), 10,
+			[]string{
+				"#######..####...#.#.#..###..#####..##.....#.####..#######",
+				"#.....#........#.....#.#.#.##.####..#..#.#.....#..#.....#",
+				"#.###.#.#.#.#.#.#.#..##.......#..#.#.#..#..#####..#.###.#",
+				"#.###.#.#.##..########.##.#..#.##.#.##.#.###.#.#..#.###.#",
+				"#.###.#.####.#...#.##.###.#####.#..##.....##...#..#.###.#",
+				"#.....#.#...#..##..##.#.#.#...###.#...####.#..#...#.....#",
+				"#######.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#######",
+				"........#...##..#.##.##..##...#..#.#....#.###............",
+				"#.#####....#######.##.#..#######...###.#.##..#.#..#####..",
+				"..#....#.###.#.##..#.#.##.##.###.#.#.#.####.#...##.....#.",
+				".#..###.###..#...#.###.###.###.....#...#..#..##...####.#.",
+				"##..#....####..#.#..#...#..#...###..#.....##.##.#...###..",
+				"##....#####.#.#.######.####.###...##.##..#.#...###...##.#",
+				"###.#..#..#####.##......#..#####.....#..######.###..#.#.#",
+				"....#.######.#.#.#..#.####..#...####.#####..#.##..##...#.",
+				"#..#.....##....###......#.....######..###..#.#.....#..###",
+				"#...########....##.###...#...##.....##....##...####..#.##",
+				"....##.###.###.##..#.####.##.##.#....#.#.##....##......#.",
+				"..#####.#.#..##.#...#..###.##....##.#.#.#...##..####.###.",
+				".#.#.#.####...##.##.....#..##.#.##.####...#.##......#.#..",
+				"##.##.##.######.#####....##..##....##.#..#.#..##.#...#..#",
+				"#.##.#.##...#.##.###..###.....##...#.##.####...###.#....#",
+				".######.#.#.....#.#....###.#.###.##....###....##..##..##.",
+				"#..###.##...##..#.##.....#.##.##.######.....####..##.###.",
+				"...#####.##....##...#..#.....##..#.##.#..###...##....#...",
+				"##.#.#.####....####..####....###....##.#.##.#..###......#",
+				"..#######.##...#.#..#.##.######.......###..####.########.",
+				"#...#...###..###...####.###...##..#....##.#.#.#.#...#.##.",
+				"#..##.#.#.####.#.#####..#.#.#.##.##.##...###..#.#.#.##..#",
+				".##.#...##.#.##.#...##.####...###.####...###...##...#.###",
+				"#.#########.....##...##########.##.#..##.....##.########.",
+				"#...#..#.##.#.#.###.#.#.#####..#.####.###...####.#.#.####",
+				"##.#..#.#.#.##.#.#...##.#...#..#.#..#.....##......#.#.#..",
+				"#........#####.#..##.###..#...#....###...###....####..###",
+				"..##..##.#.....##.#####..##.###.###.##.####.###.##...#..#",
+				"######.###...###.#.##.##.#.#.##.###...###.####.#.#.####..",
+				"..######....#....#..#.#.###....#..#####..###........##...",
+				"...#...#...##.#..#...#.###.#.#.#...###.#.####...#.##...##",
+				".##.######..##.#..#.#.###.#..##.....##.#.....###...#####.",
+				".##.##.#...##.#..##.#.#.##.#.##.##.....##....###.###.####",
+				".#.#.######......#..####....#.##.#####....##.#....#......",
+				"...###.....#####..##..#.#.#.....##.##....###...##.#..##..",
+				"#..#.##....#....#.##.######.#.#...#..#.####.....#...#.##.",
+				".....#.....####.###.#.#....#.#...###....#...#####.######.",
+				"#####.###..#..##.#..##.#...#.#.##..####..#....#.....##...",
+				"####.#..#...##.####.#.#.#.#.....##.#.#..#####..#..#....##",
+				"#.#..###...#.#.##.....#..##..#.##.#.#.##.####..#...#.##..",
+				"#####..##...#.########..##.#..#.##.....#..#....#.##..###.",
+				"......#.####...####.#..##.######...##.#....#.#..#####...#",
+				"........###.##........###.#...##.#.#......##...##...#.###",
+				"#######......###.#.#.###..#.#.#.#..#.###.#...####.#.###..",
+				"#.....#.###.##..#.####....#...####..#.#.#..####.#...###.#",
+				"#.###.#.#.##.#.#..###.##########...#.#.#.##...#.#####....",
+				"#.###.#.#...#..#.#....#.##..#.###.........##....#.#......",
+				"#.###.#.#.....#..#.#.#.##.##......##.#####..#..###....#..",
+				"#.....#..#..###.....###.###...#..#..#...##.###.##...###..",
+				"#######.##.....##....#.###.###.#...###...##..##.########.",
+			},
+		},
+	}
+	for _, tt := range tests {
+		c, err := Encode(tt.text)
+		if err != nil {
+			t.Errorf("Encode(%d bytes): %v", len(tt.text), err)
+			continue
+		}
+		if want := 17 + 4*tt.version; c.Size != want {
+			t.Errorf("Encode(%d bytes).Size = %d, want %d", len(tt.text), c.Size, want)
+			continue
+		}
+		for y, row := range tt.want {
+			var got strings.Builder
+			for x := 0; x < c.Size; x++ {
+				if c.Black(x, y) {
+					got.WriteByte('#')
+				} else {
+					got.WriteByte('.')
+				}
+			}
+			if got.String() != row {
+				t.Errorf("Encode(%d bytes) row %d = %s, want %s", len(tt.text), y, got.String(), row)
+			}
+		}
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	// Version 10 holds 213 bytes at level M.
+	if _, err := Encode(strings.Repeat("a", 213)); err != nil {
+		t.Errorf("Encode(213 bytes): %v", err)
+	}
+	if _, err := Encode(strings.Repeat("a", 214)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Encode(214 bytes) = %v, want ErrTooLong", err)
+	}
+}
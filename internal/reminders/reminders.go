@@ -0,0 +1,173 @@
+// Package reminders reads the ICS and CSV exports of Apple Reminders into
+// todos.
+package reminders
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/ical"
+	"dhruvarora9/personal-todo-golang/internal/nldate"
+	"dhruvarora9/personal-todo-golang/internal/service"
+)
+
+// Priority tags, as Reminders' priorities have no field of their own here.
+const (
+	TagHigh   = "priority-high"
+	TagMedium = "priority-medium"
+	TagLow    = "priority-low"
+)
+
+// ErrNoTitle is returned by ParseCSV for a header without a title column.
+var ErrNoTitle = errors.New("reminders: the CSV has no title column")
+
+// ParseICS reads the VTODOs of an iCalendar export. Their list is the
+// calendar's X-WR-CALNAME; times without a zone are read in loc.
+func ParseICS(r io.Reader, loc *time.Location) ([]service.ImportTask, error) {
+	var out []service.ImportTask
+	var list string
+	var cur *service.ImportTask
+	err := ical.Scan(r, func(p ical.Prop) {
+		switch {
+		case p.Name == "X-WR-CALNAME":
+			list = p.Text()
+		case p.Name == "BEGIN" && p.Value == "VTODO":
+			cur = &service.ImportTask{TodoInput: service.TodoInput{List: list}}
+		case cur == nil:
+		case p.Name == "END" && p.Value == "VTODO":
+			out = append(out, *cur)
+			cur = nil
+		case p.Name == "SUMMARY":
+			cur.Title = p.Text()
+		case p.Name == "DUE":
+			cur.DueAt = p.Due(loc)
+		case p.Name == "STATUS":
+			cur.Completed = p.Value == "COMPLETED"
+		case p.Name == "COMPLETED":
+			cur.Completed = true
+		case p.Name == "PRIORITY":
+			cur.Tags = appendPriority(cur.Tags, p.Value)
+		case p.Name == "CATEGORIES":
+			for _, c := range p.List() {
+				if tag := service.TagSlug(c); tag != "" {
+					cur.Tags = append(cur.Tags, tag)
+				}
+			}
+		}
+	})
+	return out, err
+}
+
+// appendPriority adds the tag for priority p: 1 to 9 as in iCalendar, where
+// Reminders writes 1, 5 and 9, or high, medium and low, or "!!!" to "!".
+func appendPriority(tags []string, p string) []string {
+	switch p = strings.ToLower(strings.TrimSpace(p)); p {
+	case "high", "!!!":
+		return append(tags, TagHigh)
+	case "medium", "!!":
+		return append(tags, TagMedium)
+	case "low", "!":
+		return append(tags, TagLow)
+	}
+	n, err := strconv.Atoi(p)
+	switch {
+	case err != nil || n <= 0:
+		return tags
+	case n < 5:
+		return append(tags, TagHigh)
+	case n == 5:
+		return append(tags, TagMedium)
+	}
+	return append(tags, TagLow)
+}
+
+// csvLayouts are the due date formats ParseCSV tries before nldate.
+var csvLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"1/2/2006 15:04",
+	"1/2/2006 3:04 PM",
+	"1/2/2006",
+}
+
+// ParseCSV reads a CSV export with a header row. Columns are matched by
+// name, ignoring case: title (or name or reminder), list, due (or due
+// date), priority, completed (or done) and tags. Due dates without a zone
+// are read in loc; a date alone is due at nldate.DefaultHour.
+func ParseCSV(r io.Reader, loc *time.Location, now time.Time) ([]service.ImportTask, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		switch h {
+		case "name", "reminder":
+			h = "title"
+		case "due date":
+			h = "due"
+		case "done":
+			h = "completed"
+		}
+		if _, ok := col[h]; !ok {
+			col[h] = i
+		}
+	}
+	if _, ok := col["title"]; !ok {
+		return nil, ErrNoTitle
+	}
+	var out []service.ImportTask
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		get := func(name string) string {
+			if i, ok := col[name]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		t := service.ImportTask{TodoInput: service.TodoInput{Title: get("title"), List: get("list")}}
+		if due := get("due"); due != "" {
+			t.DueAt = csvTime(due, loc, now)
+		}
+		switch strings.ToLower(get("completed")) {
+		case "true", "yes", "1", "x":
+			t.Completed = true
+		}
+		t.Tags = appendPriority(nil, get("priority"))
+		for _, tag := range strings.Split(get("tags"), ",") {
+			if tag = service.TagSlug(tag); tag != "" {
+				t.Tags = append(t.Tags, tag)
+			}
+		}
+		out = append(out, t)
+	}
+}
+
+func csvTime(s string, loc *time.Location, now time.Time) *time.Time {
+	for _, layout := range csvLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			if !strings.Contains(layout, "15") && !strings.Contains(layout, "3:04") {
+				t = t.Add(nldate.DefaultHour * time.Hour)
+			}
+			return &t
+		}
+	}
+	if t, err := nldate.Parse(s, now.In(loc)); err == nil {
+		return &t
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+// Package render writes HTTP responses in the shapes the API promises:
+// successful JSON responses come in an envelope, errors are RFC 7807
+// problem documents, and pages come from html/template.
+//
+// The envelope is {"data": ..., "meta": ...} or, for clients that want
+// none, the bare payload with any metadata left to headers. The server
+// picks one, and a request can ask for the other with a profile parameter
+// in Accept, such as `application/json; profile="bare"`.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+)
+
+const (
+	contentJSON    = "application/json; charset=utf-8"
+	contentProblem = "application/problem+json; charset=utf-8"
+	contentHTML    = "text/html; charset=utf-8"
+)
+
+// Envelopes successful JSON responses can come in.
+const (
+	// EnvelopeData wraps the payload as {"data": ...}, with its metadata,
+	// if any, beside it in "meta".
+	EnvelopeData = "data"
+	// EnvelopeBare sends the payload alone.
+	EnvelopeBare = "bare"
+)
+
+// M is a convenient map for ad-hoc JSON objects.
+type M map[string]interface{}
+
+// Problem is an RFC 7807 problem document.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type Renderer struct {
+	templates *template.Template
+	envelope  string
+	// localized holds a clone of templates per locale, translating into
+	// it; templates itself is never executed, so it can still be cloned.
+	localized sync.Map
+}
+
+// New returns a Renderer that executes pages from templates, which may be nil
+// if HTML is never rendered, and sends data in envelope unless a request
+// asks for another. An empty envelope is EnvelopeData.
+func New(templates *template.Template, envelope string) *Renderer {
+	if envelope == "" {
+		envelope = EnvelopeData
+	}
+	return &Renderer{templates: templates, envelope: envelope}
+}
+
+// envelopeFor returns the envelope req asks for in its Accept header, the
+// Renderer's own if it names none it knows.
+func (r *Renderer) envelopeFor(req *http.Request) string {
+	for _, part := range strings.Split(req.Header.Get("Accept"), ",") {
+		_, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		switch p := params["profile"]; p {
+		case EnvelopeData, EnvelopeBare:
+			return p
+		}
+	}
+	return r.envelope
+}
+
+func write(w http.ResponseWriter, status int, contentType string, body []byte) error {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, err := w.Write(body)
+	return err
+}
+
+// buffers holds response buffers for reuse, sparing each request the
+// allocations of growing a fresh one.
+var buffers = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+func getBuffer() *bytes.Buffer {
+	return buffers.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	// Don't let one huge response pin its memory in the pool.
+	if buf.Cap() > 64<<10 {
+		return
+	}
+	buf.Reset()
+	buffers.Put(buf)
+}
+
+func writeJSON(w http.ResponseWriter, status int, contentType string, v interface{}) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	// Encode adds the trailing newline.
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
+	}
+	return write(w, status, contentType, buf.Bytes())
+}
+
+// JSON writes v as is. Prefer Data for API responses.
+func (r *Renderer) JSON(w http.ResponseWriter, status int, v interface{}) error {
+	return writeJSON(w, status, contentJSON, v)
+}
+
+// Data writes v, a successful API response, in the envelope req asks for.
+func (r *Renderer) Data(w http.ResponseWriter, req *http.Request, status int, v interface{}) error {
+	return r.DataMeta(w, req, status, v, nil)
+}
+
+// DataMeta is Data with metadata about v, such as the cursor of the next
+// page. A bare response leaves meta out, so it should repeat what a
+// header already says.
+func (r *Renderer) DataMeta(w http.ResponseWriter, req *http.Request, status int, v interface{}, meta M) error {
+	w.Header().Add("Vary", "Accept")
+	if r.envelopeFor(req) == EnvelopeBare {
+		return writeJSON(w, status, contentJSON, v)
+	}
+	return writeJSON(w, status, contentJSON, envelope{Data: v, Meta: meta})
+}
+
+// envelope is {"data": v, "meta": meta} without the cost of building a map.
+type envelope struct {
+	Data interface{} `json:"data"`
+	Meta M           `json:"meta,omitempty"`
+}
+
+// ListWriter streams a Data response of a JSON array one item at a time.
+// Nothing is sent until the first item, so a failure before then can still
+// be answered with Problem.
+type ListWriter struct {
+	w      http.ResponseWriter
+	status int
+	bare   bool
+	meta   M
+	n      int
+	buf    *bytes.Buffer
+}
+
+// List starts a streamed Data response of a JSON array in the envelope req
+// asks for. Call Close when the items run out.
+func (r *Renderer) List(w http.ResponseWriter, req *http.Request, status int) *ListWriter {
+	w.Header().Add("Vary", "Accept")
+	return &ListWriter{w: w, status: status, bare: r.envelopeFor(req) == EnvelopeBare}
+}
+
+// Meta sets the metadata Close sends after the items, as DataMeta does.
+func (l *ListWriter) Meta(meta M) {
+	l.meta = meta
+}
+
+// Started reports whether the response has been committed.
+func (l *ListWriter) Started() bool {
+	return l.n > 0
+}
+
+func (l *ListWriter) Write(v interface{}) error {
+	if l.buf == nil {
+		l.buf = getBuffer()
+	}
+	buf := l.buf
+	buf.Reset()
+	switch {
+	case l.n == 0 && l.bare:
+		buf.WriteByte('[')
+	case l.n == 0:
+		buf.WriteString(`{"data":[`)
+	default:
+		buf.WriteByte(',')
+	}
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return err
+	}
+	buf.Truncate(buf.Len() - 1) // Encode's newline
+	if l.n == 0 {
+		l.w.Header().Set("Content-Type", contentJSON)
+		l.w.WriteHeader(l.status)
+	}
+	l.n++
+	_, err := l.w.Write(buf.Bytes())
+	return err
+}
+
+// Close ends the array, sending an empty one if nothing was written, and
+// then the metadata.
+func (l *ListWriter) Close() error {
+	buf := l.buf
+	if buf == nil {
+		buf = getBuffer()
+	}
+	l.buf = nil
+	defer putBuffer(buf)
+	buf.Reset()
+	switch {
+	case l.n == 0 && l.bare:
+		buf.WriteByte('[')
+	case l.n == 0:
+		buf.WriteString(`{"data":[`)
+	}
+	buf.WriteByte(']')
+	if !l.bare {
+		if len(l.meta) > 0 {
+			buf.WriteString(`,"meta":`)
+			if err := json.NewEncoder(buf).Encode(l.meta); err != nil {
+				return err
+			}
+			buf.Truncate(buf.Len() - 1)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte('\n')
+	if l.n == 0 {
+		return write(l.w, l.status, contentJSON, buf.Bytes())
+	}
+	_, err := l.w.Write(buf.Bytes())
+	return err
+}
+
+// Problem writes an error response. detail is shown to the client and should
+// say what went wrong in plain words; it is translated into the locale of
+// req and, given args, formatted with them.
+func (r *Renderer) Problem(w http.ResponseWriter, req *http.Request, status int, detail string, args ...interface{}) error {
+	return writeJSON(w, status, contentProblem, Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: i18n.T(i18n.FromContext(req.Context()), detail, args...),
+	})
+}
+
+// NoContent answers with a bodyless 204.
+func (r *Renderer) NoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HTML executes the named template in the locale of req. Nothing is
+// written if it fails, so the caller can still send an error response.
+func (r *Renderer) HTML(w http.ResponseWriter, req *http.Request, status int, name string, data interface{}) error {
+	tpl, err := r.in(i18n.FromContext(req.Context()))
+	if err != nil {
+		return err
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := tpl.ExecuteTemplate(buf, name, data); err != nil {
+		return err
+	}
+	return write(w, status, contentHTML, buf.Bytes())
+}
+
+// in returns the templates translating into locale, cloning them on first
+// use.
+func (r *Renderer) in(locale string) (*template.Template, error) {
+	if t, ok := r.localized.Load(locale); ok {
+		return t.(*template.Template), nil
+	}
+	t, err := r.templates.Clone()
+	if err != nil {
+		return nil, err
+	}
+	t.Funcs(i18n.Funcs(locale))
+	t2, _ := r.localized.LoadOrStore(locale, t)
+	return t2.(*template.Template), nil
+}
@@ -0,0 +1,81 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type benchTodo struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Completed bool      `json:"completed"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"createAt"`
+}
+
+func benchTodos(n int) []benchTodo {
+	out := make([]benchTodo, n)
+	for i := range out {
+		out[i] = benchTodo{ID: "65f0c0ffee0000000000abcd", Title: "Water the plants", Tags: []string{"home"}, CreatedAt: time.Unix(1700000000, 0)}
+	}
+	return out
+}
+
+// discard is a ResponseWriter that keeps nothing, so the benchmarks count
+// the renderer's allocations rather than a recorder's.
+type discard struct{ h http.Header }
+
+func (d *discard) Header() http.Header         { return d.h }
+func (d *discard) Write(b []byte) (int, error) { return len(b), nil }
+func (d *discard) WriteHeader(int)             {}
+
+func BenchmarkData(b *testing.B) {
+	r := New(nil, EnvelopeData)
+	req := httptest.NewRequest(http.MethodGet, "/todo", nil)
+	todos := benchTodos(100)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := r.Data(&discard{h: http.Header{}}, req, http.StatusOK, todos); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDataUnpooled is Data as it was before buffers were pooled, the
+// baseline BenchmarkData's allocations compare with.
+func BenchmarkDataUnpooled(b *testing.B) {
+	todos := benchTodos(100)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(map[string]interface{}{"data": todos}); err != nil {
+			b.Fatal(err)
+		}
+		w := &discard{h: http.Header{}}
+		w.Header().Set("Content-Type", contentJSON)
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes())
+	}
+}
+
+func BenchmarkList(b *testing.B) {
+	r := New(nil, EnvelopeData)
+	req := httptest.NewRequest(http.MethodGet, "/todo", nil)
+	todos := benchTodos(100)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l := r.List(&discard{h: http.Header{}}, req, http.StatusOK)
+		for j := range todos {
+			if err := l.Write(todos[j]); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if err := l.Close(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseNext(t *testing.T) {
+	// Wednesday 2026-10-14 at 15:30:20.
+	from := time.Date(2026, time.October, 14, 15, 30, 20, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, time.October, 14, 15, 31, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2026, time.October, 14, 16, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, time.October, 14, 16, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, time.October, 14, 15, 45, 0, 0, time.UTC)},
+		{"30 15 * * *", time.Date(2026, time.October, 15, 15, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2026, time.October, 14, 17, 0, 0, 0, time.UTC)},
+		{"0 7 * * 1,5", time.Date(2026, time.October, 16, 7, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either one matches.
+		{"0 0 20 * 4", time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@every 90m", time.Date(2026, time.October, 14, 17, 0, 20, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.spec)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Parse(%q).Next = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every",
+		"@every 10ms",
+		"@every soon",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestNextNever(t *testing.T) {
+	s, err := Parse("0 0 31 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Next(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("Next = %v, want the zero time for a day that never comes", got)
+	}
+}
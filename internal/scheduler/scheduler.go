@@ -0,0 +1,165 @@
+// Package scheduler runs periodic maintenance tasks on cron-style schedules.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TaskFunc does one run of a task. ctx is cancelled when the scheduler
+// stops.
+type TaskFunc func(ctx context.Context) error
+
+// Stats describes a task and how its runs have gone.
+type Stats struct {
+	Name         string    `json:"name"`
+	Schedule     string    `json:"schedule"`
+	NextRun      time.Time `json:"next_run"`
+	LastRun      time.Time `json:"last_run,omitempty"`
+	LastDuration float64   `json:"last_duration_ms"`
+	LastError    string    `json:"last_error,omitempty"`
+	Runs         int       `json:"runs"`
+	Failures     int       `json:"failures"`
+}
+
+type task struct {
+	fn       TaskFunc
+	schedule Schedule
+	stats    Stats
+}
+
+// Scheduler runs each registered task in its own goroutine, one run at a
+// time, so a slow run delays the next one instead of overlapping it.
+type Scheduler struct {
+	mu    sync.Mutex
+	specs map[string]string
+	tasks map[string]*task
+	log   *log.Logger
+	now   func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a scheduler; specs overrides the default schedule of tasks by
+// name, and the spec "off" disables a task.
+func New(specs map[string]string, logger *log.Logger, now func() time.Time) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		specs:  specs,
+		tasks:  map[string]*task{},
+		log:    logger,
+		now:    now,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Register adds a task that runs on defaultSpec unless the config says
+// otherwise. It must be called before Start.
+func (s *Scheduler) Register(name, defaultSpec string, fn TaskFunc) error {
+	spec := defaultSpec
+	if override, ok := s.specs[name]; ok {
+		spec = override
+	}
+	if spec == "off" {
+		return nil
+	}
+	sched, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("scheduler task %s: %w", name, err)
+	}
+	s.tasks[name] = &task{fn: fn, schedule: sched, stats: Stats{Name: name, Schedule: spec}}
+	return nil
+}
+
+func (s *Scheduler) Start() {
+	for _, t := range s.tasks {
+		s.wg.Add(1)
+		go s.loop(t)
+	}
+}
+
+// Stop cancels running tasks and waits for them to return, or for ctx to
+// expire.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.cancel()
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(t *task) {
+	defer s.wg.Done()
+	for {
+		next := t.schedule.Next(s.now())
+		if next.IsZero() {
+			s.log.Printf("scheduler: %s never runs again", t.stats.Name)
+			return
+		}
+		s.mu.Lock()
+		t.stats.NextRun = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.run(t)
+	}
+}
+
+func (s *Scheduler) run(t *task) {
+	start := s.now()
+	err := s.call(t.fn)
+	elapsed := s.now().Sub(start)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.stats.Runs++
+	t.stats.LastRun = start
+	t.stats.LastDuration = float64(elapsed.Microseconds()) / 1000
+	t.stats.LastError = ""
+	if err != nil {
+		t.stats.Failures++
+		t.stats.LastError = err.Error()
+		s.log.Printf("scheduler: %s failed after %s: %v", t.stats.Name, elapsed, err)
+	}
+}
+
+func (s *Scheduler) call(fn TaskFunc) (err error) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			err = fmt.Errorf("panic: %v", rvr)
+		}
+	}()
+	return fn(s.ctx)
+}
+
+// Stats returns every task's statistics, sorted by name.
+func (s *Scheduler) Stats() []Stats {
+	s.mu.Lock()
+	out := make([]Stats, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		out = append(out, t.stats)
+	}
+	s.mu.Unlock()
+	sort.Slice(out, func(a, b int) bool { return out[a].Name < out[b].Name })
+	return out
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var (
+	// ErrBlocked is returned when completing a todo that others not yet
+	// done block.
+	ErrBlocked = errors.New("the todo is blocked by todos not yet done")
+	// ErrCycle is returned when a blocker would end up blocking itself.
+	ErrCycle = errors.New("the todo already blocks that one, directly or through others")
+)
+
+// maxBlockers caps the todos that may block one todo.
+const maxBlockers = 50
+
+// checkUnblocked returns ErrBlocked if saving before as completed would
+// complete it while a blocker is still open. Deleted blockers don't count.
+func (s *TodoService) checkUnblocked(ctx context.Context, before *model.Todo, completed bool) error {
+	if !completed || before.Completed || len(before.BlockedBy) == 0 {
+		return nil
+	}
+	blockers, err := s.store.GetTodos(ctx, before.BlockedBy)
+	if err != nil {
+		return err
+	}
+	for _, b := range blockers {
+		if !b.Completed {
+			return ErrBlocked
+		}
+	}
+	return nil
+}
+
+// AddBlocker records that the todo blockerID must be done before the todo
+// id can be. It refuses links that would form a cycle.
+func (s *TodoService) AddBlocker(ctx context.Context, id, blockerID string) error {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return err
+	}
+	bid, err := store.ParseID(blockerID)
+	if err != nil {
+		return err
+	}
+	if oid == bid {
+		return &ValidationError{Field: "blocker", Message: "A todo can't block itself"}
+	}
+	found, err := s.store.GetTodos(ctx, []bson.ObjectID{oid, bid})
+	if err != nil {
+		return err
+	}
+	if len(found) != 2 {
+		return store.ErrNotFound
+	}
+	edges, err := s.store.BlockerEdges(ctx)
+	if err != nil {
+		return err
+	}
+	if len(edges[oid]) >= maxBlockers {
+		return &ValidationError{Field: "blocker", Message: "A todo may have at most 50 blockers"}
+	}
+	if blocks(edges, oid, bid) {
+		return ErrCycle
+	}
+	return s.store.AddBlocker(ctx, oid, bid)
+}
+
+// blocks reports whether a blocks b, directly or through other todos,
+// following the edges from b to its blockers.
+func blocks(edges map[bson.ObjectID][]bson.ObjectID, a, b bson.ObjectID) bool {
+	seen := map[bson.ObjectID]bool{}
+	stack := []bson.ObjectID{b}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, m := range edges[n] {
+			if m == a {
+				return true
+			}
+			if !seen[m] {
+				seen[m] = true
+				stack = append(stack, m)
+			}
+		}
+	}
+	return false
+}
+
+// RemoveBlocker undoes AddBlocker.
+func (s *TodoService) RemoveBlocker(ctx context.Context, id, blockerID string) error {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return err
+	}
+	bid, err := store.ParseID(blockerID)
+	if err != nil {
+		return err
+	}
+	return s.store.RemoveBlocker(ctx, oid, bid)
+}
+
+// DependencyGraph is every todo that blocks or is blocked by another, and
+// the links between them.
+type DependencyGraph struct {
+	Todos []model.Todo
+	// Edges map a todo to those blocking it.
+	Edges map[bson.ObjectID][]bson.ObjectID
+}
+
+// Dependencies returns the dependency graph.
+func (s *TodoService) Dependencies(ctx context.Context) (*DependencyGraph, error) {
+	edges, err := s.store.BlockerEdges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ids []bson.ObjectID
+	seen := map[bson.ObjectID]bool{}
+	add := func(id bson.ObjectID) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for id, blockers := range edges {
+		add(id)
+		for _, b := range blockers {
+			add(b)
+		}
+	}
+	g := &DependencyGraph{Todos: []model.Todo{}, Edges: edges}
+	if len(ids) == 0 {
+		return g, nil
+	}
+	if g.Todos, err = s.store.GetTodos(ctx, ids); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
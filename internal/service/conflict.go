@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+)
+
+// Strategies for a write made against an out-of-date copy of a todo.
+const (
+	// ConflictLastWriteWins applies the write regardless; it is the
+	// default.
+	ConflictLastWriteWins = "last_write_wins"
+	// ConflictMerge applies the fields the writer changed on top of the
+	// todo as it is now, failing only if both sides changed a field
+	// differently. It needs Base.Values.
+	ConflictMerge = "merge"
+	// ConflictReject fails the write, reporting the todo as it is now.
+	ConflictReject = "reject"
+)
+
+// ConflictStrategies lists every strategy.
+var ConflictStrategies = []string{ConflictLastWriteWins, ConflictMerge, ConflictReject}
+
+// Base is the copy of a todo a write was made against.
+type Base struct {
+	// UpdatedAt is the copy's; the write conflicts if the todo has been
+	// updated since.
+	UpdatedAt time.Time
+	// Values are the copy's, which a merge needs to tell who changed what.
+	Values *TodoInput
+}
+
+// ConflictError is returned for a write that conflicts with changes made
+// since its base and that its strategy doesn't settle.
+type ConflictError struct {
+	// Current is the todo as it is now.
+	Current *model.Todo
+	// Fields changed on both sides, if known.
+	Fields []string
+}
+
+func (e *ConflictError) Error() string {
+	return e.In("")
+}
+
+// In returns the message translated into locale.
+func (e *ConflictError) In(locale string) string {
+	if len(e.Fields) > 0 {
+		return i18n.T(locale, "the todo was changed meanwhile: %s", strings.Join(e.Fields, ", "))
+	}
+	return i18n.T(locale, "the todo was changed meanwhile")
+}
+
+func validateStrategy(strategy string) error {
+	for _, s := range ConflictStrategies {
+		if strategy == s {
+			return nil
+		}
+	}
+	if strategy == "" {
+		return nil
+	}
+	return NewValidationError("on_conflict", "on_conflict must be one of %s", strings.Join(ConflictStrategies, ", "))
+}
+
+// inputOf is t as input, so a write can start from it.
+func inputOf(t *model.Todo) TodoInput {
+	return TodoInput{
+		Title:           t.Title,
+		List:            t.List,
+		Completed:       t.Completed,
+		Status:          t.CurrentStatus(),
+		DueAt:           t.DueAt,
+		Tags:            t.Tags,
+		Notes:           t.Notes,
+		EstimateMinutes: t.EstimateMinutes,
+		Fields:          t.Fields,
+		Geofence:        t.Geofence,
+	}
+}
+
+// mergeField is a field a merge compares and copies.
+type mergeField struct {
+	name string
+	get  func(*TodoInput) interface{}
+	set  func(dst, src *TodoInput)
+}
+
+var mergeFields = []mergeField{
+	{"title", func(in *TodoInput) interface{} { return strings.TrimSpace(in.Title) }, func(d, s *TodoInput) { d.Title = s.Title }},
+	{"list", func(in *TodoInput) interface{} { return strings.TrimSpace(in.List) }, func(d, s *TodoInput) { d.List = s.List }},
+	{"status", func(in *TodoInput) interface{} {
+		status, _ := resolveStatus(in.Status, in.Completed, model.StatusTodo)
+		return status
+	}, func(d, s *TodoInput) { d.Status, d.Completed = s.Status, s.Completed }},
+	{"due_at", func(in *TodoInput) interface{} {
+		if in.DueAt == nil {
+			return nil
+		}
+		return in.DueAt.UnixNano()
+	}, func(d, s *TodoInput) { d.DueAt = s.DueAt }},
+	{"tags", func(in *TodoInput) interface{} {
+		tags, _ := normalizeTags("tags", in.Tags)
+		return tags
+	}, func(d, s *TodoInput) { d.Tags = s.Tags }},
+	{"notes", func(in *TodoInput) interface{} { return in.Notes }, func(d, s *TodoInput) { d.Notes = s.Notes }},
+	{"estimate_minutes", func(in *TodoInput) interface{} { return in.EstimateMinutes }, func(d, s *TodoInput) { d.EstimateMinutes = s.EstimateMinutes }},
+	{"fields", func(in *TodoInput) interface{} {
+		if len(in.Fields) == 0 {
+			return nil
+		}
+		return in.Fields
+	}, func(d, s *TodoInput) { d.Fields = s.Fields }},
+	{"geofence", func(in *TodoInput) interface{} {
+		if in.Geofence == nil {
+			return nil
+		}
+		return *in.Geofence
+	}, func(d, s *TodoInput) { d.Geofence = s.Geofence }},
+}
+
+// resolve returns what to write over current for in, written against base
+// (nil if unknown), settling a conflict by strategy.
+func resolve(strategy string, current *model.Todo, in TodoInput, base *Base) (TodoInput, error) {
+	if base == nil || base.UpdatedAt.Equal(current.UpdatedAt) {
+		return in, nil
+	}
+	switch strategy {
+	case ConflictReject:
+		return in, &ConflictError{Current: current}
+	case ConflictMerge:
+		if base.Values == nil {
+			return in, &ValidationError{Field: "base", Message: "A merge needs the base todo"}
+		}
+	default:
+		return in, nil
+	}
+	now := inputOf(current)
+	merged := now
+	var clashes []string
+	for _, f := range mergeFields {
+		was, mine, theirs := f.get(base.Values), f.get(&in), f.get(&now)
+		if reflect.DeepEqual(was, mine) {
+			continue // left alone by the writer
+		}
+		if !reflect.DeepEqual(was, theirs) && !reflect.DeepEqual(mine, theirs) {
+			clashes = append(clashes, f.name)
+			continue
+		}
+		f.set(&merged, &in)
+	}
+	if len(clashes) > 0 {
+		return in, &ConflictError{Current: current, Fields: clashes}
+	}
+	return merged, nil
+}
+
+// conflictRetries is how many times UpdateAgainst reads the todo again and
+// settles anew when another write lands between its read and its own.
+const conflictRetries = 3
+
+// UpdateAgainst is Update for a write made against base, settling a
+// conflict by strategy. The write only lands if the todo is still as it
+// was settled against; if not, it is read and settled again.
+func (s *TodoService) UpdateAgainst(ctx context.Context, id string, in TodoInput, base *Base, strategy string) (*model.Todo, error) {
+	if err := validateStrategy(strategy); err != nil {
+		return nil, err
+	}
+	if base == nil {
+		return s.Update(ctx, id, in)
+	}
+	for attempt := 0; ; attempt++ {
+		current, err := s.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if attempt == conflictRetries {
+			return nil, &ConflictError{Current: current}
+		}
+		resolved, err := resolve(strategy, current, in, base)
+		if err != nil {
+			return nil, err
+		}
+		t, err := s.update(ctx, id, resolved, current)
+		if !errors.Is(err, store.ErrNotFound) {
+			return t, err
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// maxDigestTodos caps each section of a digest.
+const maxDigestTodos = 50
+
+// digestHour is the hour of the day, in each tenant's time zone, digests
+// go out at.
+const digestHour = 7
+
+// SendDigests publishes a DailyDigest event for every tenant with an
+// integration asking for one, listing its open todos overdue and due
+// today and the todos it shared in the past day. Run hourly, it only
+// sends to the tenants for whom it is digestHour; tenants with nothing to
+// report get none. A tenant whose digest fails is logged and skipped, so
+// one bad tenant does not cost the others theirs.
+func (s *TodoService) SendDigests(ctx context.Context) error {
+	tenants, err := s.store.TenantsWanting(ctx, events.DailyDigest)
+	if err != nil {
+		return err
+	}
+	for _, id := range tenants {
+		if err := s.sendDigest(tenant.NewContext(ctx, id), id); err != nil {
+			s.log.Printf("digest: tenant %q: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// sendDigest publishes the digest of the tenant in ctx, id, if it is
+// digestHour there and there is something in it.
+func (s *TodoService) sendDigest(ctx context.Context, id string) error {
+	st, err := s.store.GetSettings(ctx)
+	if err != nil {
+		return err
+	}
+	loc, err := tz.Load(st.TimeZone)
+	if err != nil {
+		return err
+	}
+	now := s.now().In(loc)
+	if now.Hour() != digestHour {
+		return nil
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	tomorrow := today.AddDate(0, 0, 1)
+	overdue, err := s.openDue(ctx, nil, &now)
+	if err != nil {
+		return err
+	}
+	dueToday, err := s.openDue(ctx, &now, &tomorrow)
+	if err != nil {
+		return err
+	}
+	shared, err := s.sharedSince(ctx, now.AddDate(0, 0, -1))
+	if err != nil {
+		return err
+	}
+	if len(overdue) == 0 && len(dueToday) == 0 && len(shared) == 0 {
+		return nil
+	}
+	s.bus.Publish(events.Event{
+		Type:   events.DailyDigest,
+		Tenant: id,
+		At:     now,
+		Digest: &events.Digest{Overdue: overdue, DueToday: dueToday, Shared: shared},
+	})
+	return nil
+}
+
+// sharedSince returns the todos, up to maxDigestTodos, shared on their own
+// at or after since, in no particular order. Shares of whole lists are left
+// out; they are not about any one todo.
+func (s *TodoService) sharedSince(ctx context.Context, since time.Time) ([]model.Todo, error) {
+	shares, err := s.store.ListShares(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ids []bson.ObjectID
+	for _, sh := range shares {
+		if sh.TodoID.IsZero() || sh.CreatedAt.Before(since) {
+			continue
+		}
+		ids = append(ids, sh.TodoID)
+		if len(ids) == maxDigestTodos {
+			break
+		}
+	}
+	out := []model.Todo{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	todos, err := s.store.GetTodos(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return append(out, todos...), nil
+}
+
+// openDue returns the first todos not yet done due in [after, before),
+// soonest first.
+func (s *TodoService) openDue(ctx context.Context, after, before *time.Time) ([]model.Todo, error) {
+	open := false
+	f := store.TodoFilter{Completed: &open, DueAfter: after, DueBefore: before, Sort: "due_at"}
+	out := []model.Todo{}
+	err := s.store.EachTodo(ctx, f, 0, maxDigestTodos, func(t *model.Todo) error {
+		out = append(out, *t)
+		return nil
+	})
+	return out, err
+}
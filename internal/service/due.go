@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/nldate"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+)
+
+// DueReading reports how InterpretDue understood a due date, for the
+// client to confirm.
+type DueReading struct {
+	// Text is the words the date was read from.
+	Text  string
+	DueAt time.Time
+	// FromTitle is set when Text was taken off the end of the title.
+	FromTitle bool
+}
+
+// InterpretDue sets in.DueAt from everyday English. due, if not empty, is
+// read as a whole, such as "tomorrow 5pm"; it is an error if it isn't a
+// date. Otherwise, if fromTitle is set, a date ending the title, as in
+// "call mom friday", is moved from the title to DueAt. Dates are read in
+// the tenant's time zone. It returns what it understood, or nil if there
+// was nothing to read.
+func (s *TodoService) InterpretDue(ctx context.Context, in *TodoInput, due string, fromTitle bool) (*DueReading, error) {
+	now := s.now().In(tz.FromContext(ctx))
+	if due = strings.TrimSpace(due); due != "" {
+		t, err := nldate.Parse(due, now)
+		if err != nil {
+			return nil, NewValidationError("due", "Could not read %q as a date", due)
+		}
+		in.DueAt = &t
+		return &DueReading{Text: due, DueAt: t}, nil
+	}
+	if !fromTitle {
+		return nil, nil
+	}
+	title, t, phrase, ok := nldate.Extract(strings.TrimSpace(in.Title), now)
+	if !ok {
+		return nil, nil
+	}
+	in.Title, in.DueAt = title, &t
+	return &DueReading{Text: phrase, DueAt: t, FromTitle: true}, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// FeedStore is the persistence FeedService needs.
+type FeedStore interface {
+	AddActivity(ctx context.Context, a *model.Activity) error
+	ListActivity(ctx context.Context, before bson.ObjectID, limit int64) ([]model.Activity, error)
+}
+
+// feedEvents are the events the feed records. Edits are left out: every
+// completion is also one, and they would drown the rest.
+var feedEvents = map[string]bool{
+	events.TodoCreated:   true,
+	events.TodoCompleted: true,
+	events.TodoDeleted:   true,
+}
+
+// Feed page sizes.
+const (
+	defaultFeedLimit = 50
+	maxFeedLimit     = 200
+)
+
+// FeedService keeps the activity feed: what happened to the tenant's
+// todos, newest first.
+type FeedService struct {
+	store FeedStore
+	log   *log.Logger
+}
+
+// NewFeedService returns a service backed by s, recording the events
+// published on bus.
+func NewFeedService(s FeedStore, bus *events.Bus, logger *log.Logger) *FeedService {
+	f := &FeedService{store: s, log: logger}
+	bus.Subscribe("feed", f.record)
+	return f
+}
+
+func (f *FeedService) record(e events.Event) {
+	if !feedEvents[e.Type] {
+		return
+	}
+	a := &model.Activity{Type: e.Type, TodoID: e.TodoID, At: e.At}
+	if e.Todo != nil {
+		a.Title, a.List = e.Todo.Title, e.Todo.List
+	}
+	if err := f.store.AddActivity(tenant.NewContext(context.Background(), e.Tenant), a); err != nil {
+		f.log.Printf("feed: recording %s of %s: %v", e.Type, e.TodoID, err)
+	}
+}
+
+// Feed returns up to limit entries, newest first, after cursor, which is
+// empty for the first page. next is the cursor of the following page,
+// empty after the last one.
+func (f *FeedService) Feed(ctx context.Context, cursor string, limit int) (entries []model.Activity, next string, err error) {
+	var before bson.ObjectID
+	if cursor != "" {
+		if before, err = store.ParseID(cursor); err != nil {
+			return nil, "", &ValidationError{Field: "cursor", Message: "The cursor is invalid"}
+		}
+	}
+	if limit == 0 {
+		limit = defaultFeedLimit
+	}
+	if limit < 1 || limit > maxFeedLimit {
+		return nil, "", &ValidationError{Field: "limit", Message: "limit must be between 1 and 200"}
+	}
+	entries, err = f.store.ListActivity(ctx, before, int64(limit))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(entries) == limit {
+		next = entries[len(entries)-1].ID.Hex()
+	}
+	return entries, next, nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+)
+
+// ErrFieldExists is returned when defining a field a list already has.
+var ErrFieldExists = errors.New("the list already has a field with this name")
+
+// fieldName is what a custom field name may look like.
+var fieldName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// Limits on custom fields.
+const (
+	maxFieldsPerList = 50
+	maxFieldOptions  = 100
+	maxTextField     = 1000
+)
+
+// FieldInput holds the fields callers set on a custom field definition.
+type FieldInput struct {
+	List    string
+	Name    string
+	Type    string
+	Options []string
+}
+
+// ListFields returns the custom fields defined on list.
+func (s *TodoService) ListFields(ctx context.Context, list string) ([]model.FieldDef, error) {
+	return s.store.ListFieldDefs(ctx, strings.TrimSpace(list))
+}
+
+// DefineField adds a custom field to a list.
+func (s *TodoService) DefineField(ctx context.Context, in FieldInput) (*model.FieldDef, error) {
+	d := &model.FieldDef{
+		List:      strings.TrimSpace(in.List),
+		Name:      strings.TrimSpace(in.Name),
+		Type:      in.Type,
+		CreatedAt: s.now(),
+	}
+	if !fieldName.MatchString(d.Name) {
+		return nil, &ValidationError{Field: "name", Message: "name must start with a lowercase letter and hold only lowercase letters, digits and '_', up to 32 of them"}
+	}
+	if !contains(model.FieldTypes, d.Type) {
+		return nil, NewValidationError("type", "type must be one of %s", strings.Join(model.FieldTypes, ", "))
+	}
+	if d.Type == model.FieldSelect {
+		for _, o := range in.Options {
+			if o = strings.TrimSpace(o); o != "" && !contains(d.Options, o) {
+				d.Options = append(d.Options, o)
+			}
+		}
+		if len(d.Options) == 0 || len(d.Options) > maxFieldOptions {
+			return nil, &ValidationError{Field: "options", Message: "A select field needs between 1 and 100 options"}
+		}
+	}
+	existing, err := s.store.ListFieldDefs(ctx, d.List)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxFieldsPerList {
+		return nil, &ValidationError{Field: "list", Message: "A list may have at most 50 custom fields"}
+	}
+	if err := s.store.CreateFieldDef(ctx, d); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			return nil, ErrFieldExists
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// DeleteField removes a custom field and clears its values from the todos.
+func (s *TodoService) DeleteField(ctx context.Context, id string) error {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return err
+	}
+	return s.store.DeleteFieldDef(ctx, oid)
+}
+
+// fieldDefs loads custom field definitions by list, each list once.
+type fieldDefs struct {
+	store  Store
+	byList map[string]map[string]model.FieldDef
+}
+
+func (s *TodoService) fieldDefs() *fieldDefs {
+	return &fieldDefs{store: s.store, byList: map[string]map[string]model.FieldDef{}}
+}
+
+func (f *fieldDefs) of(ctx context.Context, list string) (map[string]model.FieldDef, error) {
+	if defs, ok := f.byList[list]; ok {
+		return defs, nil
+	}
+	all, err := f.store.ListFieldDefs(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+	defs := make(map[string]model.FieldDef, len(all))
+	for _, d := range all {
+		defs[d.Name] = d
+	}
+	f.byList[list] = defs
+	return defs, nil
+}
+
+// check validates the custom field values of a todo on list and returns
+// them as stored. Null values are dropped, which clears them.
+func (f *fieldDefs) check(ctx context.Context, list string, values map[string]interface{}) (map[string]interface{}, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	defs, err := f.of(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]interface{}, len(values))
+	for name, v := range values {
+		if v == nil {
+			continue
+		}
+		d, ok := defs[name]
+		if !ok {
+			return nil, NewValidationError("fields", "The list has no custom field %q", name)
+		}
+		if out[name], err = fieldValue(d, v); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// fieldValue checks v, as decoded from JSON or read from a query string,
+// against d and converts it to its stored form.
+func fieldValue(d model.FieldDef, v interface{}) (interface{}, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return NewValidationError("fields", format, append([]interface{}{d.Name}, args...)...)
+	}
+	switch d.Type {
+	case model.FieldNumber:
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case int:
+			return float64(n), nil
+		case string:
+			if f, err := strconv.ParseFloat(n, 64); err == nil {
+				return f, nil
+			}
+		}
+		return nil, invalid("Custom field %q must be a number")
+	case model.FieldDate:
+		s, _ := v.(string)
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return nil, invalid("Custom field %q must be a date, YYYY-MM-DD")
+		}
+		return s, nil
+	case model.FieldSelect:
+		s, _ := v.(string)
+		if !contains(d.Options, s) {
+			return nil, invalid("Custom field %q must be one of %s", strings.Join(d.Options, ", "))
+		}
+		return s, nil
+	default:
+		s, ok := v.(string)
+		if !ok || len(s) > maxTextField {
+			return nil, invalid("Custom field %q must be text of at most 1000 bytes")
+		}
+		return s, nil
+	}
+}
+
+// typedFields converts the field values of a filter, read as strings, to
+// their stored form, by the fields of the filter's list.
+func (s *TodoService) typedFields(ctx context.Context, f store.TodoFilter) (store.TodoFilter, error) {
+	if len(f.Fields) == 0 {
+		return f, nil
+	}
+	typed, err := s.fieldDefs().check(ctx, f.List, f.Fields)
+	f.Fields = typed
+	return f, err
+}
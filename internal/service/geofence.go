@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+)
+
+// maxGeofenceMeters is the widest a geofence may be.
+const maxGeofenceMeters = 50000
+
+// earthRadiusMeters is the mean radius MongoDB also uses for spheres.
+const earthRadiusMeters = 6378100
+
+func validateCoords(lat, lng float64) error {
+	if lat < -90 || lat > 90 || math.IsNaN(lat) {
+		return &ValidationError{Field: "lat", Message: "lat must be between -90 and 90"}
+	}
+	if lng < -180 || lng > 180 || math.IsNaN(lng) {
+		return &ValidationError{Field: "lng", Message: "lng must be between -180 and 180"}
+	}
+	return nil
+}
+
+func validateGeofence(g *model.Geofence) error {
+	if g == nil {
+		return nil
+	}
+	if err := validateCoords(g.Lat, g.Lng); err != nil {
+		return err
+	}
+	if g.RadiusMeters < 1 || g.RadiusMeters > maxGeofenceMeters {
+		return &ValidationError{Field: "radius_m", Message: "radius_m must be between 1 and 50000"}
+	}
+	return nil
+}
+
+// NearbyTodo is a todo whose geofence holds the caller.
+type NearbyTodo struct {
+	Todo           model.Todo
+	DistanceMeters int
+}
+
+// Nearby returns the todos not yet done whose geofence holds the point
+// lat, lng, nearest first. Mobile clients call it as their location
+// changes.
+func (s *TodoService) Nearby(ctx context.Context, lat, lng float64) ([]NearbyTodo, error) {
+	if err := validateCoords(lat, lng); err != nil {
+		return nil, err
+	}
+	here := model.Geofence{Lat: lat, Lng: lng}
+	todos, err := s.store.OpenTodosNear(ctx, *here.Point(), maxGeofenceMeters)
+	if err != nil {
+		return nil, err
+	}
+	out := []NearbyTodo{}
+	for _, t := range todos {
+		d := distance(here, *t.Geofence)
+		if d <= float64(t.Geofence.RadiusMeters) {
+			out = append(out, NearbyTodo{Todo: t, DistanceMeters: int(math.Round(d))})
+		}
+	}
+	return out, nil
+}
+
+// distance is the great-circle distance in meters between the centres of
+// a and b.
+func distance(a, b model.Geofence) float64 {
+	rad := math.Pi / 180
+	dLat := (b.Lat - a.Lat) * rad
+	dLng := (b.Lng - a.Lng) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(a.Lat*rad)*math.Cos(b.Lat*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
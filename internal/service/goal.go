@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// GoalStore is the persistence GoalService needs.
+type GoalStore interface {
+	ListGoals(ctx context.Context) ([]model.Goal, error)
+	GetGoal(ctx context.Context, id bson.ObjectID) (*model.Goal, error)
+	CreateGoal(ctx context.Context, g *model.Goal) error
+	UpdateGoal(ctx context.Context, g *model.Goal) error
+	DeleteGoal(ctx context.Context, id bson.ObjectID) error
+	AttachTodo(ctx context.Context, goal, todo bson.ObjectID) error
+	DetachTodo(ctx context.Context, goal, todo bson.ObjectID) error
+	GoalCounts(ctx context.Context) ([]store.GoalCount, error)
+}
+
+// GoalInput holds the fields callers set on a goal.
+type GoalInput struct {
+	Name        string
+	Description string
+	DueAt       *time.Time
+}
+
+// GoalProgress is a goal with how far its todos have come.
+type GoalProgress struct {
+	Goal  model.Goal
+	Total int
+	Done  int
+	// Percent is Done out of Total, 0 for a goal without todos.
+	Percent int
+	// Overdue is set once a goal not yet reached is past its due date.
+	Overdue bool
+}
+
+// GoalService manages goals and the todos attached to them.
+type GoalService struct {
+	store GoalStore
+	now   func() time.Time
+}
+
+// NewGoalService returns a service backed by s.
+func NewGoalService(s GoalStore, now func() time.Time) *GoalService {
+	return &GoalService{store: s, now: now}
+}
+
+// List returns every goal with its progress, oldest first.
+func (s *GoalService) List(ctx context.Context) ([]GoalProgress, error) {
+	goals, err := s.store.ListGoals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.progress(ctx, goals)
+}
+
+func (s *GoalService) Get(ctx context.Context, id string) (*GoalProgress, error) {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+	g, err := s.store.GetGoal(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.progress(ctx, []model.Goal{*g})
+	if err != nil {
+		return nil, err
+	}
+	return &out[0], nil
+}
+
+// Dashboard returns the goals not yet reached, soonest due first and
+// those without a due date last.
+func (s *GoalService) Dashboard(ctx context.Context) ([]GoalProgress, error) {
+	all, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	open := []GoalProgress{}
+	for _, p := range all {
+		if p.Total == 0 || p.Done < p.Total {
+			open = append(open, p)
+		}
+	}
+	sort.SliceStable(open, func(i, j int) bool {
+		a, b := open[i].Goal.DueAt, open[j].Goal.DueAt
+		return a != nil && (b == nil || a.Before(*b))
+	})
+	return open, nil
+}
+
+func (s *GoalService) progress(ctx context.Context, goals []model.Goal) ([]GoalProgress, error) {
+	counts, err := s.store.GoalCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byGoal := map[bson.ObjectID]store.GoalCount{}
+	for _, c := range counts {
+		byGoal[c.Goal] = c
+	}
+	now := s.now()
+	out := make([]GoalProgress, 0, len(goals))
+	for _, g := range goals {
+		c := byGoal[g.ID]
+		p := GoalProgress{Goal: g, Total: c.Total, Done: c.Done}
+		if c.Total > 0 {
+			p.Percent = 100 * c.Done / c.Total
+		}
+		p.Overdue = g.DueAt != nil && g.DueAt.Before(now) && (c.Total == 0 || c.Done < c.Total)
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func validateGoal(in GoalInput) (GoalInput, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	in.Description = strings.TrimSpace(in.Description)
+	if in.Name == "" {
+		return in, &ValidationError{Field: "name", Message: "The name field is required"}
+	}
+	return in, nil
+}
+
+func (s *GoalService) Create(ctx context.Context, in GoalInput) (*model.Goal, error) {
+	in, err := validateGoal(in)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	g := &model.Goal{Name: in.Name, Description: in.Description, DueAt: in.DueAt, CreatedAt: now, UpdatedAt: now}
+	if err := s.store.CreateGoal(ctx, g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Update replaces the editable fields of the goal with in.
+func (s *GoalService) Update(ctx context.Context, id string, in GoalInput) (*model.Goal, error) {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if in, err = validateGoal(in); err != nil {
+		return nil, err
+	}
+	g, err := s.store.GetGoal(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	g.Name, g.Description, g.DueAt, g.UpdatedAt = in.Name, in.Description, in.DueAt, s.now()
+	if err := s.store.UpdateGoal(ctx, g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Delete deletes the goal; its todos stay, detached.
+func (s *GoalService) Delete(ctx context.Context, id string) error {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return err
+	}
+	return s.store.DeleteGoal(ctx, oid)
+}
+
+// Attach attaches the todo to the goal. A todo works towards one goal at
+// most, so this detaches it from any other.
+func (s *GoalService) Attach(ctx context.Context, id, todoID string) error {
+	oid, tid, err := parseGoalTodo(id, todoID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.store.GetGoal(ctx, oid); err != nil {
+		return err
+	}
+	return s.store.AttachTodo(ctx, oid, tid)
+}
+
+// Detach detaches the todo from the goal.
+func (s *GoalService) Detach(ctx context.Context, id, todoID string) error {
+	oid, tid, err := parseGoalTodo(id, todoID)
+	if err != nil {
+		return err
+	}
+	return s.store.DetachTodo(ctx, oid, tid)
+}
+
+func parseGoalTodo(id, todoID string) (bson.ObjectID, bson.ObjectID, error) {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return oid, oid, err
+	}
+	tid, err := store.ParseID(todoID)
+	return oid, tid, err
+}
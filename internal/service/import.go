@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// maxImport caps the todos, steps included, one import may create.
+const maxImport = 5000
+
+// ImportTask is a todo brought in from another app. Its Steps become todos
+// of their own on the same list, each blocking the task.
+type ImportTask struct {
+	TodoInput
+	Steps []TodoInput
+}
+
+// ImportFailure names a task or step that could not be imported.
+type ImportFailure struct {
+	Title string
+	Err   error
+}
+
+// ImportResult reports on an import.
+type ImportResult struct {
+	Created  int
+	Failures []ImportFailure
+}
+
+// Import creates a todo for each task and each of its steps. A task or step
+// that fails validation is reported and the rest are still created; the
+// steps of a failed task are skipped.
+func (s *TodoService) Import(ctx context.Context, tasks []ImportTask) (*ImportResult, error) {
+	n := len(tasks)
+	for _, t := range tasks {
+		n += len(t.Steps)
+	}
+	if n > maxImport {
+		return nil, &ValidationError{Field: "tasks", Message: "An import may hold at most 5000 todos, steps included"}
+	}
+	res := &ImportResult{}
+	items := make([]BulkItem, len(tasks))
+	for i, t := range tasks {
+		items[i] = BulkItem{TodoInput: t.TodoInput}
+	}
+	var steps []BulkItem
+	var parents []bson.ObjectID // the task of each step
+	for i, saved := range s.WriteMany(ctx, items) {
+		if saved.Err != nil {
+			res.Failures = append(res.Failures, ImportFailure{Title: tasks[i].Title, Err: saved.Err})
+			continue
+		}
+		res.Created++
+		for _, st := range tasks[i].Steps {
+			st.List = tasks[i].List
+			steps = append(steps, BulkItem{TodoInput: st})
+			parents = append(parents, saved.Todo.ID)
+		}
+	}
+
+	// Steps are new, so blocking their task can't close a cycle.
+	blockers := map[bson.ObjectID]int{}
+	for j, saved := range s.WriteMany(ctx, steps) {
+		if saved.Err != nil {
+			res.Failures = append(res.Failures, ImportFailure{Title: steps[j].Title, Err: saved.Err})
+			continue
+		}
+		res.Created++
+		p := parents[j]
+		if blockers[p] >= maxBlockers {
+			continue
+		}
+		if err := s.store.AddBlocker(ctx, p, saved.Todo.ID); err != nil {
+			return nil, err
+		}
+		blockers[p]++
+	}
+	return res, nil
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// IntegrationStore is the persistence IntegrationService needs.
+type IntegrationStore interface {
+	ListIntegrations(ctx context.Context) ([]model.Integration, error)
+	GetIntegration(ctx context.Context, id bson.ObjectID) (*model.Integration, error)
+	CreateIntegration(ctx context.Context, in *model.Integration) error
+	UpdateIntegration(ctx context.Context, in *model.Integration) error
+	DeleteIntegration(ctx context.Context, id bson.ObjectID) error
+	TenantsSubscribed(ctx context.Context, channel, address string) ([]string, error)
+}
+
+// IntegrationInput holds the fields callers set on an integration.
+type IntegrationInput struct {
+	Name     string
+	Channel  string
+	Address  string
+	Events   []string
+	List     string
+	Template string
+	Enabled  bool
+}
+
+// urlChannels are the channels that POST to the integration's address, which
+// must then be an http or https URL.
+var urlChannels = map[string]bool{"webhook": true, "discord": true}
+
+// IntegrationService manages where todo events are sent.
+type IntegrationService struct {
+	store    IntegrationStore
+	channels func() []string
+	now      func() time.Time
+}
+
+// NewIntegrationService returns a service backed by s; channels lists the
+// notification channels integrations may use.
+func NewIntegrationService(s IntegrationStore, channels func() []string, now func() time.Time) *IntegrationService {
+	return &IntegrationService{store: s, channels: channels, now: now}
+}
+
+func (s *IntegrationService) List(ctx context.Context) ([]model.Integration, error) {
+	return s.store.ListIntegrations(ctx)
+}
+
+func (s *IntegrationService) Get(ctx context.Context, id string) (*model.Integration, error) {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.GetIntegration(ctx, oid)
+}
+
+func (s *IntegrationService) validate(in IntegrationInput) (IntegrationInput, error) {
+	in.Channel = strings.TrimSpace(in.Channel)
+	in.Address = strings.TrimSpace(in.Address)
+	in.Name = strings.TrimSpace(in.Name)
+	in.List = strings.TrimSpace(in.List)
+	if in.Name == "" {
+		in.Name = in.Channel
+	}
+	known := false
+	for _, c := range s.channels() {
+		known = known || c == in.Channel
+	}
+	if !known {
+		return in, NewValidationError("channel",
+			"Unknown channel %q, expected one of %s", in.Channel, strings.Join(s.channels(), ", "))
+	}
+	if urlChannels[in.Channel] {
+		if u, err := url.Parse(in.Address); err != nil || u.Host == "" || u.Scheme != "http" && u.Scheme != "https" {
+			return in, NewValidationError("address", "The address of a %s integration must be an http or https URL", in.Channel)
+		}
+	}
+	for _, e := range in.Events {
+		if !contains(events.Types, e) {
+			return in, NewValidationError("events",
+				"Unknown event %q, expected one of %s", e, strings.Join(events.Types, ", "))
+		}
+	}
+	if in.Events == nil {
+		in.Events = []string{}
+	}
+	if _, err := template.New("").Parse(in.Template); err != nil {
+		return in, NewValidationError("template", "The template is invalid: %v", err)
+	}
+	return in, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *IntegrationService) Create(ctx context.Context, in IntegrationInput) (*model.Integration, error) {
+	in, err := s.validate(in)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	it := &model.Integration{
+		Name:      in.Name,
+		Channel:   in.Channel,
+		Address:   in.Address,
+		Events:    in.Events,
+		List:      in.List,
+		Template:  in.Template,
+		Enabled:   in.Enabled,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.store.CreateIntegration(ctx, it); err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
+// Update replaces the editable fields of the integration with in.
+func (s *IntegrationService) Update(ctx context.Context, id string, in IntegrationInput) (*model.Integration, error) {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if in, err = s.validate(in); err != nil {
+		return nil, err
+	}
+	it, err := s.store.GetIntegration(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	it.Name, it.Channel, it.Address = in.Name, in.Channel, in.Address
+	it.Events, it.List, it.Template, it.Enabled = in.Events, in.List, in.Template, in.Enabled
+	it.UpdatedAt = s.now()
+	if err := s.store.UpdateIntegration(ctx, it); err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
+func (s *IntegrationService) Delete(ctx context.Context, id string) error {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return err
+	}
+	return s.store.DeleteIntegration(ctx, oid)
+}
+
+// Find returns the integrations for a channel and address, as used by
+// channels where the recipient subscribes themselves (a Telegram chat, a
+// phone number).
+func (s *IntegrationService) Find(ctx context.Context, channel, address string) ([]model.Integration, error) {
+	all, err := s.store.ListIntegrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []model.Integration
+	for _, it := range all {
+		if it.Channel == channel && it.Address == address {
+			out = append(out, it)
+		}
+	}
+	return out, nil
+}
+
+// Tenants returns, across every tenant, those with an integration for
+// channel and address. Webhooks from a subscriber, such as an SMS, reach
+// no tenant by themselves and act in each of these.
+func (s *IntegrationService) Tenants(ctx context.Context, channel, address string) ([]string, error) {
+	return s.store.TenantsSubscribed(ctx, channel, address)
+}
+
+// Subscribe adds an enabled integration for channel and address with every
+// event, unless there already is one.
+func (s *IntegrationService) Subscribe(ctx context.Context, channel, address string) error {
+	existing, err := s.Find(ctx, channel, address)
+	if err != nil || len(existing) > 0 {
+		return err
+	}
+	_, err = s.Create(ctx, IntegrationInput{Channel: channel, Address: address, Enabled: true})
+	return err
+}
+
+// Unsubscribe deletes every integration for channel and address.
+func (s *IntegrationService) Unsubscribe(ctx context.Context, channel, address string) error {
+	existing, err := s.Find(ctx, channel, address)
+	if err != nil {
+		return err
+	}
+	for _, it := range existing {
+		if err := s.store.DeleteIntegration(ctx, it.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// maxLinks caps the links a todo holds.
+const maxLinks = 100
+
+// inverseLinks names each link type as seen from the other todo.
+var inverseLinks = map[string]string{
+	model.LinkRelatesTo:  model.LinkRelatesTo,
+	model.LinkDuplicates: "duplicated_by",
+	model.LinkFollows:    "followed_by",
+}
+
+// AddLink links the todo id to the todo other with the given type, one of
+// model.LinkTypes.
+func (s *TodoService) AddLink(ctx context.Context, id, typ, other string) error {
+	oid, l, err := parseLink(id, typ, other)
+	if err != nil {
+		return err
+	}
+	if oid == l.TodoID {
+		return &ValidationError{Field: "todo_id", Message: "A todo can't be linked to itself"}
+	}
+	found, err := s.store.GetTodos(ctx, []bson.ObjectID{oid, l.TodoID})
+	if err != nil {
+		return err
+	}
+	if len(found) != 2 {
+		return store.ErrNotFound
+	}
+	for _, t := range found {
+		if t.ID == oid && len(t.Links) >= maxLinks {
+			return &ValidationError{Field: "todo_id", Message: "A todo may have at most 100 links"}
+		}
+	}
+	return s.store.AddLink(ctx, oid, l)
+}
+
+// RemoveLink undoes AddLink.
+func (s *TodoService) RemoveLink(ctx context.Context, id, typ, other string) error {
+	oid, l, err := parseLink(id, typ, other)
+	if err != nil {
+		return err
+	}
+	return s.store.RemoveLink(ctx, oid, l)
+}
+
+func parseLink(id, typ, other string) (bson.ObjectID, model.Link, error) {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return oid, model.Link{}, err
+	}
+	to, err := store.ParseID(other)
+	if err != nil {
+		return oid, model.Link{}, err
+	}
+	if _, ok := inverseLinks[typ]; !ok {
+		return oid, model.Link{}, &ValidationError{Field: "type", Message: "type must be relates_to, duplicates or follows"}
+	}
+	return oid, model.Link{Type: typ, TodoID: to}, nil
+}
+
+// Links returns the links of t in both directions: those it holds, then
+// those other todos hold to it, named as seen from t ("duplicated_by",
+// "followed_by").
+func (s *TodoService) Links(ctx context.Context, t *model.Todo) ([]model.Link, error) {
+	from, err := s.store.LinksTo(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	out := append([]model.Link{}, t.Links...)
+	for _, o := range from {
+		for _, l := range o.Links {
+			if l.TodoID == t.ID {
+				out = append(out, model.Link{Type: inverseLinks[l.Type], TodoID: o.ID})
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+)
+
+var (
+	// ErrPomodoroRunning is returned when starting a pomodoro on a todo
+	// that has one running.
+	ErrPomodoroRunning = errors.New("a pomodoro is already running for this todo")
+	// ErrPomodoroEnded is returned when ending a pomodoro that isn't
+	// running.
+	ErrPomodoroEnded = errors.New("this pomodoro is not running")
+)
+
+// Pomodoro lengths, in minutes, when the caller gives none.
+const (
+	defaultPomodoroMinutes = 25
+	defaultBreakMinutes    = 5
+)
+
+// StartPomodoro starts a focus session of minutes on the todo, to be
+// followed by a break of breakMinutes; 0 picks 25 and 5.
+func (s *TodoService) StartPomodoro(ctx context.Context, id string, minutes, breakMinutes int) (*model.Pomodoro, error) {
+	if minutes == 0 {
+		minutes = defaultPomodoroMinutes
+	}
+	if breakMinutes == 0 {
+		breakMinutes = defaultBreakMinutes
+	}
+	if minutes < 1 || minutes > 120 {
+		return nil, &ValidationError{Field: "minutes", Message: "minutes must be between 1 and 120"}
+	}
+	if breakMinutes < 1 || breakMinutes > 60 {
+		return nil, &ValidationError{Field: "break_minutes", Message: "break_minutes must be between 1 and 60"}
+	}
+	t, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	p := &model.Pomodoro{TodoID: t.ID, Minutes: minutes, BreakMinutes: breakMinutes, Start: s.now()}
+	if err := s.store.StartPomodoro(ctx, p); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			return nil, ErrPomodoroRunning
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+// CompletePomodoro ends the session as done and announces the break with
+// a PomodoroBreak event, which integrations can deliver.
+func (s *TodoService) CompletePomodoro(ctx context.Context, id, pomodoroID string) (*model.Pomodoro, error) {
+	t, p, err := s.endPomodoro(ctx, id, pomodoroID, model.PomodoroCompleted)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(ctx, events.PomodoroBreak, id, t)
+	return p, nil
+}
+
+// InterruptPomodoro ends the session before its time.
+func (s *TodoService) InterruptPomodoro(ctx context.Context, id, pomodoroID string) (*model.Pomodoro, error) {
+	_, p, err := s.endPomodoro(ctx, id, pomodoroID, model.PomodoroInterrupted)
+	return p, err
+}
+
+func (s *TodoService) endPomodoro(ctx context.Context, id, pomodoroID, state string) (*model.Todo, *model.Pomodoro, error) {
+	t, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	pid, err := store.ParseID(pomodoroID)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := s.store.EndPomodoro(ctx, t.ID, pid, state, s.now())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil, ErrPomodoroEnded
+	}
+	return t, p, err
+}
+
+// PomodoroDay sums up one day's sessions.
+type PomodoroDay struct {
+	// Day is YYYY-MM-DD in the server's time zone.
+	Day         string
+	Completed   int
+	Interrupted int
+	// Focus is the time spent in ended sessions.
+	Focus time.Duration
+}
+
+// PomodoroStats sums up, per day, the sessions started from from until to.
+// Days without any are left out.
+func (s *TodoService) PomodoroStats(ctx context.Context, from, to time.Time) ([]PomodoroDay, error) {
+	if err := checkPeriod(from, to); err != nil {
+		return nil, err
+	}
+	all, err := s.store.ListPomodoros(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	loc := tz.FromContext(ctx)
+	var out []PomodoroDay
+	for _, p := range all {
+		day := p.Start.In(loc).Format("2006-01-02")
+		if len(out) == 0 || out[len(out)-1].Day != day {
+			out = append(out, PomodoroDay{Day: day})
+		}
+		d := &out[len(out)-1]
+		switch p.State {
+		case model.PomodoroCompleted:
+			d.Completed++
+		case model.PomodoroInterrupted:
+			d.Interrupted++
+		}
+		if p.End != nil {
+			d.Focus += p.End.Sub(p.Start)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+)
+
+// PushStore is the persistence PushService needs.
+type PushStore interface {
+	SavePushSubscription(ctx context.Context, sub *model.PushSubscription) error
+	ListPushSubscriptions(ctx context.Context) ([]model.PushSubscription, error)
+	DeletePushSubscription(ctx context.Context, endpoint string) error
+}
+
+// PushService manages the browsers subscribed to Web Push notifications.
+type PushService struct {
+	store PushStore
+	now   func() time.Time
+}
+
+func NewPushService(s PushStore, now func() time.Time) *PushService {
+	return &PushService{store: s, now: now}
+}
+
+func (s *PushService) Subscribe(ctx context.Context, sub *model.PushSubscription) error {
+	u, err := url.Parse(sub.Endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return &ValidationError{Field: "endpoint", Message: "The endpoint must be an https URL"}
+	}
+	if sub.Keys.P256dh == "" || sub.Keys.Auth == "" {
+		return &ValidationError{Field: "keys", Message: "The p256dh and auth keys are required"}
+	}
+	sub.CreatedAt = s.now()
+	return s.store.SavePushSubscription(ctx, sub)
+}
+
+// Unsubscribe forgets the subscription; unknown endpoints are not an error,
+// so a browser may safely unsubscribe twice.
+func (s *PushService) Unsubscribe(ctx context.Context, endpoint string) error {
+	err := s.store.DeletePushSubscription(ctx, endpoint)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	return err
+}
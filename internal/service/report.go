@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/store"
+)
+
+// maxReportWeeks bounds the span of a weekly report.
+const maxReportWeeks = 52
+
+// ReportWeek is one week of a WeeklyReport.
+type ReportWeek struct {
+	// Start is the Monday the week begins, at midnight server time.
+	Start     time.Time
+	Created   int
+	Completed int
+	Due       int
+	// CarriedOver counts the todos due in the week but not done by its
+	// end.
+	CarriedOver int
+	// CompletionRate is the share of the todos due in the week done by
+	// its end; nil when none were due.
+	CompletionRate *float64
+}
+
+// WeeklyReport is how work went over a run of weeks.
+type WeeklyReport struct {
+	Weeks []ReportWeek
+	// BusiestTags are the tags of the most todos completed over all
+	// weeks, busiest first.
+	BusiestTags []store.TagCount
+}
+
+// WeeklyReport covers the weeks, Monday to Sunday, up to and including
+// the one holding end.
+func (s *TodoService) WeeklyReport(ctx context.Context, end time.Time, weeks int) (*WeeklyReport, error) {
+	if weeks < 1 || weeks > maxReportWeeks {
+		return nil, &ValidationError{Field: "weeks", Message: "weeks must be between 1 and 52"}
+	}
+	day := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
+	monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	from := monday.AddDate(0, 0, -7*(weeks-1))
+	counts, tags, err := s.store.WeeklyCounts(ctx, from, weeks)
+	if err != nil {
+		return nil, err
+	}
+	out := &WeeklyReport{Weeks: make([]ReportWeek, 0, len(counts)), BusiestTags: tags}
+	for _, c := range counts {
+		w := ReportWeek{
+			Start:       from.AddDate(0, 0, 7*c.Week),
+			Created:     c.Created,
+			Completed:   c.Completed,
+			Due:         c.Due,
+			CarriedOver: c.Due - c.DueDone,
+		}
+		if c.Due > 0 {
+			rate := float64(c.DueDone) / float64(c.Due)
+			w.CompletionRate = &rate
+		}
+		out.Weeks = append(out.Weeks, w)
+	}
+	if out.BusiestTags == nil {
+		out.BusiestTags = []store.TagCount{}
+	}
+	return out, nil
+}
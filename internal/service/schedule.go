@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+)
+
+// ScheduleSlot is a todo placed on the timeline: it is worked on from
+// Start so as to be done by its due date, End.
+type ScheduleSlot struct {
+	Todo       model.Todo
+	Start, End time.Time
+	// Overlaps is set when another slot shares some of its time.
+	Overlaps bool
+}
+
+// ScheduleDay is the slots of the todos due on one day, by start.
+type ScheduleDay struct {
+	// Day is YYYY-MM-DD in the tenant's time zone.
+	Day   string
+	Slots []ScheduleSlot
+}
+
+// Schedule lays out the todos not yet done that are due from from until
+// to, each ending at its due date and starting its estimate earlier. A
+// todo without an estimate takes no time, so it never overlaps. Days with
+// nothing due are left out.
+func (s *TodoService) Schedule(ctx context.Context, from, to time.Time) ([]ScheduleDay, error) {
+	if err := checkPeriod(from, to); err != nil {
+		return nil, err
+	}
+	open := false
+	f := store.TodoFilter{Completed: &open, DueAfter: &from, DueBefore: &to, Sort: "due_at"}
+	var slots []ScheduleSlot
+	err := s.store.EachTodo(ctx, f, 0, 0, func(t *model.Todo) error {
+		end := *t.DueAt
+		start := end.Add(-time.Duration(t.EstimateMinutes) * time.Minute)
+		slots = append(slots, ScheduleSlot{Todo: *t, Start: start, End: end})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].Start.Before(slots[j].Start) })
+	// Sweeping by start, a slot overlaps the one reaching furthest so far
+	// if it starts before that one ends.
+	last := -1
+	for i := range slots {
+		if last >= 0 && slots[i].Start.Before(slots[last].End) {
+			slots[i].Overlaps, slots[last].Overlaps = true, true
+		}
+		if last < 0 || slots[i].End.After(slots[last].End) {
+			last = i
+		}
+	}
+	loc := tz.FromContext(ctx)
+	at := map[string]int{}
+	var out []ScheduleDay
+	for _, sl := range slots {
+		day := sl.End.In(loc).Format("2006-01-02")
+		i, ok := at[day]
+		if !ok {
+			i = len(out)
+			at[day] = i
+			out = append(out, ScheduleDay{Day: day})
+		}
+		out[i].Slots = append(out[i].Slots, sl)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Day < out[j].Day })
+	return out, nil
+}
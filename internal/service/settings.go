@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+)
+
+// SettingsStore is the persistence SettingsService needs.
+type SettingsStore interface {
+	GetSettings(ctx context.Context) (*model.Settings, error)
+	SaveSettings(ctx context.Context, st *model.Settings) error
+}
+
+// settingsTTL is how long settings are cached. Every request reads them,
+// and another instance's changes show up after at most this long.
+const settingsTTL = time.Minute
+
+// SettingsInput holds the settings callers set.
+type SettingsInput struct {
+	Locale   string
+	TimeZone string
+}
+
+type cachedSettings struct {
+	settings model.Settings
+	expires  time.Time
+}
+
+// SettingsService keeps each tenant's preferences.
+type SettingsService struct {
+	store SettingsStore
+	now   func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedSettings
+}
+
+// NewSettingsService returns a service backed by s.
+func NewSettingsService(s SettingsStore, now func() time.Time) *SettingsService {
+	return &SettingsService{store: s, now: now, cache: map[string]cachedSettings{}}
+}
+
+// Get returns the tenant's settings.
+func (s *SettingsService) Get(ctx context.Context) (*model.Settings, error) {
+	id := tenant.FromContext(ctx)
+	s.mu.Lock()
+	c, ok := s.cache[id]
+	s.mu.Unlock()
+	if ok && s.now().Before(c.expires) {
+		st := c.settings
+		return &st, nil
+	}
+	st, err := s.store.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.remember(id, *st)
+	return st, nil
+}
+
+// Locale returns the locale the tenant chose, "" if it chose none.
+func (s *SettingsService) Locale(ctx context.Context) (string, error) {
+	st, err := s.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+	return st.Locale, nil
+}
+
+// Location returns the zone the tenant chose to read dates in, nil if it
+// chose none.
+func (s *SettingsService) Location(ctx context.Context) (*time.Location, error) {
+	st, err := s.Get(ctx)
+	if err != nil || st.TimeZone == "" {
+		return nil, err
+	}
+	return tz.Load(st.TimeZone)
+}
+
+// Update replaces the tenant's settings. The locale may be any language
+// tag whose language there are messages in, such as "de-AT"; it is saved
+// as the locale it matched.
+func (s *SettingsService) Update(ctx context.Context, in SettingsInput) (*model.Settings, error) {
+	st := &model.Settings{TimeZone: strings.TrimSpace(in.TimeZone), UpdatedAt: s.now()}
+	if in.Locale != "" {
+		if st.Locale = i18n.Match(in.Locale); st.Locale == "" {
+			return nil, NewValidationError("locale", "locale must be one of %s", strings.Join(i18n.Locales(), ", "))
+		}
+	}
+	if _, err := tz.Load(st.TimeZone); err != nil {
+		return nil, &ValidationError{Field: "time_zone", Message: "time_zone must be an IANA time zone name, such as Europe/Berlin"}
+	}
+	if err := s.store.SaveSettings(ctx, st); err != nil {
+		return nil, err
+	}
+	s.remember(tenant.FromContext(ctx), *st)
+	return st, nil
+}
+
+func (s *SettingsService) remember(id string, st model.Settings) {
+	s.mu.Lock()
+	s.cache[id] = cachedSettings{settings: st, expires: s.now().Add(settingsTTL)}
+	s.mu.Unlock()
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ShareStore is the persistence ShareService needs.
+type ShareStore interface {
+	ListShares(ctx context.Context) ([]model.Share, error)
+	CreateShare(ctx context.Context, sh *model.Share) error
+	DeleteShare(ctx context.Context, id bson.ObjectID) error
+	ShareByToken(ctx context.Context, token string, now time.Time) (*model.Share, error)
+}
+
+// maxSharedTodos caps the todos a shared list shows.
+const maxSharedTodos = 500
+
+// ShareService hands out read-only links to todos and lists.
+type ShareService struct {
+	store ShareStore
+	todos *TodoService
+	now   func() time.Time
+}
+
+// NewShareService returns a service backed by s, reading todos through
+// todos.
+func NewShareService(s ShareStore, todos *TodoService, now func() time.Time) *ShareService {
+	return &ShareService{store: s, todos: todos, now: now}
+}
+
+func (s *ShareService) List(ctx context.Context) ([]model.Share, error) {
+	return s.store.ListShares(ctx)
+}
+
+// ShareTodo creates a share of the todo id, expiring at expiresAt if it
+// isn't nil.
+func (s *ShareService) ShareTodo(ctx context.Context, id string, expiresAt *time.Time) (*model.Share, error) {
+	t, err := s.todos.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.create(ctx, &model.Share{TodoID: t.ID, ExpiresAt: expiresAt})
+}
+
+// ShareList creates a share of the named list. The default list, having
+// no name, can't be shared.
+func (s *ShareService) ShareList(ctx context.Context, list string, expiresAt *time.Time) (*model.Share, error) {
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return nil, &ValidationError{Field: "list", Message: "The list field is required"}
+	}
+	return s.create(ctx, &model.Share{List: list, ExpiresAt: expiresAt})
+}
+
+func (s *ShareService) create(ctx context.Context, sh *model.Share) (*model.Share, error) {
+	sh.CreatedAt = s.now()
+	if sh.ExpiresAt != nil && !sh.ExpiresAt.After(sh.CreatedAt) {
+		return nil, &ValidationError{Field: "expires_at", Message: "expires_at must be in the future"}
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	sh.Token = base64.RawURLEncoding.EncodeToString(b)
+	if err := s.store.CreateShare(ctx, sh); err != nil {
+		return nil, err
+	}
+	return sh, nil
+}
+
+// Revoke deletes a share; its token stops working at once.
+func (s *ShareService) Revoke(ctx context.Context, id string) error {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return err
+	}
+	return s.store.DeleteShare(ctx, oid)
+}
+
+// Shared is what a share shows.
+type Shared struct {
+	Share model.Share
+	// Todos is the shared todo, or the todos of the shared list, oldest
+	// first.
+	Todos []model.Todo
+}
+
+// Open returns what the share with token shows, acting for the tenant
+// that made it. It returns store.ErrNotFound for unknown, revoked and
+// expired tokens alike.
+func (s *ShareService) Open(ctx context.Context, token string) (*Shared, error) {
+	sh, err := s.store.ShareByToken(ctx, token, s.now())
+	if err != nil {
+		return nil, err
+	}
+	ctx = tenant.NewContext(ctx, sh.TenantID)
+	out := &Shared{Share: *sh, Todos: []model.Todo{}}
+	if sh.List == "" {
+		t, err := s.todos.Get(ctx, sh.TodoID.Hex())
+		if err != nil {
+			return nil, err
+		}
+		out.Todos = append(out.Todos, *t)
+		return out, nil
+	}
+	err = s.todos.Each(ctx, store.TodoFilter{List: sh.List}, 0, maxSharedTodos, func(t *model.Todo) error {
+		out.Todos = append(out.Todos, *t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+)
+
+// slugAlphabet leaves out characters that are easily misread for another:
+// 0, 1, i, l and o.
+const slugAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+
+// slugLength is the length of a new slug. Every slugGrowth collisions in a
+// row lengthen it by one, up to slugAttempts tries.
+const (
+	slugLength   = 6
+	slugGrowth   = 3
+	slugAttempts = 12
+)
+
+// Slug returns the short slug of the todo id, giving it one if it has
+// none yet. A todo keeps its slug for good.
+func (s *TodoService) Slug(ctx context.Context, id string) (string, error) {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return "", err
+	}
+	t, err := s.store.GetTodo(ctx, oid)
+	if err != nil {
+		return "", err
+	}
+	for attempt := 0; t.Slug == "" && attempt < slugAttempts; attempt++ {
+		slug, err := newSlug(slugLength + attempt/slugGrowth)
+		if err != nil {
+			return "", err
+		}
+		err = s.store.SetSlug(ctx, oid, slug)
+		switch {
+		case err == nil:
+			return slug, nil
+		case errors.Is(err, store.ErrNotFound):
+			// Deleted, or given a slug by a concurrent call.
+			if t, err = s.store.GetTodo(ctx, oid); err != nil {
+				return "", err
+			}
+		case !errors.Is(err, store.ErrConflict):
+			return "", err
+		}
+	}
+	if t.Slug == "" {
+		return "", errors.New("service: no free slug found")
+	}
+	return t.Slug, nil
+}
+
+// BySlug returns the todo with slug, read case-insensitively.
+func (s *TodoService) BySlug(ctx context.Context, slug string) (*model.Todo, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if slug == "" || strings.Trim(slug, slugAlphabet) != "" {
+		return nil, store.ErrNotFound
+	}
+	return s.store.TodoBySlug(ctx, slug)
+}
+
+func newSlug(n int) (string, error) {
+	b := make([]byte, n)
+	max := big.NewInt(int64(len(slugAlphabet)))
+	for i := range b {
+		k, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = slugAlphabet[k.Int64()]
+	}
+	return string(b), nil
+}
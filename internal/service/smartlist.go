@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// SmartListStore is the persistence SmartListService needs.
+type SmartListStore interface {
+	ListSmartLists(ctx context.Context) ([]model.SmartList, error)
+	GetSmartList(ctx context.Context, id bson.ObjectID) (*model.SmartList, error)
+	CreateSmartList(ctx context.Context, l *model.SmartList) error
+	UpdateSmartList(ctx context.Context, l *model.SmartList) error
+	DeleteSmartList(ctx context.Context, id bson.ObjectID) error
+}
+
+// SmartListInput holds the fields callers set on a smart list.
+type SmartListInput struct {
+	Name          string
+	Tags          []string
+	AnyTags       []string
+	NotTags       []string
+	List          string
+	Completed     *bool
+	DueWithinDays *int
+	Sort          string
+}
+
+// maxDueWithinDays bounds how far ahead a smart list may look.
+const maxDueWithinDays = 366
+
+// SmartListService manages saved filters over the todos.
+type SmartListService struct {
+	store SmartListStore
+	now   func() time.Time
+}
+
+// NewSmartListService returns a service backed by s.
+func NewSmartListService(s SmartListStore, now func() time.Time) *SmartListService {
+	return &SmartListService{store: s, now: now}
+}
+
+func (s *SmartListService) List(ctx context.Context) ([]model.SmartList, error) {
+	return s.store.ListSmartLists(ctx)
+}
+
+func (s *SmartListService) Get(ctx context.Context, id string) (*model.SmartList, error) {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.GetSmartList(ctx, oid)
+}
+
+func validateSmartList(in SmartListInput) (SmartListInput, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	in.List = strings.TrimSpace(in.List)
+	in.Sort = strings.TrimSpace(in.Sort)
+	if in.Name == "" {
+		return in, &ValidationError{Field: "name", Message: "The name field is required"}
+	}
+	f, err := normalizeFilter(store.TodoFilter{Tags: in.Tags, AnyTags: in.AnyTags, NotTags: in.NotTags})
+	if err != nil {
+		return in, err
+	}
+	in.Tags, in.AnyTags, in.NotTags = f.Tags, f.AnyTags, f.NotTags
+	if d := in.DueWithinDays; d != nil && (*d < 0 || *d > maxDueWithinDays) {
+		return in, &ValidationError{Field: "due_within_days", Message: "due_within_days must be between 0 and 366"}
+	}
+	if !store.ValidSort(in.Sort) {
+		return in, &ValidationError{Field: "sort", Message: "sort must be created_at, updated_at, due_at or title, optionally preceded by '-'"}
+	}
+	return in, nil
+}
+
+func (s *SmartListService) Create(ctx context.Context, in SmartListInput) (*model.SmartList, error) {
+	in, err := validateSmartList(in)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	l := &model.SmartList{CreatedAt: now}
+	applySmartList(l, in, now)
+	if err := s.store.CreateSmartList(ctx, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Update replaces the editable fields of the smart list with in.
+func (s *SmartListService) Update(ctx context.Context, id string, in SmartListInput) (*model.SmartList, error) {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if in, err = validateSmartList(in); err != nil {
+		return nil, err
+	}
+	l, err := s.store.GetSmartList(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	applySmartList(l, in, s.now())
+	if err := s.store.UpdateSmartList(ctx, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func applySmartList(l *model.SmartList, in SmartListInput, now time.Time) {
+	l.Name, l.Tags, l.AnyTags, l.NotTags = in.Name, in.Tags, in.AnyTags, in.NotTags
+	l.List, l.Completed, l.DueWithinDays, l.Sort = in.List, in.Completed, in.DueWithinDays, in.Sort
+	l.UpdatedAt = now
+}
+
+func (s *SmartListService) Delete(ctx context.Context, id string) error {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return err
+	}
+	return s.store.DeleteSmartList(ctx, oid)
+}
+
+// Filter returns the todo filter the smart list stands for right now, to
+// pass to TodoService.Each and Count.
+func (s *SmartListService) Filter(ctx context.Context, id string) (store.TodoFilter, error) {
+	l, err := s.Get(ctx, id)
+	if err != nil {
+		return store.TodoFilter{}, err
+	}
+	f := store.TodoFilter{
+		Tags:      l.Tags,
+		AnyTags:   l.AnyTags,
+		NotTags:   l.NotTags,
+		List:      l.List,
+		Completed: l.Completed,
+		Sort:      l.Sort,
+	}
+	if l.DueWithinDays != nil {
+		before := s.now().AddDate(0, 0, *l.DueWithinDays)
+		f.DueBefore = &before
+	}
+	return f, nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+)
+
+// maxSnooze is the furthest a todo can be put off in one go.
+const maxSnooze = 365 * 24 * time.Hour
+
+// Snooze puts the todo off, either by d, counted from its due date or from
+// now if that is later, or until until; exactly one of them is given.
+func (s *TodoService) Snooze(ctx context.Context, id string, d time.Duration, until *time.Time) (*model.Todo, error) {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if (d == 0) == (until == nil) {
+		return nil, &ValidationError{Field: "for", Message: "Give either for or until"}
+	}
+	if d < 0 || d > maxSnooze {
+		return nil, &ValidationError{Field: "for", Message: "for must be positive and at most a year"}
+	}
+	t, err := s.store.GetTodo(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if t.Completed {
+		return nil, &ValidationError{Field: "id", Message: "A done todo can't be snoozed"}
+	}
+	now := s.now()
+	sn := model.Snooze{At: now, From: t.DueAt}
+	if until != nil {
+		sn.Until = *until
+	} else {
+		from := now
+		if t.DueAt != nil && t.DueAt.After(now) {
+			from = *t.DueAt
+		}
+		sn.Until = from.Add(d)
+	}
+	if !sn.Until.After(now) || sn.Until.Sub(now) > maxSnooze {
+		return nil, &ValidationError{Field: "until", Message: "until must be in the next year"}
+	}
+	if err := s.store.SnoozeTodo(ctx, t, sn); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, events.TodoUpdated, id, t)
+	return t, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+)
+
+func validateStatus(status string) error {
+	if status == "" {
+		return nil
+	}
+	for _, s := range model.Statuses {
+		if s == status {
+			return nil
+		}
+	}
+	return NewValidationError("status", "status must be one of %s", strings.Join(model.Statuses, ", "))
+}
+
+// resolveStatus settles a todo's status and completed flag from what the
+// caller set, given its status so far. A status wins; without one, as from
+// clients that only know completed, completing makes it done and
+// reopening makes a done todo todo again while keeping any other status.
+func resolveStatus(status string, completed bool, prev string) (string, bool) {
+	switch {
+	case status != "":
+	case completed:
+		status = model.StatusDone
+	case prev == model.StatusDone:
+		status = model.StatusTodo
+	default:
+		status = prev
+	}
+	return status, status == model.StatusDone
+}
+
+// completedAt is when a todo, completed or not as given, was completed:
+// now if it just was, or as before if it already was. before is nil for
+// new todos.
+func completedAt(before *model.Todo, completed bool, now time.Time) *time.Time {
+	switch {
+	case !completed:
+		return nil
+	case before != nil && before.Completed:
+		return before.CompletedAt
+	}
+	return &now
+}
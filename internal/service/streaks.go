@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+)
+
+// StreakStore is the persistence StreakService needs.
+type StreakStore interface {
+	CompletionDays(ctx context.Context, tz string) ([]store.DayCount, error)
+}
+
+// Badge milestones: streak lengths in days and total completions.
+var (
+	streakBadges     = []int{3, 7, 30, 100, 365}
+	completionBadges = []int{10, 100, 1000}
+)
+
+// Badge is a milestone reached.
+type Badge struct {
+	Name  string
+	Title string
+}
+
+func streakBadge(days int) Badge {
+	return Badge{Name: fmt.Sprintf("streak_%d", days), Title: fmt.Sprintf("%d-day streak", days)}
+}
+
+func completionBadge(n int) Badge {
+	return Badge{Name: fmt.Sprintf("completed_%d", n), Title: fmt.Sprintf("%d todos completed", n)}
+}
+
+// Streaks is the run of days on which todos were completed.
+type Streaks struct {
+	// Current counts the days in a row, up to today, with a completion.
+	// A streak reaching yesterday still stands until today ends.
+	Current int
+	Longest int
+	// Completed counts the completions recorded.
+	Completed int
+	// Today counts today's completions.
+	Today  int
+	Badges []Badge
+}
+
+// StreakService keeps track of completion streaks and celebrates their
+// milestones with StreakMilestone events.
+type StreakService struct {
+	store StreakStore
+	bus   *events.Bus
+	now   func() time.Time
+	log   *log.Logger
+}
+
+// NewStreakService returns a service backed by s, watching completions
+// on bus.
+func NewStreakService(s StreakStore, bus *events.Bus, now func() time.Time, logger *log.Logger) *StreakService {
+	st := &StreakService{store: s, bus: bus, now: now, log: logger}
+	bus.Subscribe("streaks", st.completed)
+	return st
+}
+
+// Streaks returns the tenant's streaks and the badges earned, in its time
+// zone.
+func (s *StreakService) Streaks(ctx context.Context) (*Streaks, error) {
+	now := s.now().In(tz.FromContext(ctx))
+	days, err := s.store.CompletionDays(ctx, now.Format("-07:00"))
+	if err != nil {
+		return nil, err
+	}
+	out := &Streaks{Badges: []Badge{}}
+	run := 0
+	var prev time.Time
+	for _, d := range days {
+		day, err := time.ParseInLocation("2006-01-02", d.Day, now.Location())
+		if err != nil {
+			return nil, err
+		}
+		if run > 0 && day.Equal(prev.AddDate(0, 0, 1)) {
+			run++
+		} else {
+			run = 1
+		}
+		prev = day
+		if run > out.Longest {
+			out.Longest = run
+		}
+		out.Completed += d.Completed
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if len(days) > 0 && (prev.Equal(today) || prev.Equal(today.AddDate(0, 0, -1))) {
+		out.Current = run
+	}
+	if len(days) > 0 && prev.Equal(today) {
+		out.Today = days[len(days)-1].Completed
+	}
+	for _, n := range streakBadges {
+		if out.Longest >= n {
+			out.Badges = append(out.Badges, streakBadge(n))
+		}
+	}
+	for _, n := range completionBadges {
+		if out.Completed >= n {
+			out.Badges = append(out.Badges, completionBadge(n))
+		}
+	}
+	return out, nil
+}
+
+// completed publishes a StreakMilestone when a completion reaches one: the
+// first completion of a day that makes the streak a badge's length, or
+// the completion that makes the total a badge's count.
+func (s *StreakService) completed(e events.Event) {
+	if e.Type != events.TodoCompleted {
+		return
+	}
+	ctx := tenant.NewContext(context.Background(), e.Tenant)
+	st, err := s.Streaks(ctx)
+	if err != nil {
+		s.log.Printf("streaks: %v", err)
+		return
+	}
+	var reached []Badge
+	for _, n := range streakBadges {
+		if st.Today == 1 && st.Current == n {
+			reached = append(reached, streakBadge(n))
+		}
+	}
+	for _, n := range completionBadges {
+		if st.Completed == n {
+			reached = append(reached, completionBadge(n))
+		}
+	}
+	for _, b := range reached {
+		s.bus.Publish(events.Event{
+			Type:   events.StreakMilestone,
+			TodoID: e.TodoID,
+			Tenant: e.Tenant,
+			At:     s.now(),
+			Todo:   e.Todo,
+			Badge:  b.Title,
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// SyncStore is the persistence SyncService needs.
+type SyncStore interface {
+	ChangedTodos(ctx context.Context, p store.SyncPos, limit int64) ([]model.Todo, error)
+	Tombstones(ctx context.Context, p store.SyncPos, limit int64) ([]model.Tombstone, error)
+	SetExternalID(ctx context.Context, id bson.ObjectID, integration, externalID string) error
+	GetTodoByExternalID(ctx context.Context, integration, externalID string) (*model.Todo, error)
+}
+
+const (
+	// SyncIntegration is the key Todo.External keeps a client's id for a
+	// todo it created under.
+	SyncIntegration = "sync"
+	// syncPage caps the todos, and the deletions, one pull returns.
+	syncPage = 500
+	// syncSettle is how far behind the clock a cursor stays, so a write
+	// stamped a moment before another but saved after it isn't skipped.
+	// Changes in that window are sent again on the next pull.
+	syncSettle = 5 * time.Second
+	// maxSyncPush caps the changes one push may carry.
+	maxSyncPush = 1000
+)
+
+// ErrCursorExpired is returned for a cursor older than the deletions are
+// remembered; the client must sync from scratch.
+var ErrCursorExpired = errors.New("the sync cursor has expired; sync again without one")
+
+// SyncService lets offline clients pull what changed since they last
+// synced and push what they changed meanwhile.
+type SyncService struct {
+	store SyncStore
+	todos *TodoService
+	now   func() time.Time
+}
+
+// NewSyncService returns a service backed by s, writing todos through
+// todos.
+func NewSyncService(s SyncStore, todos *TodoService, now func() time.Time) *SyncService {
+	return &SyncService{store: s, todos: todos, now: now}
+}
+
+// SyncChanges is one page of changes.
+type SyncChanges struct {
+	// Todos were created or updated since the cursor.
+	Todos []model.Todo
+	// Deleted are the ids of todos deleted or archived since the cursor.
+	Deleted []bson.ObjectID
+	// Cursor is where the next pull starts.
+	Cursor string
+	// More is set if there are more changes to pull straight away.
+	More bool
+}
+
+// syncCursor is a position in the todos and one in the tombstones.
+type syncCursor struct {
+	todos, deleted store.SyncPos
+}
+
+func (c syncCursor) String() string {
+	s := fmt.Sprintf("%d.%s.%d.%s", c.todos.At.UnixNano(), c.todos.ID.Hex(), c.deleted.At.UnixNano(), c.deleted.ID.Hex())
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+func parseSyncCursor(s string) (syncCursor, error) {
+	bad := &ValidationError{Field: "since", Message: "The sync cursor is invalid"}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return syncCursor{}, bad
+	}
+	parts := strings.Split(string(b), ".")
+	if len(parts) != 4 {
+		return syncCursor{}, bad
+	}
+	var pos [2]store.SyncPos
+	for i := range pos {
+		ns, err := strconv.ParseInt(parts[2*i], 10, 64)
+		if err != nil {
+			return syncCursor{}, bad
+		}
+		id, err := bson.ObjectIDFromHex(parts[2*i+1])
+		if err != nil {
+			return syncCursor{}, bad
+		}
+		pos[i] = store.SyncPos{At: time.Unix(0, ns).UTC(), ID: id}
+	}
+	return syncCursor{todos: pos[0], deleted: pos[1]}, nil
+}
+
+// Pull returns the changes since cursor, or every todo if cursor is
+// empty. A todo may be sent more than once; clients apply changes by id.
+func (s *SyncService) Pull(ctx context.Context, cursor string) (*SyncChanges, error) {
+	now := s.now()
+	settled := now.Add(-syncSettle)
+	var c syncCursor
+	if cursor == "" {
+		// Deletions before the first pull don't concern the client.
+		c.deleted.At = settled
+	} else {
+		var err error
+		if c, err = parseSyncCursor(cursor); err != nil {
+			return nil, err
+		}
+		if c.deleted.At.Before(now.Add(-store.TombstoneTTL)) {
+			return nil, ErrCursorExpired
+		}
+	}
+	todos, err := s.store.ChangedTodos(ctx, c.todos, syncPage)
+	if err != nil {
+		return nil, err
+	}
+	tombstones, err := s.store.Tombstones(ctx, c.deleted, syncPage)
+	if err != nil {
+		return nil, err
+	}
+	out := &SyncChanges{Todos: todos, More: len(todos) == syncPage || len(tombstones) == syncPage}
+	for _, t := range todos {
+		if t.UpdatedAt.After(settled) {
+			break
+		}
+		c.todos = store.SyncPos{At: t.UpdatedAt, ID: t.ID}
+	}
+	for _, t := range tombstones {
+		out.Deleted = append(out.Deleted, t.TodoID)
+		if !t.DeletedAt.After(settled) {
+			c.deleted = store.SyncPos{At: t.DeletedAt, ID: t.TodoID}
+		}
+	}
+	if len(tombstones) < syncPage && c.deleted.At.Before(settled) {
+		// Caught up: move on, so a client that sees no deletions for a
+		// while doesn't find its cursor expired.
+		c.deleted = store.SyncPos{At: settled}
+	}
+	out.Cursor = c.String()
+	return out, nil
+}
+
+// SyncChange is one change a client made offline: a deletion, or the new
+// values of a todo. ID names the todo to update or delete; a Base settles
+// an update that conflicts with changes made since the client last synced.
+type SyncChange struct {
+	BulkItem
+	// ClientID, made up by the client, names a todo it created, so that a
+	// creation pushed twice is applied once and later changes can refer
+	// to it before the client has learnt its ID.
+	ClientID string
+	Delete   bool
+}
+
+// SyncResult reports on one SyncChange.
+type SyncResult struct {
+	Todo    *model.Todo
+	Created bool
+	Err     error
+}
+
+// Push applies changes in order, one by one, since a change may build on
+// an earlier one, and reports on each. Deleting a todo already gone is
+// not an error.
+func (s *SyncService) Push(ctx context.Context, changes []SyncChange) ([]SyncResult, error) {
+	if len(changes) > maxSyncPush {
+		return nil, NewValidationError("changes", "A push may hold at most %d changes", maxSyncPush)
+	}
+	results := make([]SyncResult, len(changes))
+	for i, ch := range changes {
+		results[i] = s.apply(ctx, ch)
+	}
+	return results, nil
+}
+
+func (s *SyncService) apply(ctx context.Context, ch SyncChange) SyncResult {
+	id := strings.TrimSpace(ch.ID)
+	clientID := strings.TrimSpace(ch.ClientID)
+	if id == "" && clientID != "" {
+		t, err := s.store.GetTodoByExternalID(ctx, SyncIntegration, clientID)
+		switch {
+		case err == nil:
+			id = t.ID.Hex()
+		case !errors.Is(err, store.ErrNotFound):
+			return SyncResult{Err: err}
+		}
+	}
+	switch {
+	case ch.Delete:
+		if id == "" {
+			return SyncResult{}
+		}
+		if err := s.todos.Delete(ctx, id); err != nil && !errors.Is(err, store.ErrNotFound) {
+			return SyncResult{Err: err}
+		}
+		return SyncResult{}
+	case id != "":
+		t, err := s.todos.UpdateAgainst(ctx, id, ch.TodoInput, ch.Base, ch.OnConflict)
+		return SyncResult{Todo: t, Err: err}
+	}
+	t, err := s.todos.Create(ctx, ch.TodoInput)
+	if err == nil && clientID != "" {
+		if err = s.store.SetExternalID(ctx, t.ID, SyncIntegration, clientID); err == nil {
+			if t.External == nil {
+				t.External = map[string]string{}
+			}
+			t.External[SyncIntegration] = clientID
+		}
+	}
+	return SyncResult{Todo: t, Created: err == nil, Err: err}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"dhruvarora9/personal-todo-golang/internal/store"
+)
+
+// maxTags caps the tags on one todo.
+const maxTags = 20
+
+// tagPattern is what a tag may look like once lowercased: letters, digits,
+// '-' and '_', so that the separators of tag queries never appear in one.
+var tagPattern = regexp.MustCompile(`^[\p{L}\p{N}_-]{1,32}$`)
+
+// TagSlug turns free text, such as a category from another app, into a
+// valid tag: spaces become '-' and other characters a tag can't hold are
+// dropped. It is empty if nothing is left.
+func TagSlug(s string) string {
+	var out []rune
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsNumber(r), r == '-', r == '_':
+			out = append(out, r)
+		case unicode.IsSpace(r):
+			out = append(out, '-')
+		}
+		if len(out) == 32 {
+			break
+		}
+	}
+	return string(out)
+}
+
+// normalizeTags lowercases and trims tags, dropping blanks and repeats, and
+// rejects the result if any tag is malformed. field names the input in the
+// error.
+func normalizeTags(field string, tags []string) ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if !tagPattern.MatchString(tag) {
+			return nil, NewValidationError(field, "Tag %q may only hold letters, digits, '-' and '_', up to 32 of them", tag)
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	if len(out) > maxTags {
+		return nil, NewValidationError(field, "A todo may have at most %d tags", maxTags)
+	}
+	return out, nil
+}
+
+func normalizeFilter(f store.TodoFilter) (store.TodoFilter, error) {
+	var err error
+	for _, p := range []struct {
+		field string
+		tags  *[]string
+	}{{"tags", &f.Tags}, {"tags_any", &f.AnyTags}, {"tags_not", &f.NotTags}} {
+		if *p.tags, err = normalizeTags(p.field, *p.tags); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+)
+
+var (
+	// ErrTimerRunning is returned when starting a timer that already runs.
+	ErrTimerRunning = errors.New("a timer is already running for this todo")
+	// ErrNoTimer is returned when stopping a timer that doesn't run.
+	ErrNoTimer = errors.New("no timer is running for this todo")
+)
+
+// StartTimer starts tracking time against the todo.
+func (s *TodoService) StartTimer(ctx context.Context, id string) (*model.TimeEntry, error) {
+	t, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	e := &model.TimeEntry{TodoID: t.ID, Tags: t.Tags, Start: s.now()}
+	if err := s.store.StartTimer(ctx, e); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			return nil, ErrTimerRunning
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// StopTimer stops the todo's timer and adds the time to its total.
+func (s *TodoService) StopTimer(ctx context.Context, id string) (*model.TimeEntry, error) {
+	t, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	e, err := s.store.StopTimer(ctx, t.ID, s.now())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, ErrNoTimer
+	}
+	return e, err
+}
+
+// Report groupings for TimeReport.
+const (
+	ByDay = "day"
+	ByTag = "tag"
+)
+
+// TimeTotal is the time tracked under one key of a report: a day as
+// YYYY-MM-DD or a tag, "" for untagged todos.
+type TimeTotal struct {
+	Key      string
+	Duration time.Duration
+}
+
+// maxReportDays bounds the period of one report.
+const maxReportDays = 366
+
+func checkPeriod(from, to time.Time) error {
+	if !to.After(from) || to.Sub(from) > maxReportDays*24*time.Hour {
+		return &ValidationError{Field: "to", Message: "to must be after from, by at most 366 days"}
+	}
+	return nil
+}
+
+// TimeReport totals the time tracked from from until to, by day or by tag,
+// in key order. Entries count on the day they started, in the tenant's
+// time zone; one on several tags counts in full under each. Running
+// timers count up to now.
+func (s *TodoService) TimeReport(ctx context.Context, from, to time.Time, by string) ([]TimeTotal, error) {
+	if by != ByDay && by != ByTag {
+		return nil, &ValidationError{Field: "by", Message: "by must be day or tag"}
+	}
+	if err := checkPeriod(from, to); err != nil {
+		return nil, err
+	}
+	entries, err := s.store.ListTimeEntries(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	totals := map[string]time.Duration{}
+	for _, e := range entries {
+		d := e.Duration(now)
+		switch {
+		case by == ByDay:
+			totals[e.Start.In(tz.FromContext(ctx)).Format("2006-01-02")] += d
+		case len(e.Tags) == 0:
+			totals[""] += d
+		default:
+			for _, tag := range e.Tags {
+				totals[tag] += d
+			}
+		}
+	}
+	out := make([]TimeTotal, 0, len(totals))
+	for k, d := range totals {
+		out = append(out, TimeTotal{Key: k, Duration: d})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
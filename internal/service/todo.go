@@ -0,0 +1,555 @@
+// Package service holds the business rules for todos, shared by every
+// frontend (HTTP today) so they don't each reimplement them.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ValidationError is returned when the caller's input is unacceptable. Its
+// message is meant to be shown to the user.
+type ValidationError struct {
+	Field   string
+	Message string
+	// format and args are what a message with variable parts was made
+	// from, for translation.
+	format string
+	args   []interface{}
+}
+
+// NewValidationError returns a ValidationError whose message is format
+// filled in with args.
+func NewValidationError(field, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Field: field, Message: fmt.Sprintf(format, args...), format: format, args: args}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// In returns the message translated into locale.
+func (e *ValidationError) In(locale string) string {
+	if e.format == "" {
+		return i18n.T(locale, e.Message)
+	}
+	return i18n.T(locale, e.format, e.args...)
+}
+
+// Store is the persistence TodoService needs; *store.Store implements it.
+type Store interface {
+	ListTodos(ctx context.Context) ([]model.Todo, error)
+	EachTodo(ctx context.Context, f store.TodoFilter, skip, limit int64, fn func(*model.Todo) error) error
+	CountTodos(ctx context.Context, f store.TodoFilter) (int64, error)
+	GetTodo(ctx context.Context, id bson.ObjectID) (*model.Todo, error)
+	CreateTodo(ctx context.Context, t *model.Todo) error
+	UpdateTodo(ctx context.Context, t *model.Todo) error
+	UpdateTodoIf(ctx context.Context, t *model.Todo, version int64) error
+	DeleteTodo(ctx context.Context, id bson.ObjectID) error
+	ArchiveTodos(ctx context.Context, cutoff, now time.Time) (int, error)
+	AddTombstone(ctx context.Context, id bson.ObjectID, at time.Time) error
+	SearchArchive(ctx context.Context, q, list string, limit int) ([]model.Todo, error)
+	GetTodos(ctx context.Context, ids []bson.ObjectID) ([]model.Todo, error)
+	WriteTodos(ctx context.Context, todos []*model.Todo) []error
+	SearchTodos(ctx context.Context, q string, limit int) ([]model.Todo, error)
+	BackfillTrigrams(ctx context.Context) (int, error)
+	StartTimer(ctx context.Context, e *model.TimeEntry) error
+	StopTimer(ctx context.Context, todoID bson.ObjectID, end time.Time) (*model.TimeEntry, error)
+	ListTimeEntries(ctx context.Context, from, to time.Time) ([]model.TimeEntry, error)
+	StartPomodoro(ctx context.Context, p *model.Pomodoro) error
+	EndPomodoro(ctx context.Context, todoID, id bson.ObjectID, state string, end time.Time) (*model.Pomodoro, error)
+	ListPomodoros(ctx context.Context, from, to time.Time) ([]model.Pomodoro, error)
+	ListFieldDefs(ctx context.Context, list string) ([]model.FieldDef, error)
+	CreateFieldDef(ctx context.Context, d *model.FieldDef) error
+	DeleteFieldDef(ctx context.Context, id bson.ObjectID) error
+	BlockerEdges(ctx context.Context) (map[bson.ObjectID][]bson.ObjectID, error)
+	AddBlocker(ctx context.Context, id, blocker bson.ObjectID) error
+	RemoveBlocker(ctx context.Context, id, blocker bson.ObjectID) error
+	UnlinkBlocker(ctx context.Context, blocker bson.ObjectID) error
+	SnoozeTodo(ctx context.Context, t *model.Todo, sn model.Snooze) error
+	OpenTodosNear(ctx context.Context, p model.GeoPoint, maxMeters int) ([]model.Todo, error)
+	AddLink(ctx context.Context, id bson.ObjectID, l model.Link) error
+	RemoveLink(ctx context.Context, id bson.ObjectID, l model.Link) error
+	LinksTo(ctx context.Context, id bson.ObjectID) ([]model.Todo, error)
+	UnlinkTodo(ctx context.Context, id bson.ObjectID) error
+	SetSlug(ctx context.Context, id bson.ObjectID, slug string) error
+	TodoBySlug(ctx context.Context, slug string) (*model.Todo, error)
+	TenantsWanting(ctx context.Context, eventType string) ([]string, error)
+	GetSettings(ctx context.Context) (*model.Settings, error)
+	ListShares(ctx context.Context) ([]model.Share, error)
+	WeeklyCounts(ctx context.Context, from time.Time, weeks int) ([]store.WeekCounts, []store.TagCount, error)
+}
+
+type TodoService struct {
+	store Store
+	bus   *events.Bus
+	now   func() time.Time
+	log   *log.Logger
+}
+
+// NewTodoService returns a service backed by s that announces changes on bus
+// and takes the current time from now (normally time.Now). Failures of
+// background work, such as one tenant's digest, are logged to logger.
+func NewTodoService(s Store, bus *events.Bus, now func() time.Time, logger *log.Logger) *TodoService {
+	return &TodoService{store: s, bus: bus, now: now, log: logger}
+}
+
+func (s *TodoService) publish(ctx context.Context, typ, id string, t *model.Todo) {
+	s.bus.Publish(events.Event{Type: typ, TodoID: id, Tenant: tenant.FromContext(ctx), At: s.now(), Todo: t})
+}
+
+func validateTitle(title string) (string, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return "", &ValidationError{Field: "title", Message: "The title field is required"}
+	}
+	return title, nil
+}
+
+func (s *TodoService) List(ctx context.Context) ([]model.Todo, error) {
+	return s.store.ListTodos(ctx)
+}
+
+// Each streams the todos matching f to fn, oldest first, skipping offset
+// of them and stopping after limit (0 for no limit). Prefer it to List for
+// responses that may be large.
+func (s *TodoService) Each(ctx context.Context, f store.TodoFilter, offset, limit int, fn func(*model.Todo) error) error {
+	f, err := normalizeFilter(f)
+	if err == nil {
+		f, err = s.typedFields(ctx, f)
+	}
+	if err != nil {
+		return err
+	}
+	return s.store.EachTodo(ctx, f, int64(offset), int64(limit), fn)
+}
+
+// Count returns how many todos match f.
+func (s *TodoService) Count(ctx context.Context, f store.TodoFilter) (int64, error) {
+	f, err := normalizeFilter(f)
+	if err == nil {
+		f, err = s.typedFields(ctx, f)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return s.store.CountTodos(ctx, f)
+}
+
+func (s *TodoService) Get(ctx context.Context, id string) (*model.Todo, error) {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.GetTodo(ctx, oid)
+}
+
+// TodoInput holds the fields callers set on a todo.
+type TodoInput struct {
+	Title     string
+	List      string
+	Completed bool
+	// Status, if set, overrides Completed.
+	Status string
+	DueAt  *time.Time
+	Tags   []string
+	Notes  string
+	// EstimateMinutes is 0 for no estimate.
+	EstimateMinutes int
+	// Fields sets the custom fields of the todo's list.
+	Fields map[string]interface{}
+	// Geofence is nil for a todo tied to no place.
+	Geofence *model.Geofence
+}
+
+func (s *TodoService) Create(ctx context.Context, in TodoInput) (*model.Todo, error) {
+	title, err := validateTitle(in.Title)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := normalizeTags("tags", in.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateEstimate(in.EstimateMinutes); err != nil {
+		return nil, err
+	}
+	if err := validateGeofence(in.Geofence); err != nil {
+		return nil, err
+	}
+	if err := validateStatus(in.Status); err != nil {
+		return nil, err
+	}
+	status, completed := resolveStatus(in.Status, in.Completed, model.StatusTodo)
+	list := strings.TrimSpace(in.List)
+	fields, err := s.fieldDefs().check(ctx, list, in.Fields)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	t := &model.Todo{
+		Title:           title,
+		Completed:       completed,
+		CompletedAt:     completedAt(nil, completed, now),
+		Status:          status,
+		List:            list,
+		DueAt:           in.DueAt,
+		Tags:            tags,
+		Notes:           in.Notes,
+		EstimateMinutes: in.EstimateMinutes,
+		Fields:          fields,
+		Geofence:        in.Geofence,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.store.CreateTodo(ctx, t); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, events.TodoCreated, t.ID.Hex(), t)
+	return t, nil
+}
+
+// Update replaces the editable fields of the todo with in.
+func (s *TodoService) Update(ctx context.Context, id string, in TodoInput) (*model.Todo, error) {
+	return s.update(ctx, id, in, nil)
+}
+
+// update is Update. Given before, the todo as last read, the write only
+// lands if nobody has updated the todo since; otherwise it fails with
+// store.ErrNotFound.
+func (s *TodoService) update(ctx context.Context, id string, in TodoInput, before *model.Todo) (*model.Todo, error) {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+	title, err := validateTitle(in.Title)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := normalizeTags("tags", in.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateEstimate(in.EstimateMinutes); err != nil {
+		return nil, err
+	}
+	if err := validateGeofence(in.Geofence); err != nil {
+		return nil, err
+	}
+	if err := validateStatus(in.Status); err != nil {
+		return nil, err
+	}
+	guarded := before != nil
+	if !guarded {
+		if before, err = s.store.GetTodo(ctx, oid); err != nil {
+			return nil, err
+		}
+	}
+	status, completed := resolveStatus(in.Status, in.Completed, before.CurrentStatus())
+	list := strings.TrimSpace(in.List)
+	fields, err := s.fieldDefs().check(ctx, list, in.Fields)
+	if err != nil {
+		return nil, err
+	}
+	t := &model.Todo{
+		ID:              oid,
+		Title:           title,
+		Completed:       completed,
+		Status:          status,
+		List:            list,
+		DueAt:           in.DueAt,
+		Tags:            tags,
+		Notes:           in.Notes,
+		EstimateMinutes: in.EstimateMinutes,
+		Fields:          fields,
+		Geofence:        in.Geofence,
+		UpdatedAt:       s.now(),
+	}
+	if err := s.checkUnblocked(ctx, before, t.Completed); err != nil {
+		return nil, err
+	}
+	t.CompletedAt = completedAt(before, completed, t.UpdatedAt)
+	if !guarded {
+		return t, s.save(ctx, before, t)
+	}
+	if err := s.store.UpdateTodoIf(ctx, t, before.Version); err != nil {
+		return nil, err
+	}
+	s.publishUpdate(ctx, before, t)
+	return t, nil
+}
+
+// SetCompleted marks the todo done or not done, leaving the rest as is. It
+// refuses to complete a todo blocked by others not yet done.
+func (s *TodoService) SetCompleted(ctx context.Context, id string, completed bool) (*model.Todo, error) {
+	return s.setCompleted(ctx, id, completed, true)
+}
+
+// SyncCompleted is SetCompleted for integrations mirroring another
+// system, whose word is final: blockers don't stop it.
+func (s *TodoService) SyncCompleted(ctx context.Context, id string, completed bool) (*model.Todo, error) {
+	return s.setCompleted(ctx, id, completed, false)
+}
+
+func (s *TodoService) setCompleted(ctx context.Context, id string, completed, checkBlockers bool) (*model.Todo, error) {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.store.GetTodo(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	t := *before
+	t.Status, t.Completed = resolveStatus("", completed, before.CurrentStatus())
+	t.UpdatedAt = s.now()
+	t.CompletedAt = completedAt(before, t.Completed, t.UpdatedAt)
+	if checkBlockers {
+		if err := s.checkUnblocked(ctx, before, t.Completed); err != nil {
+			return nil, err
+		}
+	}
+	return &t, s.save(ctx, before, &t)
+}
+
+func (s *TodoService) save(ctx context.Context, before, t *model.Todo) error {
+	if err := s.store.UpdateTodo(ctx, t); err != nil {
+		return err
+	}
+	s.publishUpdate(ctx, before, t)
+	return nil
+}
+
+// publishUpdate publishes the events of t's update from before.
+func (s *TodoService) publishUpdate(ctx context.Context, before, t *model.Todo) {
+	id := t.ID.Hex()
+	s.publish(ctx, events.TodoUpdated, id, t)
+	if t.Completed && !before.Completed {
+		s.publish(ctx, events.TodoCompleted, id, t)
+	}
+}
+
+func (s *TodoService) Delete(ctx context.Context, id string) error {
+	oid, err := store.ParseID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.store.DeleteTodo(ctx, oid); err != nil {
+		return err
+	}
+	if err := s.store.AddTombstone(ctx, oid, s.now()); err != nil {
+		return err
+	}
+	// Todos left pointing at a deleted one aren't blocked by it, and links
+	// to it are skipped, so this is only tidying up.
+	_ = s.store.UnlinkBlocker(ctx, oid)
+	_ = s.store.UnlinkTodo(ctx, oid)
+	s.publish(ctx, events.TodoDeleted, id, nil)
+	return nil
+}
+
+// Archive moves todos not updated in the last months months out of the
+// live collection, for every tenant, and returns how many it moved.
+func (s *TodoService) Archive(ctx context.Context, months int) (int, error) {
+	now := s.now()
+	return s.store.ArchiveTodos(ctx, now.AddDate(0, -months, 0), now)
+}
+
+// maxArchiveResults caps one archive search.
+const maxArchiveResults = 200
+
+// SearchArchive finds archived todos whose title contains q, optionally
+// only those on list. limit is clamped to 1..200.
+func (s *TodoService) SearchArchive(ctx context.Context, q, list string, limit int) ([]model.Todo, error) {
+	if limit < 1 || limit > maxArchiveResults {
+		limit = maxArchiveResults
+	}
+	return s.store.SearchArchive(ctx, strings.TrimSpace(q), strings.TrimSpace(list), limit)
+}
+
+// maxSearchResults caps one fuzzy search.
+const maxSearchResults = 50
+
+// Search finds todos whose title matches q despite typos, best match first.
+// limit is clamped to 1..50.
+func (s *TodoService) Search(ctx context.Context, q string, limit int) ([]model.Todo, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return nil, &ValidationError{Field: "q", Message: "The q parameter is required"}
+	}
+	if limit < 1 || limit > maxSearchResults {
+		limit = maxSearchResults
+	}
+	return s.store.SearchTodos(ctx, q, limit)
+}
+
+// ReindexSearch makes todos saved before search existed findable and
+// returns how many it updated.
+func (s *TodoService) ReindexSearch(ctx context.Context) (int, error) {
+	return s.store.BackfillTrigrams(ctx)
+}
+
+// BulkItem is one entry of WriteMany: a new todo when ID is empty, else new
+// values for the todo with that ID.
+type BulkItem struct {
+	ID string
+	TodoInput
+	// Base, if set, is the copy of the todo the update was made against,
+	// and OnConflict the strategy for changes made since.
+	Base       *Base
+	OnConflict string
+}
+
+// BulkResult reports on one BulkItem: the saved todo, or why it failed.
+type BulkResult struct {
+	Todo    *model.Todo
+	Created bool
+	Err     error
+}
+
+// WriteMany saves items in as few database round trips as the store allows
+// and reports on each one; an item that fails doesn't hold up the others.
+// Imports and bulk endpoints go through here. Updates made against a base
+// are saved one at a time by UpdateAgainst, so none is lost to a write
+// landing meanwhile.
+func (s *TodoService) WriteMany(ctx context.Context, items []BulkItem) []BulkResult {
+	results := make([]BulkResult, len(items))
+	todos := make([]*model.Todo, len(items))
+	var ids []bson.ObjectID
+	now := s.now()
+	defs := s.fieldDefs()
+	for i, in := range items {
+		if in.Base != nil && in.ID != "" {
+			results[i].Todo, results[i].Err = s.UpdateAgainst(ctx, in.ID, in.TodoInput, in.Base, in.OnConflict)
+		}
+	}
+	for i, in := range items {
+		if results[i].Err != nil || results[i].Todo != nil {
+			continue
+		}
+		title, err := validateTitle(in.Title)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		tags, err := normalizeTags("tags", in.Tags)
+		if err == nil {
+			err = validateEstimate(in.EstimateMinutes)
+		}
+		if err == nil {
+			err = validateGeofence(in.Geofence)
+		}
+		if err == nil {
+			err = validateStatus(in.Status)
+		}
+		list := strings.TrimSpace(in.List)
+		var fields map[string]interface{}
+		if err == nil {
+			fields, err = defs.check(ctx, list, in.Fields)
+		}
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		t := &model.Todo{
+			Title:           title,
+			List:            list,
+			DueAt:           in.DueAt,
+			Tags:            tags,
+			Notes:           in.Notes,
+			EstimateMinutes: in.EstimateMinutes,
+			Fields:          fields,
+			Geofence:        in.Geofence,
+			UpdatedAt:       now,
+		}
+		if in.ID == "" {
+			t.Status, t.Completed = resolveStatus(in.Status, in.Completed, model.StatusTodo)
+			t.CompletedAt = completedAt(nil, t.Completed, now)
+			t.CreatedAt = now
+			results[i].Created = true
+		} else if t.ID, err = store.ParseID(in.ID); err != nil {
+			results[i].Err = err
+			continue
+		} else {
+			ids = append(ids, t.ID)
+		}
+		todos[i] = t
+	}
+
+	// Updates need the todos as they were, both to report missing ones and
+	// to tell whether an update completes its todo.
+	before := map[bson.ObjectID]model.Todo{}
+	if len(ids) > 0 {
+		found, err := s.store.GetTodos(ctx, ids)
+		if err != nil {
+			for i, t := range todos {
+				if t != nil && !results[i].Created {
+					results[i].Err, todos[i] = err, nil
+				}
+			}
+		}
+		for _, t := range found {
+			before[t.ID] = t
+		}
+	}
+	var batch []*model.Todo
+	var index []int
+	for i, t := range todos {
+		if t == nil {
+			continue
+		}
+		if !results[i].Created {
+			b, ok := before[t.ID]
+			if !ok {
+				results[i].Err = store.ErrNotFound
+				continue
+			}
+			// The stored todo with the edit laid over it is what the
+			// write leaves, so results and events show its other fields.
+			edit := *t
+			*t = b
+			t.Title, t.List, t.DueAt, t.Tags = edit.Title, edit.List, edit.DueAt, edit.Tags
+			t.Notes, t.EstimateMinutes, t.Fields = edit.Notes, edit.EstimateMinutes, edit.Fields
+			t.Geofence, t.UpdatedAt = edit.Geofence, edit.UpdatedAt
+			t.Version++
+			t.Status, t.Completed = resolveStatus(items[i].Status, items[i].Completed, b.CurrentStatus())
+			if err := s.checkUnblocked(ctx, &b, t.Completed); err != nil {
+				results[i].Err = err
+				continue
+			}
+			t.CompletedAt = completedAt(&b, t.Completed, now)
+		}
+		batch = append(batch, t)
+		index = append(index, i)
+	}
+
+	errs := s.store.WriteTodos(ctx, batch)
+	for j, t := range batch {
+		i := index[j]
+		if errs[j] != nil {
+			results[i].Err = errs[j]
+			continue
+		}
+		results[i].Todo = t
+		id := t.ID.Hex()
+		if results[i].Created {
+			s.publish(ctx, events.TodoCreated, id, t)
+			continue
+		}
+		s.publish(ctx, events.TodoUpdated, id, t)
+		if t.Completed && !before[t.ID].Completed {
+			s.publish(ctx, events.TodoCompleted, id, t)
+		}
+	}
+	return results
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/tz"
+)
+
+// maxEstimateMinutes caps an estimate at a working month.
+const maxEstimateMinutes = 10000
+
+func validateEstimate(minutes int) error {
+	if minutes < 0 || minutes > maxEstimateMinutes {
+		return &ValidationError{Field: "estimate_minutes", Message: "estimate_minutes must be between 0 and 10000"}
+	}
+	return nil
+}
+
+// WorkloadDay is the estimated effort of the open todos due on one day.
+type WorkloadDay struct {
+	// Day is YYYY-MM-DD in the tenant's time zone.
+	Day     string
+	Minutes int
+	Todos   int
+	// Unestimated counts the todos without an estimate, which Minutes
+	// leaves out.
+	Unestimated int
+}
+
+// Workload sums, per day, the estimates of the todos not yet done that are
+// due from from until to. Days with nothing due are left out.
+func (s *TodoService) Workload(ctx context.Context, from, to time.Time) ([]WorkloadDay, error) {
+	if err := checkPeriod(from, to); err != nil {
+		return nil, err
+	}
+	open := false
+	f := store.TodoFilter{Completed: &open, DueAfter: &from, DueBefore: &to, Sort: "due_at"}
+	loc := tz.FromContext(ctx)
+	var out []WorkloadDay
+	err := s.store.EachTodo(ctx, f, 0, 0, func(t *model.Todo) error {
+		day := t.DueAt.In(loc).Format("2006-01-02")
+		if len(out) == 0 || out[len(out)-1].Day != day {
+			out = append(out, WorkloadDay{Day: day})
+		}
+		d := &out[len(out)-1]
+		d.Todos++
+		d.Minutes += t.EstimateMinutes
+		if t.EstimateMinutes == 0 {
+			d.Unestimated++
+		}
+		return nil
+	})
+	return out, err
+}
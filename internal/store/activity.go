@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const activityCollection = "activity"
+
+// activityDays is how long the activity feed remembers.
+const activityDays = 90
+
+func (s *Store) activity() *mongo.Collection {
+	return s.db.Collection(activityCollection)
+}
+
+// AddActivity inserts a, giving it a new id.
+func (s *Store) AddActivity(ctx context.Context, a *model.Activity) error {
+	a.ID = bson.NewObjectID()
+	a.TenantID = tenant.FromContext(ctx)
+	return s.retry(ctx, insertOnce(func() error {
+		_, err := s.activity().InsertOne(ctx, a)
+		return err
+	}))
+}
+
+// ListActivity returns up to limit entries, newest first, from those
+// added before the entry before, or from the newest if before is zero.
+func (s *Store) ListActivity(ctx context.Context, before bson.ObjectID, limit int64) ([]model.Activity, error) {
+	out := []model.Activity{}
+	filter := bson.M{}
+	if !before.IsZero() {
+		filter["_id"] = bson.M{"$lt": before}
+	}
+	err := s.retry(ctx, func() error {
+		cur, err := s.reads(activityCollection).Find(ctx, scope(ctx, filter),
+			options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const archiveCollection = "todo_archive"
+
+// archiveBatch is how many todos ArchiveTodos moves per round trip.
+const archiveBatch = 500
+
+func (s *Store) archive() *mongo.Collection {
+	return s.db.Collection(archiveCollection)
+}
+
+// ArchiveTodos moves every todo, of any tenant, last updated before cutoff
+// from the todo collection to the archive and returns how many it moved.
+// Todos are copied before they're deleted, so an interrupted run loses
+// nothing and the next one picks up where it stopped. Each leaves a
+// tombstone dated now.
+func (s *Store) ArchiveTodos(ctx context.Context, cutoff, now time.Time) (int, error) {
+	moved := 0
+	for {
+		var batch []model.Todo
+		err := s.retry(ctx, func() error {
+			cur, err := s.todos().Find(ctx, bson.M{"updated_at": bson.M{"$lt": cutoff}},
+				options.Find().SetLimit(archiveBatch))
+			if err != nil {
+				return err
+			}
+			return cur.All(ctx, &batch)
+		})
+		if err != nil {
+			return moved, err
+		}
+		if len(batch) == 0 {
+			return moved, nil
+		}
+		writes := make([]mongo.WriteModel, 0, len(batch))
+		ids := make([]bson.ObjectID, 0, len(batch))
+		tombstones := make([]model.Tombstone, 0, len(batch))
+		for i := range batch {
+			writes = append(writes, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"_id": batch[i].ID}).SetReplacement(batch[i]).SetUpsert(true))
+			ids = append(ids, batch[i].ID)
+			tombstones = append(tombstones, model.Tombstone{TodoID: batch[i].ID, TenantID: batch[i].TenantID, DeletedAt: now})
+		}
+
+		err = s.retry(ctx, func() error {
+			_, err := s.archive().BulkWrite(ctx, writes)
+			return err
+		})
+		if err != nil {
+			return moved, err
+		}
+		var deleted int64
+		err = s.retry(ctx, func() error {
+			res, err := s.todos().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+			if err == nil {
+				deleted += res.DeletedCount
+			}
+			return err
+		})
+		if err != nil {
+			return moved, err
+		}
+		if err := s.writeTombstones(ctx, tombstones); err != nil {
+			return moved, err
+		}
+		moved += int(deleted)
+	}
+}
+
+// SearchArchive returns up to limit archived todos, most recently updated
+// first, whose title contains q (ignoring case) and, if list is set, that
+// are on list.
+func (s *Store) SearchArchive(ctx context.Context, q, list string, limit int) ([]model.Todo, error) {
+	filter := bson.M{}
+	if q != "" {
+		filter["title"] = bson.M{"$regex": regexp.QuoteMeta(q), "$options": "i"}
+	}
+	if list != "" {
+		filter["list"] = list
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}}).SetLimit(int64(limit))
+	out := []model.Todo{}
+	err := s.retry(ctx, func() error {
+		cur, err := s.reads(archiveCollection).Find(ctx, scope(ctx, filter), opts)
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// filter is the tenant's MongoDB filter for f, looking up which todos
+// still block others when f asks about being blocked.
+func (s *Store) filter(ctx context.Context, f TodoFilter) (bson.M, error) {
+	q := f.query()
+	if f.Blocked == nil {
+		return scope(ctx, q), nil
+	}
+	open, err := s.openBlockers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	op := "$nin"
+	if *f.Blocked {
+		op = "$in"
+	}
+	q["blocked_by"] = bson.M{op: open}
+	return scope(ctx, q), nil
+}
+
+// openBlockers returns the todos, not yet done, that block another.
+func (s *Store) openBlockers(ctx context.Context) ([]bson.ObjectID, error) {
+	var ids []bson.ObjectID
+	err := s.retry(ctx, func() error {
+		return s.reads(collectionName).Distinct(ctx, "blocked_by", scope(ctx, bson.M{})).Decode(&ids)
+	})
+	if err != nil || len(ids) == 0 {
+		return []bson.ObjectID{}, err
+	}
+	open := []bson.ObjectID{}
+	err = s.retry(ctx, func() error {
+		return s.reads(collectionName).Distinct(ctx, "_id",
+			scope(ctx, bson.M{"_id": bson.M{"$in": ids}, "completed": false})).Decode(&open)
+	})
+	return open, err
+}
+
+// BlockerEdges returns, for every todo blocked by others, the todos that
+// block it.
+func (s *Store) BlockerEdges(ctx context.Context) (map[bson.ObjectID][]bson.ObjectID, error) {
+	var docs []struct {
+		ID        bson.ObjectID   `bson:"_id"`
+		BlockedBy []bson.ObjectID `bson:"blocked_by"`
+	}
+	err := s.retry(ctx, func() error {
+		cur, err := s.reads(collectionName).Find(ctx,
+			scope(ctx, bson.M{"blocked_by.0": bson.M{"$exists": true}}),
+			options.Find().SetProjection(bson.M{"blocked_by": 1}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &docs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	edges := make(map[bson.ObjectID][]bson.ObjectID, len(docs))
+	for _, d := range docs {
+		edges[d.ID] = d.BlockedBy
+	}
+	return edges, nil
+}
+
+// AddBlocker records that blocker blocks the todo id. Blocker changes
+// stamp updated_at with the database's clock, so sync picks them up.
+func (s *Store) AddBlocker(ctx context.Context, id, blocker bson.ObjectID) error {
+	return s.retry(ctx, func() error {
+		return matched(s.todos().UpdateOne(ctx, scope(ctx, bson.M{"_id": id}),
+			bson.M{"$addToSet": bson.M{"blocked_by": blocker}, "$currentDate": bson.M{"updated_at": true}}))
+	})
+}
+
+// RemoveBlocker undoes AddBlocker; it is not an error if blocker didn't
+// block the todo.
+func (s *Store) RemoveBlocker(ctx context.Context, id, blocker bson.ObjectID) error {
+	return s.retry(ctx, func() error {
+		return matched(s.todos().UpdateOne(ctx, scope(ctx, bson.M{"_id": id}),
+			bson.M{"$pull": bson.M{"blocked_by": blocker}, "$currentDate": bson.M{"updated_at": true}}))
+	})
+}
+
+// UnlinkBlocker removes a deleted todo from the blockers of the others.
+func (s *Store) UnlinkBlocker(ctx context.Context, blocker bson.ObjectID) error {
+	return s.retry(ctx, func() error {
+		_, err := s.todos().UpdateMany(ctx, scope(ctx, bson.M{"blocked_by": blocker}),
+			bson.M{"$pull": bson.M{"blocked_by": blocker}, "$currentDate": bson.M{"updated_at": true}})
+		return err
+	})
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"dhruvarora9/personal-todo-golang/internal/fuzzy"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// GetTodos returns the todos with the given ids that exist, in no
+// particular order.
+func (s *Store) GetTodos(ctx context.Context, ids []bson.ObjectID) ([]model.Todo, error) {
+	out := []model.Todo{}
+	err := s.retry(ctx, func() error {
+		cur, err := s.todos().Find(ctx, scope(ctx, bson.M{"_id": bson.M{"$in": ids}}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WriteTodos inserts the todos without an id, giving them one, and saves
+// the editable fields of the rest, in unordered bulk writes of up to the
+// configured batch size. The result holds the error, if any, for each todo
+// by index: one document failing doesn't stop the others, and a batch
+// that fails as a whole fails each of its documents. Batches failing
+// transiently are retried like single writes. Updates of todos that don't
+// exist are not reported; check beforehand.
+func (s *Store) WriteTodos(ctx context.Context, todos []*model.Todo) []error {
+	errs := make([]error, len(todos))
+	id := tenant.FromContext(ctx)
+	for start := 0; start < len(todos); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(todos) {
+			end = len(todos)
+		}
+		writes := make([]mongo.WriteModel, 0, end-start)
+		inserted := make([]bool, 0, end-start)
+		for _, t := range todos[start:end] {
+			if t.ID.IsZero() {
+				t.ID = bson.NewObjectID()
+				t.TenantID = id
+				t.Trigrams = fuzzy.Trigrams(t.Title)
+				t.Location = t.Geofence.Point()
+				writes = append(writes, mongo.NewInsertOneModel().SetDocument(t))
+				inserted = append(inserted, true)
+				continue
+			}
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(scope(ctx, bson.M{"_id": t.ID})).
+				SetUpdate(bson.M{"$set": editable(t), "$inc": bumpVersion}))
+			inserted = append(inserted, false)
+		}
+		tries := 0
+		err := s.retry(ctx, func() error {
+			tries++
+			_, err := s.todos().BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+			var bwe mongo.BulkWriteException
+			if !errors.As(err, &bwe) || bwe.WriteConcernError != nil {
+				return err
+			}
+			for _, we := range bwe.WriteErrors {
+				if transient(we) {
+					return err
+				}
+			}
+			// The rest of the failures are the documents' own. A
+			// duplicate of an id given here means an earlier attempt
+			// inserted it after all.
+			for _, we := range bwe.WriteErrors {
+				if tries > 1 && inserted[we.Index] && mongo.IsDuplicateKeyError(we) {
+					continue
+				}
+				errs[start+we.Index] = translate(we)
+			}
+			return nil
+		})
+		if err != nil {
+			for i := start; i < end; i++ {
+				errs[i] = err
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,40 @@
+package store
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var (
+	// ErrNotFound means no todo has the given id.
+	ErrNotFound = errors.New("store: not found")
+	// ErrConflict means the write clashes with an existing document.
+	ErrConflict = errors.New("store: conflict")
+	// ErrInvalidID means the id is not in the format the store uses.
+	ErrInvalidID = errors.New("store: invalid id")
+)
+
+// ParseID converts a todo id from its string form.
+func ParseID(id string) (bson.ObjectID, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return oid, ErrInvalidID
+	}
+	return oid, nil
+}
+
+// translate turns driver errors the rest of the code cares about into the
+// store's own errors.
+func translate(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return ErrConflict
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const fieldCollection = "custom_fields"
+
+func (s *Store) fieldDefs() *mongo.Collection {
+	return s.db.Collection(fieldCollection)
+}
+
+// ListFieldDefs returns the custom fields defined on list, by name.
+func (s *Store) ListFieldDefs(ctx context.Context, list string) ([]model.FieldDef, error) {
+	out := []model.FieldDef{}
+	err := s.retry(ctx, func() error {
+		cur, err := s.fieldDefs().Find(ctx, scope(ctx, bson.M{"list": list}),
+			options.Find().SetSort(bson.M{"name": 1}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateFieldDef inserts d, giving it a new id. It returns ErrConflict if
+// the list already has a field of that name.
+func (s *Store) CreateFieldDef(ctx context.Context, d *model.FieldDef) error {
+	d.ID = bson.NewObjectID()
+	d.TenantID = tenant.FromContext(ctx)
+	return s.retry(ctx, insertOnce(func() error {
+		_, err := s.fieldDefs().InsertOne(ctx, d)
+		return err
+	}))
+}
+
+// DeleteFieldDef removes the definition and the values todos on its list
+// hold for it. Only clearing the values is retried; if it fails for good,
+// the values stay behind, undefined.
+func (s *Store) DeleteFieldDef(ctx context.Context, id bson.ObjectID) error {
+	var d model.FieldDef
+	if err := s.fieldDefs().FindOneAndDelete(ctx, scope(ctx, bson.M{"_id": id})).Decode(&d); err != nil {
+		return translate(err)
+	}
+	// Todos on the default list have no list at all.
+	var list interface{} = d.List
+	if d.List == "" {
+		list = bson.M{"$in": bson.A{nil, ""}}
+	}
+	return s.retry(ctx, func() error {
+		_, err := s.todos().UpdateMany(ctx, scope(ctx, bson.M{"list": list}),
+			bson.M{"$unset": bson.M{"fields." + d.Name: ""}})
+		return err
+	})
+}
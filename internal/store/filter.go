@@ -0,0 +1,102 @@
+package store
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// TodoFilter narrows the todos a listing returns and orders them. The zero
+// value matches them all, oldest first.
+type TodoFilter struct {
+	// A todo must carry every one of Tags, at least one of AnyTags and
+	// none of NotTags.
+	Tags, AnyTags, NotTags []string
+	// List, if set, keeps only todos on that list.
+	List string
+	// Completed, if set, keeps only todos done or not done.
+	Completed *bool
+	// Fields keeps only todos with these custom field values.
+	Fields map[string]interface{}
+	// Blocked, if set, keeps only todos blocked, or not blocked, by a todo
+	// not yet done.
+	Blocked *bool
+	// DueAfter and DueBefore, if set, keep only todos due at or after and
+	// before those times.
+	DueAfter, DueBefore *time.Time
+	// Sort is one of the keys of sortFields, optionally prefixed with '-'
+	// for descending order.
+	Sort string
+}
+
+// sortFields maps the sort keys of the API to document fields.
+var sortFields = map[string]string{
+	"created_at": "createAt",
+	"updated_at": "updated_at",
+	"due_at":     "due_at",
+	"title":      "title",
+}
+
+// ValidSort reports whether sort is acceptable as TodoFilter.Sort.
+func ValidSort(sort string) bool {
+	if sort == "" {
+		return true
+	}
+	if sort[0] == '-' {
+		sort = sort[1:]
+	}
+	_, ok := sortFields[sort]
+	return ok
+}
+
+// query is the MongoDB filter for f, before scoping to a tenant.
+func (f TodoFilter) query() bson.M {
+	q := bson.M{}
+	tags := bson.M{}
+	if len(f.Tags) > 0 {
+		tags["$all"] = f.Tags
+	}
+	if len(f.AnyTags) > 0 {
+		tags["$in"] = f.AnyTags
+	}
+	if len(f.NotTags) > 0 {
+		tags["$nin"] = f.NotTags
+	}
+	if len(tags) > 0 {
+		q["tags"] = tags
+	}
+	if f.List != "" {
+		q["list"] = f.List
+	}
+	for name, v := range f.Fields {
+		q["fields."+name] = v
+	}
+	if f.Completed != nil {
+		q["completed"] = *f.Completed
+	}
+	due := bson.M{}
+	if f.DueAfter != nil {
+		due["$gte"] = *f.DueAfter
+	}
+	if f.DueBefore != nil {
+		due["$lt"] = *f.DueBefore
+	}
+	if len(due) > 0 {
+		q["due_at"] = due
+	}
+	return q
+}
+
+// order is the MongoDB sort for f. Ties fall back to the id, so that pages
+// don't overlap.
+func (f TodoFilter) order() bson.D {
+	key, dir := f.Sort, 1
+	if len(key) > 0 && key[0] == '-' {
+		key, dir = key[1:], -1
+	}
+	field, ok := sortFields[key]
+	if !ok {
+		return bson.D{{Key: "_id", Value: 1}}
+	}
+	return bson.D{{Key: field, Value: dir}, {Key: "_id", Value: 1}}
+}
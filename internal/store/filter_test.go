@@ -0,0 +1,37 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestTodoFilterQuery(t *testing.T) {
+	open := false
+	tests := []struct {
+		f    TodoFilter
+		want bson.M
+	}{
+		{TodoFilter{}, bson.M{}},
+		{TodoFilter{Tags: []string{"work", "urgent"}}, bson.M{"tags": bson.M{"$all": []string{"work", "urgent"}}}},
+		{TodoFilter{AnyTags: []string{"home", "garden"}}, bson.M{"tags": bson.M{"$in": []string{"home", "garden"}}}},
+		{TodoFilter{NotTags: []string{"someday"}}, bson.M{"tags": bson.M{"$nin": []string{"someday"}}}},
+		// All three criteria apply to the one tags field together.
+		{
+			TodoFilter{Tags: []string{"work"}, AnyTags: []string{"q3", "q4"}, NotTags: []string{"done"}},
+			bson.M{"tags": bson.M{"$all": []string{"work"}, "$in": []string{"q3", "q4"}, "$nin": []string{"done"}}},
+		},
+		// Empty lists leave tags out.
+		{TodoFilter{Tags: []string{}, AnyTags: []string{}, NotTags: []string{}}, bson.M{}},
+		{
+			TodoFilter{Tags: []string{"work"}, List: "inbox", Completed: &open},
+			bson.M{"tags": bson.M{"$all": []string{"work"}}, "list": "inbox", "completed": false},
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.f.query(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v.query() = %v, want %v", tt.f, got, tt.want)
+		}
+	}
+}
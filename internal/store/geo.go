@@ -0,0 +1,36 @@
+package store
+
+import (
+	"context"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// nearLimit caps the todos OpenTodosNear returns.
+const nearLimit = 200
+
+// OpenTodosNear returns the todos not yet done whose geofence centre is
+// within maxMeters of the point, nearest first.
+func (s *Store) OpenTodosNear(ctx context.Context, p model.GeoPoint, maxMeters int) ([]model.Todo, error) {
+	out := []model.Todo{}
+	filter := bson.M{
+		"completed": false,
+		"location": bson.M{"$nearSphere": bson.M{
+			"$geometry":    p,
+			"$maxDistance": maxMeters,
+		}},
+	}
+	err := s.retry(ctx, func() error {
+		cur, err := s.reads(collectionName).Find(ctx, scope(ctx, filter), options.Find().SetLimit(nearLimit))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
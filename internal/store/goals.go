@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const goalCollection = "goals"
+
+func (s *Store) goals() *mongo.Collection {
+	return s.db.Collection(goalCollection)
+}
+
+// ListGoals returns the goals, oldest first.
+func (s *Store) ListGoals(ctx context.Context) ([]model.Goal, error) {
+	out := []model.Goal{}
+	err := s.retry(ctx, func() error {
+		cur, err := s.goals().Find(ctx, scope(ctx, bson.M{}), options.Find().SetSort(bson.M{"_id": 1}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *Store) GetGoal(ctx context.Context, id bson.ObjectID) (*model.Goal, error) {
+	var g model.Goal
+	err := s.retry(ctx, func() error {
+		return s.goals().FindOne(ctx, scope(ctx, bson.M{"_id": id})).Decode(&g)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// CreateGoal inserts g, giving it a new id.
+func (s *Store) CreateGoal(ctx context.Context, g *model.Goal) error {
+	g.ID = bson.NewObjectID()
+	g.TenantID = tenant.FromContext(ctx)
+	return s.retry(ctx, insertOnce(func() error {
+		_, err := s.goals().InsertOne(ctx, g)
+		return err
+	}))
+}
+
+// UpdateGoal saves the editable fields of g.
+func (s *Store) UpdateGoal(ctx context.Context, g *model.Goal) error {
+	update := bson.M{"$set": bson.M{
+		"name":        g.Name,
+		"description": g.Description,
+		"due_at":      g.DueAt,
+		"updated_at":  g.UpdatedAt,
+	}}
+	return s.retry(ctx, func() error {
+		return matched(s.goals().UpdateOne(ctx, scope(ctx, bson.M{"_id": g.ID}), update))
+	})
+}
+
+// DeleteGoal deletes the goal and detaches its todos.
+func (s *Store) DeleteGoal(ctx context.Context, id bson.ObjectID) error {
+	err := s.retry(ctx, deleteOnce(func() (*mongo.DeleteResult, error) {
+		return s.goals().DeleteOne(ctx, scope(ctx, bson.M{"_id": id}))
+	}))
+	if err != nil {
+		return err
+	}
+	return s.retry(ctx, func() error {
+		_, err := s.todos().UpdateMany(ctx, scope(ctx, bson.M{"goal_id": id}),
+			bson.M{"$unset": bson.M{"goal_id": ""}})
+		return err
+	})
+}
+
+// AttachTodo attaches the todo to the goal, detaching it from any other.
+func (s *Store) AttachTodo(ctx context.Context, goal, todo bson.ObjectID) error {
+	return s.retry(ctx, func() error {
+		return matched(s.todos().UpdateOne(ctx, scope(ctx, bson.M{"_id": todo}),
+			bson.M{"$set": bson.M{"goal_id": goal}}))
+	})
+}
+
+// DetachTodo detaches the todo from the goal. It returns ErrNotFound if
+// the todo isn't attached to it.
+func (s *Store) DetachTodo(ctx context.Context, goal, todo bson.ObjectID) error {
+	return s.retry(ctx, func() error {
+		return matched(s.todos().UpdateOne(ctx, scope(ctx, bson.M{"_id": todo, "goal_id": goal}),
+			bson.M{"$unset": bson.M{"goal_id": ""}}))
+	})
+}
+
+// GoalCount is how many todos a goal has, and how many of them are done.
+type GoalCount struct {
+	Goal  bson.ObjectID `bson:"_id"`
+	Total int           `bson:"total"`
+	Done  int           `bson:"done"`
+}
+
+// GoalCounts counts the todos of every goal that has any.
+func (s *Store) GoalCounts(ctx context.Context) ([]GoalCount, error) {
+	pipeline := bson.A{
+		bson.M{"$match": scope(ctx, bson.M{"goal_id": bson.M{"$exists": true}})},
+		bson.M{"$group": bson.M{
+			"_id":   "$goal_id",
+			"total": bson.M{"$sum": 1},
+			"done":  bson.M{"$sum": bson.M{"$cond": bson.A{"$completed", 1, 0}}},
+		}},
+	}
+	out := []GoalCount{}
+	err := s.retry(ctx, func() error {
+		cur, err := s.reads(collectionName).Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// indexes lists, by collection, the indexes the queries in this package
+// rely on.
+var indexes = map[string][]mongo.IndexModel{
+	collectionName: {
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "tags", Value: 1}}},
+		{Keys: bson.D{{Key: "createAt", Value: 1}}},
+		{Keys: bson.D{{Key: "updated_at", Value: 1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "completed", Value: 1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "completed_at", Value: 1}}},
+		{Keys: bson.D{{Key: "due_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "title", Value: "text"}}},
+		{Keys: bson.D{{Key: "trigrams", Value: 1}}},
+		{Keys: bson.D{{Key: "google_event_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+		// One wildcard index covers the lookup by any integration's ID.
+		{Keys: bson.D{{Key: "external.$**", Value: 1}}},
+		{Keys: bson.D{{Key: "fields.$**", Value: 1}}},
+		{Keys: bson.D{{Key: "blocked_by", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "location", Value: "2dsphere"}}},
+		{Keys: bson.D{{Key: "links.todo_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "goal_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+		// Slugs are unique within a tenant; most todos have none.
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "slug", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"slug": bson.M{"$exists": true}}),
+		},
+	},
+	archiveCollection: {
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "updated_at", Value: -1}}},
+	},
+	smartListCollection: {
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
+	},
+	goalCollection: {
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
+	},
+	timeCollection: {
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "start", Value: 1}}},
+		// At most one running timer per todo.
+		{
+			Keys: bson.D{{Key: "todo_id", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"running": true}),
+		},
+	},
+	pomodoroCollection: {
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "start", Value: 1}}},
+		{
+			Keys: bson.D{{Key: "todo_id", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"running": true}),
+		},
+	},
+	fieldCollection: {
+		{
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "list", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	},
+	shareCollection: {
+		{Keys: bson.D{{Key: "token", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	},
+	activityCollection: {
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(activityDays * 24 * 60 * 60)},
+	},
+	tombstoneCollection: {
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "deleted_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(TombstoneTTL / time.Second))},
+	},
+	integrationCollection: {
+		{Keys: bson.D{{Key: "channel", Value: 1}, {Key: "address", Value: 1}}},
+	},
+	// One settings document per tenant.
+	settingsCollection: {
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	},
+}
+
+// EnsureIndexes creates any missing index from indexes. Existing ones are
+// left alone, so it is cheap to run on every start.
+func (s *Store) EnsureIndexes(ctx context.Context) error {
+	for coll, models := range indexes {
+		if _, err := s.db.Collection(coll).Indexes().CreateMany(ctx, models); err != nil {
+			return fmt.Errorf("indexes on %s: %w", coll, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const integrationCollection = "integrations"
+
+func (s *Store) integrations() *mongo.Collection {
+	return s.db.Collection(integrationCollection)
+}
+
+// ListIntegrations returns the configured integrations, oldest first.
+func (s *Store) ListIntegrations(ctx context.Context) ([]model.Integration, error) {
+	out := []model.Integration{}
+	err := s.retry(ctx, func() error {
+		cur, err := s.integrations().Find(ctx, scope(ctx, bson.M{}), options.Find().SetSort(bson.M{"_id": 1}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *Store) GetIntegration(ctx context.Context, id bson.ObjectID) (*model.Integration, error) {
+	var in model.Integration
+	err := s.retry(ctx, func() error {
+		return s.integrations().FindOne(ctx, scope(ctx, bson.M{"_id": id})).Decode(&in)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &in, nil
+}
+
+// CreateIntegration inserts in, giving it a new id.
+func (s *Store) CreateIntegration(ctx context.Context, in *model.Integration) error {
+	in.ID = bson.NewObjectID()
+	in.TenantID = tenant.FromContext(ctx)
+	return s.retry(ctx, insertOnce(func() error {
+		_, err := s.integrations().InsertOne(ctx, in)
+		return err
+	}))
+}
+
+// UpdateIntegration saves the editable fields of in.
+func (s *Store) UpdateIntegration(ctx context.Context, in *model.Integration) error {
+	update := bson.M{"$set": bson.M{
+		"name":       in.Name,
+		"channel":    in.Channel,
+		"address":    in.Address,
+		"events":     in.Events,
+		"list":       in.List,
+		"template":   in.Template,
+		"enabled":    in.Enabled,
+		"updated_at": in.UpdatedAt,
+	}}
+	return s.retry(ctx, func() error {
+		return matched(s.integrations().UpdateOne(ctx, scope(ctx, bson.M{"_id": in.ID}), update))
+	})
+}
+
+func (s *Store) DeleteIntegration(ctx context.Context, id bson.ObjectID) error {
+	return s.retry(ctx, deleteOnce(func() (*mongo.DeleteResult, error) {
+		return s.integrations().DeleteOne(ctx, scope(ctx, bson.M{"_id": id}))
+	}))
+}
+
+// TenantsWanting returns, across every tenant, those with an enabled
+// integration naming the event type.
+func (s *Store) TenantsWanting(ctx context.Context, eventType string) ([]string, error) {
+	return s.integrationTenants(ctx, bson.M{"enabled": true, "events": eventType})
+}
+
+// TenantsSubscribed returns, across every tenant, those with an
+// integration for channel and address, such as a phone number's SMS.
+func (s *Store) TenantsSubscribed(ctx context.Context, channel, address string) ([]string, error) {
+	return s.integrationTenants(ctx, bson.M{"channel": channel, "address": address})
+}
+
+// integrationTenants returns the tenants with integrations matching filter,
+// each once.
+func (s *Store) integrationTenants(ctx context.Context, filter bson.M) ([]string, error) {
+	var docs []struct {
+		TenantID string `bson:"tenant_id"`
+	}
+	err := s.retry(ctx, func() error {
+		cur, err := s.integrations().Find(ctx, filter,
+			options.Find().SetProjection(bson.M{"tenant_id": 1}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &docs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	out := []string{}
+	for _, d := range docs {
+		if !seen[d.TenantID] {
+			seen[d.TenantID] = true
+			out = append(out, d.TenantID)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// AddLink links the todo id to another; linking twice is not an error.
+func (s *Store) AddLink(ctx context.Context, id bson.ObjectID, l model.Link) error {
+	return s.retry(ctx, func() error {
+		return matched(s.todos().UpdateOne(ctx, scope(ctx, bson.M{"_id": id}),
+			bson.M{"$addToSet": bson.M{"links": l}}))
+	})
+}
+
+// RemoveLink undoes AddLink.
+func (s *Store) RemoveLink(ctx context.Context, id bson.ObjectID, l model.Link) error {
+	return s.retry(ctx, func() error {
+		return matched(s.todos().UpdateOne(ctx, scope(ctx, bson.M{"_id": id}),
+			bson.M{"$pull": bson.M{"links": bson.M{"type": l.Type, "todo_id": l.TodoID}}}))
+	})
+}
+
+// LinksTo returns the todos holding a link to the todo id, with only
+// their links.
+func (s *Store) LinksTo(ctx context.Context, id bson.ObjectID) ([]model.Todo, error) {
+	out := []model.Todo{}
+	err := s.retry(ctx, func() error {
+		cur, err := s.reads(collectionName).Find(ctx, scope(ctx, bson.M{"links.todo_id": id}),
+			options.Find().SetProjection(bson.M{"links": 1}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UnlinkTodo removes the links to a deleted todo from the others.
+func (s *Store) UnlinkTodo(ctx context.Context, id bson.ObjectID) error {
+	return s.retry(ctx, func() error {
+		_, err := s.todos().UpdateMany(ctx, scope(ctx, bson.M{"links.todo_id": id}),
+			bson.M{"$pull": bson.M{"links": bson.M{"todo_id": id}}})
+		return err
+	})
+}
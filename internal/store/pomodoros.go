@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const pomodoroCollection = "pomodoros"
+
+func (s *Store) pomodoros() *mongo.Collection {
+	return s.db.Collection(pomodoroCollection)
+}
+
+// StartPomodoro inserts p as the running session of its todo, giving it a
+// new id. It returns ErrConflict if the todo already has one.
+func (s *Store) StartPomodoro(ctx context.Context, p *model.Pomodoro) error {
+	p.ID = bson.NewObjectID()
+	p.TenantID = tenant.FromContext(ctx)
+	p.State, p.Running = model.PomodoroRunning, true
+	return s.retry(ctx, insertOnce(func() error {
+		_, err := s.pomodoros().InsertOne(ctx, p)
+		return err
+	}))
+}
+
+// EndPomodoro moves the todo's running session id to state at end and
+// returns it. It returns ErrNotFound if that session isn't running. It is
+// not retried: a repeat would find the session already ended.
+func (s *Store) EndPomodoro(ctx context.Context, todoID, id bson.ObjectID, state string, end time.Time) (*model.Pomodoro, error) {
+	var p model.Pomodoro
+	err := s.pomodoros().FindOneAndUpdate(ctx,
+		scope(ctx, bson.M{"_id": id, "todo_id": todoID, "running": true}),
+		bson.M{"$set": bson.M{"state": state, "end": end}, "$unset": bson.M{"running": ""}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&p)
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &p, nil
+}
+
+// ListPomodoros returns the sessions started in [from, to), oldest first.
+func (s *Store) ListPomodoros(ctx context.Context, from, to time.Time) ([]model.Pomodoro, error) {
+	out := []model.Pomodoro{}
+	filter := bson.M{"start": bson.M{"$gte": from, "$lt": to}}
+	err := s.retry(ctx, func() error {
+		cur, err := s.reads(pomodoroCollection).Find(ctx, scope(ctx, filter),
+			options.Find().SetSort(bson.D{{Key: "start", Value: 1}}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
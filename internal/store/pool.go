@@ -0,0 +1,62 @@
+package store
+
+import (
+	"sync/atomic"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+// PoolStats describes the driver's connection pools, summed over every
+// server the client talks to.
+type PoolStats struct {
+	// Open and InUse are current counts; the rest count since startup.
+	Open             int64 `json:"open"`
+	InUse            int64 `json:"in_use"`
+	Created          int64 `json:"created"`
+	Closed           int64 `json:"closed"`
+	CheckedOut       int64 `json:"checked_out"`
+	CheckoutFailures int64 `json:"checkout_failures"`
+	// Cleared counts pool resets after a server error.
+	Cleared int64 `json:"cleared"`
+}
+
+// poolMonitor keeps PoolStats up to date from the driver's pool events.
+type poolMonitor struct {
+	open, inUse, created, closed, checkedOut, failures, cleared int64
+}
+
+func (m *poolMonitor) monitor() *event.PoolMonitor {
+	return &event.PoolMonitor{Event: func(e *event.PoolEvent) {
+		switch e.Type {
+		case event.ConnectionCreated:
+			atomic.AddInt64(&m.created, 1)
+			atomic.AddInt64(&m.open, 1)
+		case event.ConnectionClosed:
+			atomic.AddInt64(&m.closed, 1)
+			atomic.AddInt64(&m.open, -1)
+		case event.ConnectionCheckedOut:
+			atomic.AddInt64(&m.checkedOut, 1)
+			atomic.AddInt64(&m.inUse, 1)
+		case event.ConnectionCheckedIn:
+			atomic.AddInt64(&m.inUse, -1)
+		case event.ConnectionCheckOutFailed:
+			atomic.AddInt64(&m.failures, 1)
+		case event.ConnectionPoolCleared:
+			atomic.AddInt64(&m.cleared, 1)
+		}
+	}}
+}
+
+// PoolStats returns the current connection pool figures.
+func (s *Store) PoolStats() PoolStats {
+	m := s.pool
+	return PoolStats{
+		Open:             atomic.LoadInt64(&m.open),
+		InUse:            atomic.LoadInt64(&m.inUse),
+		Created:          atomic.LoadInt64(&m.created),
+		Closed:           atomic.LoadInt64(&m.closed),
+		CheckedOut:       atomic.LoadInt64(&m.checkedOut),
+		CheckoutFailures: atomic.LoadInt64(&m.failures),
+		Cleared:          atomic.LoadInt64(&m.cleared),
+	}
+}
@@ -0,0 +1,45 @@
+package store
+
+import (
+	"context"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const pushCollection = "push_subscriptions"
+
+// SavePushSubscription stores sub, replacing an earlier subscription with
+// the same endpoint.
+func (s *Store) SavePushSubscription(ctx context.Context, sub *model.PushSubscription) error {
+	sub.TenantID = tenant.FromContext(ctx)
+	return s.retry(ctx, func() error {
+		_, err := s.db.Collection(pushCollection).ReplaceOne(ctx,
+			scope(ctx, bson.M{"_id": sub.Endpoint}), sub, options.Replace().SetUpsert(true))
+		return err
+	})
+}
+
+func (s *Store) ListPushSubscriptions(ctx context.Context) ([]model.PushSubscription, error) {
+	subs := []model.PushSubscription{}
+	err := s.retry(ctx, func() error {
+		cur, err := s.db.Collection(pushCollection).Find(ctx, scope(ctx, bson.M{}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &subs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (s *Store) DeletePushSubscription(ctx context.Context, endpoint string) error {
+	return s.retry(ctx, deleteOnce(func() (*mongo.DeleteResult, error) {
+		return s.db.Collection(pushCollection).DeleteOne(ctx, scope(ctx, bson.M{"_id": endpoint}))
+	}))
+}
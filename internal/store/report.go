@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// WeekCounts is what happened to the todos in one week of a report.
+type WeekCounts struct {
+	// Week is the week's index from the start of the span.
+	Week      int `bson:"_id"`
+	Created   int `bson:"created"`
+	Completed int `bson:"completed"`
+	// Due counts the todos due in the week and DueDone those of them
+	// completed by its end.
+	Due     int `bson:"due"`
+	DueDone int `bson:"due_done"`
+}
+
+// TagCount is how many todos carrying a tag were completed.
+type TagCount struct {
+	Tag       string `bson:"_id"`
+	Completed int    `bson:"n"`
+}
+
+// busiestTags is how many tags a report ranks.
+const busiestTags = 5
+
+// WeeklyCounts counts, for each of weeks weeks from from, the todos
+// created, completed and due, and ranks the tags of the todos completed
+// over the whole span. It returns one entry per week, in order.
+func (s *Store) WeeklyCounts(ctx context.Context, from time.Time, weeks int) ([]WeekCounts, []TagCount, error) {
+	const week = int64(7 * 24 * time.Hour / time.Millisecond)
+	to := from.AddDate(0, 0, 7*weeks)
+	in := func(field string) bson.M {
+		return bson.M{field: bson.M{"$gte": from, "$lt": to}}
+	}
+	index := func(field string) bson.M {
+		return bson.M{"$toInt": bson.M{"$floor": bson.M{
+			"$divide": bson.A{bson.M{"$subtract": bson.A{"$" + field, from}}, week},
+		}}}
+	}
+	count := func(field, as string) bson.A {
+		return bson.A{
+			bson.M{"$match": in(field)},
+			bson.M{"$group": bson.M{"_id": index(field), as: bson.M{"$sum": 1}}},
+		}
+	}
+	// A todo due in a week was done in time if completed before the week
+	// ended; those completed before completion times were kept count too.
+	weekEnd := bson.M{"$add": bson.A{from, bson.M{"$multiply": bson.A{bson.M{"$add": bson.A{"$week", 1}}, week}}}}
+	doneInTime := bson.M{"$or": bson.A{
+		bson.M{"$and": bson.A{"$completed", bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$completed_at", nil}}, nil}}}},
+		bson.M{"$and": bson.A{
+			bson.M{"$gt": bson.A{"$completed_at", nil}},
+			bson.M{"$lt": bson.A{"$completed_at", weekEnd}},
+		}},
+	}}
+	pipeline := bson.A{
+		bson.M{"$match": scope(ctx, bson.M{"$or": bson.A{in("createAt"), in("completed_at"), in("due_at")}})},
+		bson.M{"$facet": bson.M{
+			"created":   count("createAt", "created"),
+			"completed": count("completed_at", "completed"),
+			"due": bson.A{
+				bson.M{"$match": in("due_at")},
+				bson.M{"$set": bson.M{"week": index("due_at")}},
+				bson.M{"$group": bson.M{
+					"_id":      "$week",
+					"due":      bson.M{"$sum": 1},
+					"due_done": bson.M{"$sum": bson.M{"$cond": bson.A{doneInTime, 1, 0}}},
+				}},
+			},
+			"tags": bson.A{
+				bson.M{"$match": in("completed_at")},
+				bson.M{"$unwind": "$tags"},
+				bson.M{"$group": bson.M{"_id": "$tags", "n": bson.M{"$sum": 1}}},
+				bson.M{"$sort": bson.D{{Key: "n", Value: -1}, {Key: "_id", Value: 1}}},
+				bson.M{"$limit": busiestTags},
+			},
+		}},
+	}
+	var facets []struct {
+		Created, Completed, Due []WeekCounts
+		Tags                    []TagCount
+	}
+	err := s.retry(ctx, func() error {
+		cur, err := s.reads(collectionName).Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &facets)
+	})
+	if err != nil || len(facets) == 0 {
+		return nil, nil, err
+	}
+	f := facets[0]
+	counts := make([]WeekCounts, weeks)
+	for i := range counts {
+		counts[i].Week = i
+	}
+	for _, c := range f.Created {
+		counts[c.Week].Created = c.Created
+	}
+	for _, c := range f.Completed {
+		counts[c.Week].Completed = c.Completed
+	}
+	for _, c := range f.Due {
+		counts[c.Week].Due, counts[c.Week].DueDone = c.Due, c.DueDone
+	}
+	return counts, f.Tags, nil
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Server error codes seen while a replica set elects a new primary or a
+// node shuts down; the operation is worth trying again.
+var transientCodes = []int{
+	6,     // HostUnreachable
+	7,     // HostNotFound
+	89,    // NetworkTimeout
+	91,    // ShutdownInProgress
+	189,   // PrimarySteppedDown
+	9001,  // SocketException
+	10107, // NotWritablePrimary
+	11600, // InterruptedAtShutdown
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+func transient(err error) bool {
+	if mongo.IsNetworkError(err) {
+		return true
+	}
+	var se mongo.ServerError
+	if !errors.As(err, &se) {
+		return false
+	}
+	if se.HasErrorLabel("RetryableWriteError") || se.HasErrorLabel("TransientTransactionError") {
+		return true
+	}
+	for _, code := range transientCodes {
+		if se.HasErrorCode(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// retrier retries operations that failed transiently, waiting a jittered,
+// doubling backoff in between. Retries draw on a shared budget that
+// successes slowly refill, so a database that stays down isn't hammered
+// with attempts times the normal load.
+type retrier struct {
+	attempts int
+	backoff  time.Duration
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+const (
+	retryBudget = 10  // retries that can happen back to back
+	retryRefill = 0.1 // budget regained per successful operation
+)
+
+func newRetrier(attempts int, backoff time.Duration) *retrier {
+	return &retrier{attempts: attempts, backoff: backoff, tokens: retryBudget}
+}
+
+func (r *retrier) succeeded() {
+	r.mu.Lock()
+	if r.tokens += retryRefill; r.tokens > retryBudget {
+		r.tokens = retryBudget
+	}
+	r.mu.Unlock()
+}
+
+func (r *retrier) spend() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// retry runs fn until it succeeds, fails for good, runs out of attempts or
+// budget, or ctx ends, and returns fn's last error translated. fn must be
+// safe to repeat.
+func (s *Store) retry(ctx context.Context, fn func() error) error {
+	r := s.retrier
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			r.succeeded()
+			return nil
+		}
+		if !transient(err) || attempt >= r.attempts || !r.spend() {
+			return translate(err)
+		}
+		wait := time.Duration(rand.Int63n(int64(r.backoff<<attempt) + 1))
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return translate(err)
+		}
+	}
+}
+
+// insertOnce adapts an insert for retry: a duplicate key on a later attempt
+// means an earlier one went through after all.
+func insertOnce(insert func() error) func() error {
+	tries := 0
+	return func() error {
+		tries++
+		err := insert()
+		if tries > 1 && mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return err
+	}
+}
+
+// deleteOnce adapts a single-document delete for retry, reporting
+// ErrNotFound when nothing matched, unless an earlier attempt may have
+// deleted it.
+func deleteOnce(del func() (*mongo.DeleteResult, error)) func() error {
+	tries := 0
+	return func() error {
+		tries++
+		res, err := del()
+		if err != nil {
+			return err
+		}
+		if res.DeletedCount == 0 && tries == 1 {
+			return ErrNotFound
+		}
+		return nil
+	}
+}
+
+// matched turns an update that matched nothing into ErrNotFound.
+func matched(res *mongo.UpdateResult, err error) error {
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"sort"
+
+	"dhruvarora9/personal-todo-golang/internal/fuzzy"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// searchCandidates caps how many todos sharing a trigram with the query
+// SearchTodos scores.
+const searchCandidates = 1000
+
+// SearchTodos returns up to limit todos whose title matches q despite
+// typos, best match first. The trigram index narrows the todos to those
+// sharing part of a word with q; they are then scored with fuzzy.Score.
+func (s *Store) SearchTodos(ctx context.Context, q string, limit int) ([]model.Todo, error) {
+	grams := fuzzy.Trigrams(q)
+	if len(grams) == 0 {
+		return []model.Todo{}, nil
+	}
+	var candidates []model.Todo
+	err := s.retry(ctx, func() error {
+		cur, err := s.reads(collectionName).Find(ctx,
+			scope(ctx, bson.M{"trigrams": bson.M{"$in": grams}}),
+			options.Find().SetLimit(searchCandidates))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &candidates)
+	})
+	if err != nil {
+		return nil, err
+	}
+	type scored struct {
+		todo  model.Todo
+		score float64
+	}
+	var hits []scored
+	for _, t := range candidates {
+		if sc := fuzzy.Score(q, t.Title); sc >= fuzzy.Threshold {
+			hits = append(hits, scored{t, sc})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+	out := make([]model.Todo, 0, limit)
+	for _, h := range hits {
+		if len(out) == limit {
+			break
+		}
+		out = append(out, h.todo)
+	}
+	return out, nil
+}
+
+// BackfillTrigrams indexes, for every tenant, the todos saved before
+// search existed and returns how many it updated.
+func (s *Store) BackfillTrigrams(ctx context.Context) (int, error) {
+	var todos []model.Todo
+	err := s.retry(ctx, func() error {
+		cur, err := s.todos().Find(ctx, bson.M{"trigrams": bson.M{"$exists": false}},
+			options.Find().SetProjection(bson.M{"title": 1}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &todos)
+	})
+	if err != nil || len(todos) == 0 {
+		return 0, err
+	}
+	writes := make([]mongo.WriteModel, 0, len(todos))
+	for _, t := range todos {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": t.ID, "title": t.Title}).
+			SetUpdate(bson.M{"$set": bson.M{"trigrams": fuzzy.Trigrams(t.Title)}}))
+	}
+	var n int64
+	err = s.retry(ctx, func() error {
+		res, err := s.todos().BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+		if res != nil {
+			n = res.ModifiedCount
+		}
+		return err
+	})
+	return int(n), err
+}
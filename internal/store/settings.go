@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const settingsCollection = "settings"
+
+// GetSettings returns the tenant's settings, the zero value if it never
+// saved any.
+func (s *Store) GetSettings(ctx context.Context) (*model.Settings, error) {
+	var st model.Settings
+	err := s.retry(ctx, func() error {
+		err := s.db.Collection(settingsCollection).FindOne(ctx, scope(ctx, bson.M{})).Decode(&st)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// SaveSettings replaces the tenant's settings with st.
+func (s *Store) SaveSettings(ctx context.Context, st *model.Settings) error {
+	st.TenantID = tenant.FromContext(ctx)
+	return s.retry(ctx, func() error {
+		_, err := s.db.Collection(settingsCollection).ReplaceOne(ctx,
+			scope(ctx, bson.M{}), st, options.Replace().SetUpsert(true))
+		return err
+	})
+}
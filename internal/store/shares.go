@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const shareCollection = "shares"
+
+func (s *Store) shares() *mongo.Collection {
+	return s.db.Collection(shareCollection)
+}
+
+// ListShares returns the tenant's shares, oldest first.
+func (s *Store) ListShares(ctx context.Context) ([]model.Share, error) {
+	out := []model.Share{}
+	err := s.retry(ctx, func() error {
+		cur, err := s.shares().Find(ctx, scope(ctx, bson.M{}), options.Find().SetSort(bson.M{"_id": 1}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateShare inserts sh, giving it a new id.
+func (s *Store) CreateShare(ctx context.Context, sh *model.Share) error {
+	sh.ID = bson.NewObjectID()
+	sh.TenantID = tenant.FromContext(ctx)
+	return s.retry(ctx, insertOnce(func() error {
+		_, err := s.shares().InsertOne(ctx, sh)
+		return err
+	}))
+}
+
+func (s *Store) DeleteShare(ctx context.Context, id bson.ObjectID) error {
+	return s.retry(ctx, deleteOnce(func() (*mongo.DeleteResult, error) {
+		return s.shares().DeleteOne(ctx, scope(ctx, bson.M{"_id": id}))
+	}))
+}
+
+// ShareByToken finds the share with token, of whichever tenant, unless it
+// expired by now.
+func (s *Store) ShareByToken(ctx context.Context, token string, now time.Time) (*model.Share, error) {
+	var sh model.Share
+	filter := bson.M{
+		"token": token,
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$exists": false}},
+			bson.M{"expires_at": bson.M{"$gt": now}},
+		},
+	}
+	err := s.retry(ctx, func() error {
+		return s.shares().FindOne(ctx, filter).Decode(&sh)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sh, nil
+}
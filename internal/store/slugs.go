@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// SetSlug gives the todo id the short slug, unless it already has one. A
+// slug another todo of the tenant holds is ErrConflict; a todo that has a
+// slug is left alone and reported as ErrNotFound, so callers re-read it.
+func (s *Store) SetSlug(ctx context.Context, id bson.ObjectID, slug string) error {
+	return s.retry(ctx, func() error {
+		return matched(s.todos().UpdateOne(ctx,
+			scope(ctx, bson.M{"_id": id, "slug": bson.M{"$exists": false}}),
+			bson.M{"$set": bson.M{"slug": slug}}))
+	})
+}
+
+// TodoBySlug finds the todo with the short slug.
+func (s *Store) TodoBySlug(ctx context.Context, slug string) (*model.Todo, error) {
+	var t model.Todo
+	err := s.retry(ctx, func() error {
+		return s.todos().FindOne(ctx, scope(ctx, bson.M{"slug": slug})).Decode(&t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const smartListCollection = "smart_lists"
+
+func (s *Store) smartLists() *mongo.Collection {
+	return s.db.Collection(smartListCollection)
+}
+
+// ListSmartLists returns the saved smart lists, oldest first.
+func (s *Store) ListSmartLists(ctx context.Context) ([]model.SmartList, error) {
+	out := []model.SmartList{}
+	err := s.retry(ctx, func() error {
+		cur, err := s.smartLists().Find(ctx, scope(ctx, bson.M{}), options.Find().SetSort(bson.M{"_id": 1}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *Store) GetSmartList(ctx context.Context, id bson.ObjectID) (*model.SmartList, error) {
+	var l model.SmartList
+	err := s.retry(ctx, func() error {
+		return s.smartLists().FindOne(ctx, scope(ctx, bson.M{"_id": id})).Decode(&l)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// CreateSmartList inserts l, giving it a new id.
+func (s *Store) CreateSmartList(ctx context.Context, l *model.SmartList) error {
+	l.ID = bson.NewObjectID()
+	l.TenantID = tenant.FromContext(ctx)
+	return s.retry(ctx, insertOnce(func() error {
+		_, err := s.smartLists().InsertOne(ctx, l)
+		return err
+	}))
+}
+
+// UpdateSmartList saves the editable fields of l.
+func (s *Store) UpdateSmartList(ctx context.Context, l *model.SmartList) error {
+	update := bson.M{"$set": bson.M{
+		"name":            l.Name,
+		"tags":            l.Tags,
+		"any_tags":        l.AnyTags,
+		"not_tags":        l.NotTags,
+		"list":            l.List,
+		"completed":       l.Completed,
+		"due_within_days": l.DueWithinDays,
+		"sort":            l.Sort,
+		"updated_at":      l.UpdatedAt,
+	}}
+	return s.retry(ctx, func() error {
+		return matched(s.smartLists().UpdateOne(ctx, scope(ctx, bson.M{"_id": l.ID}), update))
+	})
+}
+
+func (s *Store) DeleteSmartList(ctx context.Context, id bson.ObjectID) error {
+	return s.retry(ctx, deleteOnce(func() (*mongo.DeleteResult, error) {
+		return s.smartLists().DeleteOne(ctx, scope(ctx, bson.M{"_id": id}))
+	}))
+}
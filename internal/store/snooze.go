@@ -0,0 +1,27 @@
+package store
+
+import (
+	"context"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// maxSnoozes is how many snoozes a todo remembers.
+const maxSnoozes = 50
+
+// SnoozeTodo moves the todo's due date to sn.Until, records sn and leaves
+// the todo as saved in t. It isn't retried: a repeat would record the
+// snooze twice.
+func (s *Store) SnoozeTodo(ctx context.Context, t *model.Todo, sn model.Snooze) error {
+	update := bson.M{
+		"$set":  bson.M{"due_at": sn.Until, "updated_at": sn.At},
+		"$push": bson.M{"snoozes": bson.M{"$each": []model.Snooze{sn}, "$slice": -maxSnoozes}},
+		"$inc":  bumpVersion,
+	}
+	err := s.todos().FindOneAndUpdate(ctx, scope(ctx, bson.M{"_id": t.ID}), update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(t)
+	return translate(err)
+}
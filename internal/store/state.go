@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Built-in connectors keep their state (tokens, sync cursors) in one
+// document each, keyed by the connector's name.
+const integrationStateCollection = "integration_state"
+
+func (s *Store) integrationState() *mongo.Collection {
+	return s.db.Collection(integrationStateCollection)
+}
+
+// GetIntegrationState decodes the state saved for the named integration into v.
+// It returns ErrNotFound if nothing was saved.
+func (s *Store) GetIntegrationState(ctx context.Context, name string, v interface{}) error {
+	return s.retry(ctx, func() error {
+		return s.integrationState().FindOne(ctx, bson.M{"_id": name}).Decode(v)
+	})
+}
+
+func (s *Store) SaveIntegrationState(ctx context.Context, name string, v interface{}) error {
+	return s.retry(ctx, func() error {
+		_, err := s.integrationState().ReplaceOne(ctx, bson.M{"_id": name}, v, options.Replace().SetUpsert(true))
+		return err
+	})
+}
+
+func (s *Store) DeleteIntegrationState(ctx context.Context, name string) error {
+	return s.retry(ctx, func() error {
+		_, err := s.integrationState().DeleteOne(ctx, bson.M{"_id": name})
+		return err
+	})
+}
+
+// SetGoogleEventID links a todo to a Google Calendar event; an empty
+// eventID removes the link. It leaves updated_at alone, as the todo itself
+// didn't change.
+func (s *Store) SetGoogleEventID(ctx context.Context, id bson.ObjectID, eventID string) error {
+	update := bson.M{"$set": bson.M{"google_event_id": eventID}}
+	if eventID == "" {
+		update = bson.M{"$unset": bson.M{"google_event_id": ""}}
+	}
+	return s.retry(ctx, func() error {
+		return matched(s.todos().UpdateOne(ctx, scope(ctx, bson.M{"_id": id}), update))
+	})
+}
+
+func (s *Store) GetTodoByGoogleEvent(ctx context.Context, eventID string) (*model.Todo, error) {
+	var t model.Todo
+	err := s.retry(ctx, func() error {
+		return s.todos().FindOne(ctx, scope(ctx, bson.M{"google_event_id": eventID})).Decode(&t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SetExternalID records the todo's ID in the named integration.
+func (s *Store) SetExternalID(ctx context.Context, id bson.ObjectID, integration, externalID string) error {
+	update := bson.M{"$set": bson.M{"external." + integration: externalID}}
+	return s.retry(ctx, func() error {
+		return matched(s.todos().UpdateOne(ctx, scope(ctx, bson.M{"_id": id}), update))
+	})
+}
+
+func (s *Store) GetTodoByExternalID(ctx context.Context, integration, externalID string) (*model.Todo, error) {
+	var t model.Todo
+	err := s.retry(ctx, func() error {
+		return s.todos().FindOne(ctx, scope(ctx, bson.M{"external." + integration: externalID})).Decode(&t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
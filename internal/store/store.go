@@ -0,0 +1,264 @@
+// Package store keeps todos in MongoDB.
+package store
+
+import (
+	"context"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/fuzzy"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+const collectionName = "todo"
+
+// Store runs every operation under the caller's context, so request
+// deadlines and cancellation reach the database.
+type Store struct {
+	client   *mongo.Client
+	db       *mongo.Database
+	pool     *poolMonitor
+	retrier  *retrier
+	readPref *readpref.ReadPref
+	// batchSize caps the documents in one bulk write.
+	batchSize int
+}
+
+// Connect connects to the MongoDB deployment described by c. Pool and
+// timeout settings left at zero keep the driver's defaults.
+func Connect(ctx context.Context, c config.Database) (*Store, error) {
+	pool := &poolMonitor{}
+	opts := options.Client().ApplyURI(c.URI).SetPoolMonitor(pool.monitor())
+	if c.ReplicaSet != "" {
+		opts.SetReplicaSet(c.ReplicaSet)
+	}
+	if c.MaxPoolSize > 0 {
+		opts.SetMaxPoolSize(c.MaxPoolSize)
+	}
+	if c.MinPoolSize > 0 {
+		opts.SetMinPoolSize(c.MinPoolSize)
+	}
+	if c.MaxConnecting > 0 {
+		opts.SetMaxConnecting(c.MaxConnecting)
+	}
+	if c.MaxConnIdleTime > 0 {
+		opts.SetMaxConnIdleTime(c.MaxConnIdleTime)
+	}
+	if c.ConnectTimeout > 0 {
+		opts.SetConnectTimeout(c.ConnectTimeout)
+	}
+	if c.ServerSelectionTimeout > 0 {
+		opts.SetServerSelectionTimeout(c.ServerSelectionTimeout)
+	}
+	if c.OperationTimeout > 0 {
+		opts.SetTimeout(c.OperationTimeout)
+	}
+	rp, err := readPreference(c)
+	if err != nil {
+		return nil, err
+	}
+	client, err := mongo.Connect(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+	db := client.Database(c.Name)
+	return &Store{
+		client:    client,
+		db:        db,
+		pool:      pool,
+		retrier:   newRetrier(c.RetryAttempts, c.RetryBackoff),
+		readPref:  rp,
+		batchSize: c.WriteBatchSize,
+	}, nil
+}
+
+func readPreference(c config.Database) (*readpref.ReadPref, error) {
+	if c.ReadPreference == "" {
+		return readpref.Primary(), nil
+	}
+	mode, err := readpref.ModeFromString(c.ReadPreference)
+	if err != nil {
+		return nil, err
+	}
+	var opts []readpref.Option
+	if c.MaxStaleness > 0 {
+		opts = append(opts, readpref.WithMaxStaleness(c.MaxStaleness))
+	}
+	return readpref.New(mode, opts...)
+}
+
+func (s *Store) Close(ctx context.Context) error {
+	return s.client.Disconnect(ctx)
+}
+
+// scope restricts filter to the tenant ctx acts for. Documents of the
+// default tenant have no tenant_id, which a null match finds.
+func scope(ctx context.Context, filter bson.M) bson.M {
+	if id := tenant.FromContext(ctx); id != "" {
+		filter["tenant_id"] = id
+	} else {
+		filter["tenant_id"] = nil
+	}
+	return filter
+}
+
+func (s *Store) todos() *mongo.Collection {
+	return s.db.Collection(collectionName)
+}
+
+// reads returns the named collection under the configured read preference,
+// for list and search reads that a secondary may serve.
+func (s *Store) reads(name string) *mongo.Collection {
+	return s.db.Collection(name, options.Collection().SetReadPreference(s.readPref))
+}
+
+func (s *Store) ListTodos(ctx context.Context) ([]model.Todo, error) {
+	todos := []model.Todo{}
+	err := s.retry(ctx, func() error {
+		cur, err := s.reads(collectionName).Find(ctx, scope(ctx, bson.M{}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &todos)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// EachTodo calls fn with each todo matching f, in f's order, as it comes
+// off the cursor, so a large collection is never held in memory at once.
+// It skips the first skip todos and stops after limit, unless limit is 0,
+// or at fn's first error. Only opening the cursor is retried; fn may
+// already have seen todos when a later batch fails.
+func (s *Store) EachTodo(ctx context.Context, f TodoFilter, skip, limit int64, fn func(*model.Todo) error) error {
+	filter, err := s.filter(ctx, f)
+	if err != nil {
+		return err
+	}
+	opts := options.Find().SetSort(f.order()).SetSkip(skip).SetLimit(limit)
+	var cur *mongo.Cursor
+	err = s.retry(ctx, func() (err error) {
+		cur, err = s.reads(collectionName).Find(ctx, filter, opts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var t model.Todo
+		if err := cur.Decode(&t); err != nil {
+			return translate(err)
+		}
+		if err := fn(&t); err != nil {
+			return err
+		}
+	}
+	return translate(cur.Err())
+}
+
+// CountTodos returns how many todos match f.
+func (s *Store) CountTodos(ctx context.Context, f TodoFilter) (int64, error) {
+	filter, err := s.filter(ctx, f)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	err = s.retry(ctx, func() (err error) {
+		n, err = s.reads(collectionName).CountDocuments(ctx, filter)
+		return err
+	})
+	return n, err
+}
+
+func (s *Store) GetTodo(ctx context.Context, id bson.ObjectID) (*model.Todo, error) {
+	var t model.Todo
+	err := s.retry(ctx, func() error {
+		return s.todos().FindOne(ctx, scope(ctx, bson.M{"_id": id})).Decode(&t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateTodo inserts t, giving it a new id.
+func (s *Store) CreateTodo(ctx context.Context, t *model.Todo) error {
+	t.ID = bson.NewObjectID()
+	t.TenantID = tenant.FromContext(ctx)
+	t.Trigrams = fuzzy.Trigrams(t.Title)
+	t.Location = t.Geofence.Point()
+	return s.retry(ctx, insertOnce(func() error {
+		_, err := s.todos().InsertOne(ctx, t)
+		return err
+	}))
+}
+
+// editable is the $set of the fields a todo update may change.
+func editable(t *model.Todo) bson.M {
+	return bson.M{
+		"title":            t.Title,
+		"trigrams":         fuzzy.Trigrams(t.Title),
+		"completed":        t.Completed,
+		"completed_at":     t.CompletedAt,
+		"status":           t.Status,
+		"list":             t.List,
+		"due_at":           t.DueAt,
+		"tags":             t.Tags,
+		"notes":            t.Notes,
+		"estimate_minutes": t.EstimateMinutes,
+		"fields":           t.Fields,
+		"geofence":         t.Geofence,
+		"location":         t.Geofence.Point(),
+		"updated_at":       t.UpdatedAt,
+	}
+}
+
+// UpdateTodo saves the editable fields of t and refreshes t with the stored
+// document.
+func (s *Store) UpdateTodo(ctx context.Context, t *model.Todo) error {
+	return s.updateTodo(ctx, t, bson.M{"_id": t.ID})
+}
+
+// UpdateTodoIf is UpdateTodo for a todo last read at version. If it has
+// been written since, or deleted, nothing is saved and the result is
+// ErrNotFound.
+func (s *Store) UpdateTodoIf(ctx context.Context, t *model.Todo, version int64) error {
+	return s.updateTodo(ctx, t, bson.M{"_id": t.ID, "version": versionIs(version)})
+}
+
+// versionIs matches a todo's version; 0 also matches todos without one.
+func versionIs(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// bumpVersion is the $inc of every write to a todo's editable fields.
+var bumpVersion = bson.M{"version": 1}
+
+func (s *Store) updateTodo(ctx context.Context, t *model.Todo, filter bson.M) error {
+	update := bson.M{"$set": editable(t), "$inc": bumpVersion}
+	return s.retry(ctx, func() error {
+		return s.todos().FindOneAndUpdate(ctx, scope(ctx, filter), update,
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(t)
+	})
+}
+
+func (s *Store) DeleteTodo(ctx context.Context, id bson.ObjectID) error {
+	return s.retry(ctx, deleteOnce(func() (*mongo.DeleteResult, error) {
+		return s.todos().DeleteOne(ctx, scope(ctx, bson.M{"_id": id}))
+	}))
+}
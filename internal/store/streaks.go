@@ -0,0 +1,39 @@
+package store
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// DayCount is how many todos were completed on a day.
+type DayCount struct {
+	// Day is YYYY-MM-DD.
+	Day       string `bson:"_id"`
+	Completed int    `bson:"n"`
+}
+
+// CompletionDays returns the days on which todos were completed, oldest
+// first, reading days in the time zone tz (an offset such as "+02:00").
+func (s *Store) CompletionDays(ctx context.Context, tz string) ([]DayCount, error) {
+	pipeline := bson.A{
+		bson.M{"$match": scope(ctx, bson.M{"completed_at": bson.M{"$ne": nil}})},
+		bson.M{"$group": bson.M{
+			"_id": bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$completed_at", "timezone": tz}},
+			"n":   bson.M{"$sum": 1},
+		}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+	}
+	out := []DayCount{}
+	err := s.retry(ctx, func() error {
+		cur, err := s.reads(collectionName).Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
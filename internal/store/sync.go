@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const tombstoneCollection = "tombstones"
+
+// TombstoneTTL is how long deletions are remembered; a sync cursor older
+// than that can no longer be caught up.
+const TombstoneTTL = 30 * 24 * time.Hour
+
+func (s *Store) tombstones() *mongo.Collection {
+	return s.db.Collection(tombstoneCollection)
+}
+
+// SyncPos is a position in the order sync reads changes in: by time, then
+// id. The zero SyncPos is before everything.
+type SyncPos struct {
+	At time.Time
+	ID bson.ObjectID
+}
+
+// after is the filter for documents after p, timed by field.
+func (p SyncPos) after(field string) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{"$gt": p.At}},
+		bson.M{field: p.At, "_id": bson.M{"$gt": p.ID}},
+	}}
+}
+
+// ChangedTodos returns up to limit todos updated after p, in sync order.
+// It reads the primary: a lagging secondary could move a cursor past
+// writes it hasn't seen yet.
+func (s *Store) ChangedTodos(ctx context.Context, p SyncPos, limit int64) ([]model.Todo, error) {
+	out := []model.Todo{}
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit)
+	err := s.retry(ctx, func() error {
+		cur, err := s.todos().Find(ctx, scope(ctx, p.after("updated_at")), opts)
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Tombstones returns up to limit tombstones laid after p, in sync order.
+func (s *Store) Tombstones(ctx context.Context, p SyncPos, limit int64) ([]model.Tombstone, error) {
+	out := []model.Tombstone{}
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit)
+	err := s.retry(ctx, func() error {
+		cur, err := s.tombstones().Find(ctx, scope(ctx, p.after("deleted_at")), opts)
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AddTombstone records that the todo id was deleted at at.
+func (s *Store) AddTombstone(ctx context.Context, id bson.ObjectID, at time.Time) error {
+	return s.writeTombstones(ctx, []model.Tombstone{{TodoID: id, TenantID: tenant.FromContext(ctx), DeletedAt: at}})
+}
+
+// writeTombstones saves ts, replacing any tombstones of the same todos.
+func (s *Store) writeTombstones(ctx context.Context, ts []model.Tombstone) error {
+	writes := make([]mongo.WriteModel, len(ts))
+	for i, t := range ts {
+		writes[i] = mongo.NewReplaceOneModel().SetFilter(bson.M{"_id": t.TodoID}).SetReplacement(t).SetUpsert(true)
+	}
+	return s.retry(ctx, func() error {
+		_, err := s.tombstones().BulkWrite(ctx, writes)
+		return err
+	})
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/tenant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const timeCollection = "time_entries"
+
+func (s *Store) timeEntries() *mongo.Collection {
+	return s.db.Collection(timeCollection)
+}
+
+// StartTimer inserts e as the running entry of its todo, giving it a new
+// id. It returns ErrConflict if the todo already has one.
+func (s *Store) StartTimer(ctx context.Context, e *model.TimeEntry) error {
+	e.ID = bson.NewObjectID()
+	e.TenantID = tenant.FromContext(ctx)
+	e.Running = true
+	return s.retry(ctx, insertOnce(func() error {
+		_, err := s.timeEntries().InsertOne(ctx, e)
+		return err
+	}))
+}
+
+// StopTimer ends the running entry of the todo at end, adds its duration
+// to the todo's tracked time and returns it. It returns ErrNotFound if no
+// timer runs. Neither write is retried, as repeating one would stop
+// nothing or count the time twice.
+func (s *Store) StopTimer(ctx context.Context, todoID bson.ObjectID, end time.Time) (*model.TimeEntry, error) {
+	var e model.TimeEntry
+	err := s.timeEntries().FindOneAndUpdate(ctx,
+		scope(ctx, bson.M{"todo_id": todoID, "running": true}),
+		bson.M{"$set": bson.M{"end": end}, "$unset": bson.M{"running": ""}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&e)
+	if err != nil {
+		return nil, translate(err)
+	}
+	secs := int64(e.Duration(end) / time.Second)
+	_, err = s.todos().UpdateOne(ctx, scope(ctx, bson.M{"_id": todoID}),
+		bson.M{"$inc": bson.M{"tracked_seconds": secs}})
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &e, nil
+}
+
+// ListTimeEntries returns the entries started in [from, to), oldest first.
+func (s *Store) ListTimeEntries(ctx context.Context, from, to time.Time) ([]model.TimeEntry, error) {
+	out := []model.TimeEntry{}
+	filter := bson.M{"start": bson.M{"$gte": from, "$lt": to}}
+	err := s.retry(ctx, func() error {
+		cur, err := s.reads(timeCollection).Find(ctx, scope(ctx, filter),
+			options.Find().SetSort(bson.D{{Key: "start", Value: 1}}))
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
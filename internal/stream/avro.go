@@ -0,0 +1,98 @@
+package stream
+
+import (
+	"encoding/binary"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/events"
+)
+
+// Schema is the Avro schema of streamed events, in Parsing Canonical Form.
+// at and due_at are milliseconds since the Unix epoch; todo is null for
+// deletions and for events about no single todo.
+const Schema = `{"name":"todo.TodoEvent","type":"record","fields":[` +
+	`{"name":"type","type":"string"},` +
+	`{"name":"todo_id","type":"string"},` +
+	`{"name":"tenant","type":"string"},` +
+	`{"name":"at","type":"long"},` +
+	`{"name":"todo","type":["null",{"name":"todo.Todo","type":"record","fields":[` +
+	`{"name":"title","type":"string"},` +
+	`{"name":"list","type":"string"},` +
+	`{"name":"status","type":"string"},` +
+	`{"name":"completed","type":"boolean"},` +
+	`{"name":"due_at","type":["null","long"]},` +
+	`{"name":"tags","type":{"type":"array","items":"string"}}]}]}]}`
+
+// fingerprint is the CRC-64-AVRO fingerprint of Schema, which prefixes
+// every message so consumers can look the schema up.
+var fingerprint = rabin([]byte(Schema))
+
+// encodeAvro encodes e under Schema in Avro's single-object encoding.
+func encodeAvro(e events.Event) ([]byte, error) {
+	b := []byte{0xc3, 0x01}
+	b = binary.LittleEndian.AppendUint64(b, fingerprint)
+	b = appendString(b, e.Type)
+	b = appendString(b, e.TodoID)
+	b = appendString(b, e.Tenant)
+	b = appendLong(b, millis(e.At))
+	t := e.Todo
+	if t == nil {
+		return appendLong(b, 0), nil
+	}
+	b = appendLong(b, 1)
+	b = appendString(b, t.Title)
+	b = appendString(b, t.List)
+	b = appendString(b, t.CurrentStatus())
+	if t.Completed {
+		b = append(b, 1)
+	} else {
+		b = append(b, 0)
+	}
+	if t.DueAt == nil {
+		b = appendLong(b, 0)
+	} else {
+		b = appendLong(appendLong(b, 1), millis(*t.DueAt))
+	}
+	if len(t.Tags) > 0 {
+		b = appendLong(b, int64(len(t.Tags)))
+		for _, tag := range t.Tags {
+			b = appendString(b, tag)
+		}
+	}
+	return appendLong(b, 0), nil
+}
+
+func millis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// appendLong appends n zig-zag encoded as a varint.
+func appendLong(b []byte, n int64) []byte {
+	return binary.AppendVarint(b, n)
+}
+
+func appendString(b []byte, s string) []byte {
+	return append(appendLong(b, int64(len(s))), s...)
+}
+
+const rabinEmpty = 0xc15d213aa4d7a795
+
+var rabinTable = func() (t [256]uint64) {
+	for i := range t {
+		fp := uint64(i)
+		for j := 0; j < 8; j++ {
+			fp = fp>>1 ^ rabinEmpty&-(fp&1)
+		}
+		t[i] = fp
+	}
+	return t
+}()
+
+// rabin is the Rabin fingerprint Avro calls CRC-64-AVRO.
+func rabin(b []byte) uint64 {
+	fp := uint64(rabinEmpty)
+	for _, c := range b {
+		fp = fp>>8 ^ rabinTable[byte(fp)^c]
+	}
+	return fp
+}
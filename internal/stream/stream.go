@@ -0,0 +1,219 @@
+// Package stream writes domain events to a NATS subject, for analytics
+// pipelines downstream. Events go out as JSON, the events.Event as is, or
+// as Avro under Schema.
+package stream
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/events"
+)
+
+// defaultEvents are streamed when the config names none.
+var defaultEvents = []string{events.TodoCreated, events.TodoUpdated, events.TodoCompleted, events.TodoDeleted}
+
+const timeout = 10 * time.Second
+
+// Publisher keeps one connection to the NATS server, opened on the first
+// event and again after it breaks. Delivery is at most once.
+type Publisher struct {
+	c      config.Stream
+	wanted map[string]bool
+	encode func(events.Event) ([]byte, error)
+	log    *log.Logger
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// New returns a publisher for c, failing on unknown event types.
+func New(c config.Stream, logger *log.Logger) (*Publisher, error) {
+	p := &Publisher{c: c, wanted: map[string]bool{}, log: logger, encode: encodeJSON}
+	if c.Format == "avro" {
+		p.encode = encodeAvro
+	}
+	names := c.Events
+	if len(names) == 0 {
+		names = defaultEvents
+	}
+	for _, name := range names {
+		if !known(name) {
+			return nil, fmt.Errorf("stream.events: unknown event type %q", name)
+		}
+		p.wanted[name] = true
+	}
+	return p, nil
+}
+
+func known(event string) bool {
+	for _, t := range events.Types {
+		if t == event {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeJSON(e events.Event) ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// Subscribe streams the events asked for, from every tenant.
+func (p *Publisher) Subscribe(bus *events.Bus) {
+	bus.Subscribe("stream", func(e events.Event) {
+		if !p.wanted[e.Type] {
+			return
+		}
+		payload, err := p.encode(e)
+		if err == nil {
+			err = p.Publish(strings.ReplaceAll(p.c.Subject, "{type}", e.Type), payload)
+		}
+		if err != nil {
+			p.log.Printf("stream: publishing %s for %s: %v", e.Type, e.TodoID, err)
+		}
+	})
+}
+
+// Publish sends payload to subject, reconnecting once if the connection
+// has broken.
+func (p *Publisher) Publish(subject string, payload []byte) error {
+	msg := fmt.Sprintf("PUB %s %d\r\n%s\r\n", subject, len(payload), payload)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for attempt := 0; ; attempt++ {
+		err := p.write(msg)
+		if err == nil {
+			return nil
+		}
+		p.drop()
+		if attempt == 1 {
+			return err
+		}
+	}
+}
+
+// Close disconnects from the server.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.drop()
+	return nil
+}
+
+func (p *Publisher) drop() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+}
+
+// write sends msg, connecting first if need be. The caller holds mu.
+func (p *Publisher) write(msg string) error {
+	if p.conn == nil {
+		if err := p.connect(); err != nil {
+			return err
+		}
+	}
+	p.conn.SetWriteDeadline(time.Now().Add(timeout))
+	_, err := p.conn.Write([]byte(msg))
+	return err
+}
+
+// connect opens a connection and waits for the server to accept it, then
+// leaves a goroutine answering its pings.
+func (p *Publisher) connect() error {
+	u, err := url.Parse(p.c.URL)
+	if err != nil {
+		return err
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+	r := bufio.NewReader(conn)
+	line, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	var info struct {
+		TLSRequired bool `json:"tls_required"`
+	}
+	json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(line), "INFO ")), &info)
+	if u.Scheme == "tls" || info.TLSRequired {
+		tc := tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+		if err := tc.Handshake(); err != nil {
+			conn.Close()
+			return err
+		}
+		conn, r = tc, bufio.NewReader(tc)
+	}
+	opts := map[string]interface{}{"verbose": false, "pedantic": false, "name": "personal-todo", "lang": "go"}
+	if user := u.User; user != nil {
+		if pass, ok := user.Password(); ok {
+			opts["user"], opts["pass"] = user.Username(), pass
+		} else {
+			opts["auth_token"] = user.Username()
+		}
+	}
+	b, _ := json.Marshal(opts)
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", b); err != nil {
+		conn.Close()
+		return err
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			conn.Close()
+			return err
+		}
+		switch line = strings.TrimSpace(line); {
+		case line == "PONG":
+			conn.SetDeadline(time.Time{})
+			p.conn = conn
+			go p.serve(conn, r)
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			conn.Close()
+			return errors.New(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// serve answers the server's pings on conn until it closes, so an idle
+// connection isn't dropped.
+func (p *Publisher) serve(conn net.Conn, r *bufio.Reader) {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch line = strings.TrimSpace(line); {
+		case line == "PING":
+			p.mu.Lock()
+			if p.conn == conn {
+				conn.SetWriteDeadline(time.Now().Add(timeout))
+				conn.Write([]byte("PONG\r\n"))
+			}
+			p.mu.Unlock()
+		case strings.HasPrefix(line, "-ERR"):
+			p.log.Printf("stream: server error: %s", line)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Package telegram talks to the Telegram Bot API: it sends messages for the
+// notifier and runs the bot that lets a linked chat manage todos.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const apiBase = "https://api.telegram.org/bot"
+
+// pollTimeout is how long getUpdates waits for a message before returning
+// empty (long polling).
+const pollTimeout = 30 * time.Second
+
+// Client calls Bot API methods with the bot's token.
+type Client struct {
+	token string
+	http  *http.Client
+}
+
+func NewClient(token string) *Client {
+	return &Client{token: token, http: &http.Client{Timeout: pollTimeout + 10*time.Second}}
+}
+
+type update struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Text string `json:"text"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+// SendMessage posts text to a chat.
+func (c *Client) SendMessage(ctx context.Context, chatID string, text string) error {
+	b, err := json.Marshal(map[string]string{"chat_id": chatID, "text": text})
+	if err != nil {
+		return err
+	}
+	return c.call(ctx, http.MethodPost, "sendMessage", b, nil)
+}
+
+// updates long-polls for messages newer than offset.
+func (c *Client) updates(ctx context.Context, offset int64) ([]update, error) {
+	q := url.Values{
+		"offset":          {strconv.FormatInt(offset, 10)},
+		"timeout":         {strconv.Itoa(int(pollTimeout.Seconds()))},
+		"allowed_updates": {`["message"]`},
+	}
+	var out []update
+	err := c.call(ctx, http.MethodGet, "getUpdates?"+q.Encode(), nil, &out)
+	return out, err
+}
+
+// call invokes a Bot API method. Failures come back as {"ok": false} with a
+// description, whatever the status code.
+func (c *Client) call(ctx context.Context, method, path string, body []byte, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, apiBase+c.token+"/"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var out struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("telegram: %s: %w", resp.Status, err)
+	}
+	if !out.OK {
+		return fmt.Errorf("telegram: %s", out.Description)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(out.Result, result)
+}
@@ -0,0 +1,208 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/service"
+)
+
+// Channel is the notification channel name Telegram chats are linked under.
+const Channel = "telegram"
+
+const botHelp = `/add <title> – add a todo
+/list – show open todos
+/done <number> – complete a todo from /list
+/stop – stop notifications to this chat`
+
+// Bot long-polls Telegram for messages. A chat links itself with
+// "/start <link secret>"; after that it receives notifications and may add,
+// list and complete todos. Messages from other chats are refused.
+type Bot struct {
+	api          *Client
+	todos        *service.TodoService
+	integrations *service.IntegrationService
+	secret       string
+	log          *log.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+	// pending remembers the numbering of the last /list per chat, so /done
+	// can refer to todos by number.
+	pending map[string][]model.Todo
+}
+
+func NewBot(api *Client, todos *service.TodoService, integrations *service.IntegrationService, linkSecret string, logger *log.Logger) *Bot {
+	return &Bot{
+		api:          api,
+		todos:        todos,
+		integrations: integrations,
+		secret:       linkSecret,
+		log:          logger,
+		pending:      map[string][]model.Todo{},
+	}
+}
+
+func (b *Bot) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.done = make(chan struct{})
+	go b.poll(ctx)
+}
+
+// Stop ends polling, waiting for the current message to be handled until
+// ctx expires.
+func (b *Bot) Stop(ctx context.Context) error {
+	if b.cancel == nil {
+		return nil
+	}
+	b.cancel()
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Bot) poll(ctx context.Context) {
+	defer close(b.done)
+	var offset int64
+	for {
+		updates, err := b.api.updates(ctx, offset)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			b.log.Printf("telegram: %v", err)
+			select {
+			case <-time.After(5 * time.Second):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			if u.Message == nil || u.Message.Text == "" {
+				continue
+			}
+			chat := strconv.FormatInt(u.Message.Chat.ID, 10)
+			reply := b.handle(ctx, chat, u.Message.Text)
+			if err := b.api.SendMessage(ctx, chat, reply); err != nil {
+				b.log.Printf("telegram: replying to %s: %v", chat, err)
+			}
+		}
+	}
+}
+
+// handle runs one command and returns the reply.
+func (b *Bot) handle(ctx context.Context, chat, text string) string {
+	cmd, arg, _ := strings.Cut(strings.TrimSpace(text), " ")
+	cmd, _, _ = strings.Cut(cmd, "@") // "/list@MyTodoBot" in groups
+	arg = strings.TrimSpace(arg)
+
+	if cmd == "/start" {
+		if b.secret == "" || subtle.ConstantTimeCompare([]byte(arg), []byte(b.secret)) != 1 {
+			return "Send /start followed by the link secret from the server config to link this chat."
+		}
+		if err := b.integrations.Subscribe(ctx, Channel, chat); err != nil {
+			b.log.Printf("telegram: linking %s: %v", chat, err)
+			return "Sorry, linking failed."
+		}
+		return "Linked. You'll get notifications here.\n\n" + botHelp
+	}
+
+	linked, err := b.linked(ctx, chat)
+	if err != nil {
+		b.log.Printf("telegram: %v", err)
+		return "Sorry, something went wrong."
+	}
+	if !linked {
+		return "This chat isn't linked yet. Send /start <link secret> first."
+	}
+
+	switch cmd {
+	case "/add":
+		t, err := b.todos.Create(ctx, service.TodoInput{Title: arg})
+		if err != nil {
+			return b.failed(err)
+		}
+		return "Added: " + t.Title
+	case "/list":
+		return b.list(ctx, chat)
+	case "/done":
+		return b.complete(ctx, chat, arg)
+	case "/stop":
+		if err := b.integrations.Unsubscribe(ctx, Channel, chat); err != nil {
+			return b.failed(err)
+		}
+		return "Unlinked. Send /start <link secret> to link again."
+	}
+	return botHelp
+}
+
+func (b *Bot) linked(ctx context.Context, chat string) (bool, error) {
+	found, err := b.integrations.Find(ctx, Channel, chat)
+	return len(found) > 0, err
+}
+
+func (b *Bot) list(ctx context.Context, chat string) string {
+	todos, err := b.todos.List(ctx)
+	if err != nil {
+		return b.failed(err)
+	}
+	var open []model.Todo
+	for _, t := range todos {
+		if !t.Completed {
+			open = append(open, t)
+		}
+	}
+	b.mu.Lock()
+	b.pending[chat] = open
+	b.mu.Unlock()
+
+	if len(open) == 0 {
+		return "Nothing to do."
+	}
+	var sb strings.Builder
+	for i, t := range open {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, t.Title)
+	}
+	return sb.String()
+}
+
+func (b *Bot) complete(ctx context.Context, chat, arg string) string {
+	n, err := strconv.Atoi(arg)
+	b.mu.Lock()
+	open := b.pending[chat]
+	b.mu.Unlock()
+	if err != nil || n < 1 || n > len(open) {
+		return "Use /list, then /done with the todo's number."
+	}
+	t := open[n-1]
+	if _, err := b.todos.SetCompleted(ctx, t.ID.Hex(), true); err != nil {
+		return b.failed(err)
+	}
+	return "Done: " + t.Title
+}
+
+// failed turns an error into a reply, logging anything that isn't the
+// user's fault.
+func (b *Bot) failed(err error) string {
+	var ve *service.ValidationError
+	if errors.As(err, &ve) {
+		return ve.Message
+	}
+	b.log.Printf("telegram: %v", err)
+	return "Sorry, something went wrong."
+}
@@ -0,0 +1,34 @@
+// Package tenant carries the organization a request acts for. The store
+// scopes every todo, integration and push subscription to it, so tenants
+// sharing an instance never see each other's data.
+//
+// The empty ID is the default tenant: all data on single-tenant
+// deployments, and what the connectors (Google, GitHub, Jira, the bots)
+// work on.
+package tenant
+
+import (
+	"context"
+	"regexp"
+)
+
+type key struct{}
+
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// Valid reports whether id can name a tenant: lowercase letters, digits and
+// dashes, as in a DNS label.
+func Valid(id string) bool {
+	return validID.MatchString(id)
+}
+
+// NewContext returns ctx acting for tenant id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, key{}, id)
+}
+
+// FromContext returns the tenant ctx acts for, "" for the default tenant.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(key{}).(string)
+	return id
+}
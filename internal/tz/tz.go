@@ -0,0 +1,57 @@
+// Package tz carries the time zone a tenant reads its dates in: what
+// "tomorrow" means for a due date, which todos are due today and how due
+// dates are shown. Tenants that chose none get the server's zone.
+package tz
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type key struct{}
+
+// loaded caches the zones Load has read, by name.
+var loaded sync.Map
+
+// Load returns the zone with the IANA name name, such as "Europe/Berlin";
+// "" is the server's zone.
+func Load(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	if loc, ok := loaded.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	// LoadLocation reads "Local" as the server's zone, which is what the
+	// empty name is for.
+	if name == "Local" {
+		return nil, fmt.Errorf("tz: unknown time zone %q", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	loaded.Store(name, loc)
+	return loc, nil
+}
+
+// NewContext returns ctx reading dates in loc.
+func NewContext(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, key{}, loc)
+}
+
+// Lookup returns the zone ctx carries, if any.
+func Lookup(ctx context.Context) (*time.Location, bool) {
+	loc, ok := ctx.Value(key{}).(*time.Location)
+	return loc, ok && loc != nil
+}
+
+// FromContext returns the zone ctx carries, the server's if none.
+func FromContext(ctx context.Context) *time.Location {
+	if loc, ok := Lookup(ctx); ok {
+		return loc
+	}
+	return time.Local
+}
@@ -0,0 +1,13 @@
+// Package version holds the build information, set at link time:
+//
+//	go build -ldflags "-X dhruvarora9/personal-todo-golang/internal/version.Version=v1.2.0 \
+//		-X dhruvarora9/personal-todo-golang/internal/version.Commit=$(git rev-parse --short HEAD) \
+//		-X dhruvarora9/personal-todo-golang/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)" \
+//		./cmd/server
+package version
+
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
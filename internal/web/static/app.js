@@ -0,0 +1,146 @@
+// Single-page UI for the todo JSON API. No build step and no dependencies:
+// it talks to /todo with fetch and redraws the list after every change.
+(function () {
+  'use strict';
+
+  var state = { todos: [], list: null };
+  var $ = function (id) { return document.getElementById(id); };
+
+  // api calls the JSON API, unwraps the {"data": ...} envelope and turns
+  // problem responses into errors carrying their detail.
+  function api(method, path, body) {
+    var opts = { method: method, headers: { Accept: 'application/json' } };
+    if (body !== undefined) {
+      opts.headers['Content-Type'] = 'application/json';
+      opts.body = JSON.stringify(body);
+    }
+    return fetch(path, opts).then(function (res) {
+      if (res.status === 204) return null;
+      return res.json().then(function (json) {
+        if (!res.ok) throw new Error(json.detail || json.title || res.statusText);
+        return json.data;
+      });
+    });
+  }
+
+  function showError(err) {
+    $('error').textContent = err ? err.message : '';
+    $('error').hidden = !err;
+  }
+
+  function load() {
+    return api('GET', '/todo').then(function (todos) {
+      state.todos = todos;
+      render();
+    }).catch(showError);
+  }
+
+  function save(todo) {
+    return api('PUT', '/todo/' + todo.id, {
+      title: todo.title, list: todo.list, due_at: todo.due_at, completed: todo.completed
+    }).then(function () { showError(null); return load(); }).catch(showError);
+  }
+
+  function el(tag, props, children) {
+    var n = document.createElement(tag);
+    Object.keys(props || {}).forEach(function (k) { n[k] = props[k]; });
+    (children || []).forEach(function (c) { n.append(c); });
+    return n;
+  }
+
+  function lists() {
+    var seen = {};
+    state.todos.forEach(function (t) { if (t.list) seen[t.list] = true; });
+    return Object.keys(seen).sort();
+  }
+
+  function renderFilters() {
+    var names = lists();
+    var nav = $('filters');
+    nav.replaceChildren();
+    $('lists').replaceChildren.apply($('lists'), names.map(function (n) { return el('option', { value: n }); }));
+    if (!names.length) return;
+    [null].concat(names).forEach(function (name) {
+      nav.append(el('button', {
+        type: 'button',
+        textContent: name || 'All',
+        className: state.list === name ? 'active' : '',
+        onclick: function () { state.list = name; render(); }
+      }));
+    });
+  }
+
+  function meta(t) {
+    var parts = [];
+    if (t.list && !state.list) parts.push(t.list);
+    if (t.due_at) parts.push('due ' + new Date(t.due_at).toLocaleString());
+    var overdue = t.due_at && !t.completed && new Date(t.due_at) < new Date();
+    return el('span', { className: 'meta' + (overdue ? ' overdue' : ''), textContent: parts.join(' · ') });
+  }
+
+  function edit(li, t) {
+    var input = el('input', { className: 'edit', value: t.title });
+    var done = false;
+    function finish(keep) {
+      if (done) return;
+      done = true;
+      if (keep && input.value.trim() && input.value !== t.title) {
+        save(Object.assign({}, t, { title: input.value }));
+      } else {
+        render();
+      }
+    }
+    input.onkeydown = function (e) {
+      if (e.key === 'Enter') finish(true);
+      if (e.key === 'Escape') finish(false);
+    };
+    input.onblur = function () { finish(true); };
+    li.replaceChildren(input);
+    input.focus();
+  }
+
+  function render() {
+    renderFilters();
+    var shown = state.todos.filter(function (t) { return !state.list || t.list === state.list; });
+    var ul = $('todos');
+    ul.replaceChildren();
+    shown.forEach(function (t) {
+      var li = el('li', { className: t.completed ? 'done' : '' });
+      li.append(
+        el('input', {
+          type: 'checkbox', checked: t.completed, title: 'Done',
+          onchange: function () { save(Object.assign({}, t, { completed: !t.completed })); }
+        }),
+        el('span', { className: 'title', textContent: t.title, title: 'Double-click to edit',
+          ondblclick: function () { edit(li, t); } }),
+        meta(t),
+        el('button', {
+          type: 'button', textContent: '✕', title: 'Delete',
+          onclick: function () {
+            if (!confirm('Delete "' + t.title + '"?')) return;
+            api('DELETE', '/todo/' + t.id).then(load).catch(showError);
+          }
+        })
+      );
+      ul.append(li);
+    });
+    $('empty').hidden = shown.length > 0;
+  }
+
+  $('add').onsubmit = function (e) {
+    e.preventDefault();
+    var due = $('due').value;
+    api('POST', '/todo', {
+      title: $('title').value,
+      list: $('list').value || state.list || '',
+      due_at: due ? new Date(due).toISOString() : null
+    }).then(function () {
+      $('title').value = '';
+      $('due').value = '';
+      showError(null);
+      return load();
+    }).catch(showError);
+  };
+
+  load();
+})();
@@ -0,0 +1,13 @@
+// Service worker for Web Push: shows pushed todo notifications even when no
+// tab is open. Register it and subscribe with the key from GET /push/key.
+self.addEventListener('push', function (event) {
+  var data = event.data ? event.data.json() : {};
+  event.waitUntil(
+    self.registration.showNotification(data.title || 'Todo', { body: data.body || '' })
+  );
+});
+
+self.addEventListener('notificationclick', function (event) {
+  event.notification.close();
+  event.waitUntil(clients.openWindow('/'));
+});
@@ -0,0 +1,64 @@
+<!doctype html>
+<html lang="{{locale}}">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{t "Todo"}}</title>
+  <link rel="stylesheet" href="/static/app.css">
+  <!-- Optional: without htmx every action falls back to a full-page form post. -->
+  <script src="https://unpkg.com/htmx.org@1.9.12" crossorigin="anonymous" defer></script>
+</head>
+<body>
+  <main>
+    <h1>{{t "Todo"}}</h1>
+    <form method="post" action="/html/todos"
+          hx-post="/html/todos" hx-target="#todos" hx-swap="afterbegin"
+          hx-on::after-request="if (event.detail.successful && !event.detail.xhr.getResponseHeader('HX-Retarget')) { this.reset(); document.getElementById('error-slot').innerHTML = '' }">
+      <input name="title" type="text" placeholder="{{t "Add your todo"}}" value="{{.Title}}" autocomplete="off" required>
+      <input name="list" type="text" placeholder="{{t "List"}}" value="{{.List}}" autocomplete="off">
+      <button type="submit">{{t "Add"}}</button>
+    </form>
+    <div id="error-slot">{{template "error" .Error}}</div>
+    <ul id="todos">
+      {{range .Rows}}{{template "row" .}}{{end}}
+    </ul>
+    {{if not .Rows}}<p id="empty">{{t "Nothing to do."}}</p>{{end}}
+    {{if gt .Pages 1}}
+    <nav>
+      {{if gt .Page 1}}<a href="/html?page={{.Prev}}">{{t "← Newer"}}</a>{{end}}
+      <span>{{t "Page %d of %d" .Page .Pages}}</span>
+      {{if lt .Page .Pages}}<a href="/html?page={{.Next}}">{{t "Older →"}}</a>{{end}}
+    </nav>
+    {{end}}
+  </main>
+</body>
+</html>
+
+{{define "error"}}{{if .}}<p id="error" role="alert">{{.}}</p>{{end}}{{end}}
+
+{{define "row"}}{{with .Todo}}
+<li id="todo-{{.ID}}"{{if .Completed}} class="done"{{end}}>
+  {{if $.Editing}}
+  <form method="post" action="/html/todos/{{.ID}}?page={{$.Page}}"
+        hx-post="/html/todos/{{.ID}}?page={{$.Page}}" hx-target="#todo-{{.ID}}" hx-swap="outerHTML">
+    <input class="edit" name="title" type="text" value="{{.Title}}" required autofocus>
+    <input name="list" type="text" placeholder="{{t "List"}}" value="{{.List}}">
+    <button type="submit">{{t "Save"}}</button>
+    <a href="/html?page={{$.Page}}" hx-get="/html/todos/{{.ID}}?page={{$.Page}}" hx-target="#todo-{{.ID}}" hx-swap="outerHTML">{{t "Cancel"}}</a>
+  </form>
+  {{else}}
+  <form method="post" action="/html/todos/{{.ID}}/toggle?page={{$.Page}}"
+        hx-post="/html/todos/{{.ID}}/toggle?page={{$.Page}}" hx-target="#todo-{{.ID}}" hx-swap="outerHTML">
+    <button type="submit" title="{{if .Completed}}{{t "Mark as not done"}}{{else}}{{t "Mark as done"}}{{end}}">{{if .Completed}}☑{{else}}☐{{end}}</button>
+  </form>
+  <a class="title" href="/html/todos/{{.ID}}/edit?page={{$.Page}}"
+     hx-get="/html/todos/{{.ID}}/edit?page={{$.Page}}" hx-target="#todo-{{.ID}}" hx-swap="outerHTML">{{.Title}}</a>
+  <span class="meta">{{.List}}{{if .DueAt}} {{t "due %s" (.DueAt.Format "2006-01-02 15:04")}}{{end}}</span>
+  <form method="post" action="/html/todos/{{.ID}}/delete?page={{$.Page}}"
+        hx-post="/html/todos/{{.ID}}/delete?page={{$.Page}}" hx-target="#todo-{{.ID}}" hx-swap="outerHTML"
+        hx-confirm="{{t "Delete this todo?"}}">
+    <button type="submit" title="{{t "Delete"}}">✕</button>
+  </form>
+  {{end}}
+</li>
+{{end}}{{end}}
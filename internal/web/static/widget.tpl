@@ -0,0 +1,31 @@
+<!doctype html>
+<html lang="{{locale}}">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{.Title}}</title>
+  <style>
+    body { margin: 0; font: 14px/1.4 system-ui, sans-serif; color: #333; background: #fff; }
+    h1 { margin: 0; padding: .4rem .75rem; font-size: 1rem; background: #b88f92; color: #fff; }
+    ul { list-style: none; margin: 0; padding: 0; }
+    li { display: flex; gap: .5rem; padding: .4rem .75rem; border-top: 1px solid #eee; }
+    li .title { flex: 1; }
+    li.done .title { text-decoration: line-through; color: #999; }
+    li .meta { font-size: .8rem; color: #888; }
+    p { margin: 0; padding: .4rem .75rem; color: #888; }
+  </style>
+</head>
+<body>
+  <h1>{{.Title}}</h1>
+  <ul>
+    {{range .Todos}}
+    <li{{if .Completed}} class="done"{{end}}>
+      <span>{{if .Completed}}☑{{else}}☐{{end}}</span>
+      <span class="title">{{.Title}}</span>
+      {{if .DueAt}}<span class="meta">{{t "due %s" (.DueAt.Format "2006-01-02")}}</span>{{end}}
+    </li>
+    {{end}}
+  </ul>
+  {{if not .Todos}}<p>{{t "Nothing to do."}}</p>{{end}}
+</body>
+</html>
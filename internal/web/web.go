@@ -0,0 +1,91 @@
+// Package web holds the HTML templates and static assets served by the
+// server, embedded into the binary.
+package web
+
+import (
+	"embed"
+	"errors"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"dhruvarora9/personal-todo-golang/internal/i18n"
+)
+
+//go:embed static
+var embedded embed.FS
+
+// overlayFS serves files from dir when they exist there and from the embedded
+// static directory otherwise, so a deployment can replace single templates or
+// assets without shipping the whole set.
+type overlayFS struct {
+	dir  fs.FS
+	base fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if o.dir != nil {
+		f, err := o.dir.Open(name)
+		if err == nil || !errors.Is(err, fs.ErrNotExist) {
+			return f, err
+		}
+	}
+	return o.base.Open(name)
+}
+
+// NewFS returns the filesystem templates and static files are read from.
+// overrideDir may be empty.
+func NewFS(overrideDir string) fs.FS {
+	base, err := fs.Sub(embedded, "static")
+	if err != nil {
+		panic(err)
+	}
+	o := overlayFS{base: base}
+	if overrideDir != "" {
+		o.dir = os.DirFS(overrideDir)
+	}
+	return o
+}
+
+// ParseTemplates parses every template in fsys, with the functions of
+// package i18n translating into its default locale.
+func ParseTemplates(fsys fs.FS) (*template.Template, error) {
+	names, err := fs.Glob(fsys, "*.tpl")
+	if err != nil {
+		return nil, err
+	}
+	t := template.New("").Funcs(i18n.Funcs(i18n.Default))
+	if len(names) == 0 {
+		return t, nil
+	}
+	return t.ParseFS(fsys, names...)
+}
+
+// IndexHandler serves the single-page app's index.html.
+func IndexHandler(fsys fs.FS) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := fs.ReadFile(fsys, "index.html")
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(b)
+	})
+}
+
+// StaticHandler serves the static assets. Templates live in the same tree
+// but are not meant to be downloaded as-is.
+func StaticHandler(fsys fs.FS) http.Handler {
+	files := http.FileServer(http.FS(fsys))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(path.Clean(r.URL.Path), ".tpl") {
+			http.NotFound(w, r)
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,396 @@
+// Package server assembles the todo API into an http.Handler that can run on
+// its own (see cmd/server) or be mounted inside a larger application.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"dhruvarora9/personal-todo-golang/internal/cache"
+	"dhruvarora9/personal-todo-golang/internal/caldav"
+	"dhruvarora9/personal-todo-golang/internal/config"
+	"dhruvarora9/personal-todo-golang/internal/events"
+	"dhruvarora9/personal-todo-golang/internal/flags"
+	"dhruvarora9/personal-todo-golang/internal/gcal"
+	"dhruvarora9/personal-todo-golang/internal/github"
+	"dhruvarora9/personal-todo-golang/internal/handler"
+	"dhruvarora9/personal-todo-golang/internal/inbound"
+	"dhruvarora9/personal-todo-golang/internal/jira"
+	"dhruvarora9/personal-todo-golang/internal/jobs"
+	"dhruvarora9/personal-todo-golang/internal/middleware"
+	"dhruvarora9/personal-todo-golang/internal/model"
+	"dhruvarora9/personal-todo-golang/internal/mqtt"
+	"dhruvarora9/personal-todo-golang/internal/mstodo"
+	"dhruvarora9/personal-todo-golang/internal/notify"
+	"dhruvarora9/personal-todo-golang/internal/render"
+	"dhruvarora9/personal-todo-golang/internal/scheduler"
+	"dhruvarora9/personal-todo-golang/internal/service"
+	"dhruvarora9/personal-todo-golang/internal/store"
+	"dhruvarora9/personal-todo-golang/internal/stream"
+	"dhruvarora9/personal-todo-golang/internal/telegram"
+	"dhruvarora9/personal-todo-golang/internal/web"
+	"github.com/go-chi/chi"
+	chimw "github.com/go-chi/chi/middleware"
+)
+
+type (
+	// Config holds the server settings; start from DefaultConfig or
+	// LoadConfig.
+	Config = config.Config
+	// Database holds the settings for the MongoDB connection.
+	Database = config.Database
+	// Todo is the stored form of a todo.
+	Todo = model.Todo
+	// Integration routes todo events to a notification channel.
+	Integration = model.Integration
+)
+
+// Store is the persistence the API runs on.
+type Store interface {
+	service.Store
+	service.IntegrationStore
+	service.SmartListStore
+	service.ShareStore
+	service.FeedStore
+	service.StreakStore
+	service.GoalStore
+	service.SyncStore
+	service.PushStore
+	service.SettingsStore
+	caldav.Store
+	gcal.Store
+	github.Store
+	jira.Store
+}
+
+// DefaultConfig returns the settings used when no config file is given.
+func DefaultConfig() Config {
+	return config.Default()
+}
+
+// LoadConfig reads a YAML config file; a missing file yields the defaults.
+func LoadConfig(path string) (Config, error) {
+	return config.Load(path)
+}
+
+// ConnectMongo returns a Store backed by the MongoDB deployment db
+// describes, creating its indexes first if db asks for it. Call the
+// returned close function on shutdown.
+func ConnectMongo(ctx context.Context, db Database) (Store, func(context.Context) error, error) {
+	s, err := store.Connect(ctx, db)
+	if err != nil {
+		return nil, nil, err
+	}
+	if db.AutoIndexes {
+		if err := s.EnsureIndexes(ctx); err != nil {
+			s.Close(ctx)
+			return nil, nil, err
+		}
+	}
+	return s, s.Close, nil
+}
+
+type options struct {
+	logger    *log.Logger
+	accessLog io.Writer
+	now       func() time.Time
+}
+
+// Option adjusts how NewServer builds the handler.
+type Option func(*options)
+
+// WithLogger sets where errors are logged; the default is os.Stderr.
+func WithLogger(l *log.Logger) Option {
+	return func(o *options) { o.logger = l }
+}
+
+// WithAccessLog sets where the access log goes; the default is os.Stdout.
+// Pass io.Discard to turn it off.
+func WithAccessLog(w io.Writer) Option {
+	return func(o *options) { o.accessLog = w }
+}
+
+// WithClock replaces time.Now, mostly for tests.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) { o.now = now }
+}
+
+// Server is the complete todo API. It also owns the background workers,
+// which Close stops.
+type Server struct {
+	http.Handler
+	jobs    *jobs.Queue
+	sched   *scheduler.Scheduler
+	bot     *telegram.Bot
+	bus     *events.Bus
+	mqtt    *mqtt.Publisher
+	stream  *stream.Publisher
+	exports *handler.Exports
+}
+
+// Close stops the background workers and periodic tasks, waiting for running
+// ones until ctx expires, and removes exports not yet downloaded.
+func (s *Server) Close(ctx context.Context) error {
+	if s.bot != nil {
+		if err := s.bot.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	if err := s.sched.Stop(ctx); err != nil {
+		return err
+	}
+	s.bus.Close()
+	if s.mqtt != nil {
+		s.mqtt.Close()
+	}
+	if s.stream != nil {
+		s.stream.Close()
+	}
+	err := s.jobs.Stop(ctx)
+	s.exports.Close()
+	return err
+}
+
+// NewServer returns the complete todo API, middleware included, serving s.
+func NewServer(cfg Config, s Store, opts ...Option) (*Server, error) {
+	o := options{
+		logger:    log.New(os.Stderr, "", log.LstdFlags),
+		accessLog: os.Stdout,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	assets := web.NewFS(cfg.AssetsDir)
+	templates, err := web.ParseTemplates(assets)
+	if err != nil {
+		return nil, err
+	}
+	rnd := render.New(templates, cfg.Envelope)
+
+	r := chi.NewRouter()
+	ips, err := middleware.NewIPResolver(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	r.Use(middleware.NewAccessLogger(cfg.AccessLog, ips, o.accessLog, o.logger).Handler)
+	r.Use(middleware.Language)
+	sink, err := middleware.NewErrorSink(cfg.ErrorReporting, o.logger)
+	if err != nil {
+		return nil, err
+	}
+	r.Use(middleware.ReportErrors(sink, rnd))
+	if cfg.IPFilter.Enabled() {
+		f, err := middleware.NewIPFilter(cfg.IPFilter, ips, rnd)
+		if err != nil {
+			return nil, err
+		}
+		r.Use(f.Handler)
+	}
+	if cfg.RateLimit.Enabled {
+		r.Use(middleware.NewRateLimiter(cfg.RateLimit, ips, rnd).Handler)
+	}
+	if cfg.RequestTimeout > 0 {
+		r.Use(chimw.Timeout(cfg.RequestTimeout))
+	}
+	maint := middleware.NewMaintenance(cfg.Maintenance, rnd)
+	r.Use(maint.Handler)
+	deprecations := middleware.NewDeprecations(cfg.Deprecations, o.now)
+	r.Use(deprecations.Handler)
+	features := flags.New(cfg.Features)
+	queue := jobs.New(cfg.Jobs, o.logger)
+	sched := scheduler.New(cfg.Schedules, o.logger, o.now)
+	bus := events.NewBus(o.logger)
+	notifiers := notify.NewRegistry()
+	notifiers.Register("log", notify.LogNotifier{Log: o.logger})
+	notifiers.Register("webhook", notify.NewWebhookNotifier())
+	notifiers.Register("slack", notify.NewSlackNotifier(cfg.Slack.BotToken))
+	notifiers.Register("discord", notify.NewDiscordNotifier())
+	var tg *telegram.Client
+	if cfg.Telegram.BotToken != "" {
+		tg = telegram.NewClient(cfg.Telegram.BotToken)
+		notifiers.Register(telegram.Channel, notify.NewTelegramNotifier(tg))
+	}
+	if cfg.WebPush.Enabled() {
+		notifiers.Register("webpush", notify.NewWebPushNotifier(cfg.WebPush, s))
+	}
+	if cfg.Twilio.Enabled() {
+		notifiers.Register("sms", notify.NewSMSNotifier(cfg.Twilio, o.logger, o.now))
+	}
+	if cfg.SMTP.Enabled() {
+		notifiers.Register("email", notify.NewEmailNotifier(cfg.SMTP))
+	}
+	notify.NewDispatcher(notifiers, s, s, queue, bus, o.logger)
+
+	todos := service.NewTodoService(s, bus, o.now, o.logger)
+	integrations := service.NewIntegrationService(s, notifiers.Channels, o.now)
+	shares := service.NewShareService(s, todos, o.now)
+	feed := service.NewFeedService(s, bus, o.logger)
+	streaks := service.NewStreakService(s, bus, o.now, o.logger)
+	goals := service.NewGoalService(s, o.now)
+	h := handler.New(todos, integrations, service.NewSmartListService(s, o.now), service.NewPushService(s, o.now), shares, feed, streaks, goals, service.NewSyncService(s, todos, o.now), rnd, o.logger)
+	exportDir := cfg.ExportDir
+	if exportDir == "" {
+		exportDir = filepath.Join(os.TempDir(), "todo-exports")
+	}
+	exports, err := handler.NewExports(todos, goals, queue, exportDir, rnd, o.now, o.logger)
+	if err != nil {
+		return nil, err
+	}
+	prefs := service.NewSettingsService(s, o.now)
+	settings := handler.NewSettings(prefs, rnd, o.logger)
+	r.Handle("/", web.IndexHandler(assets))
+	r.Get("/version", h.Version)
+	// Share links are public: the token alone grants access.
+	r.Get("/share/{token}", h.OpenShare)
+	r.Get("/share/{token}/widget", h.ShareWidget)
+	r.Get("/share/{token}/qr.png", h.ShareQR)
+	r.Handle("/static/*", http.StripPrefix("/static/", web.StaticHandler(assets)))
+	todoRoutes := h.TodoRoutes()
+	if cfg.Cache.TTL > 0 {
+		c := cache.New(cfg.Cache.TTL, o.now)
+		c.Subscribe(bus)
+		todoRoutes = c.Handler(todoRoutes)
+	}
+	var email *handler.Email
+	if c := cfg.InboundEmail; c.Enabled() {
+		email = handler.NewEmail(todos, inbound.NewAddresses(c.Domain, c.Secret), rnd, c.MailgunSigningKey, c.SESToken, o.now)
+		// Mail names its tenant by the address it was sent to.
+		r.Mount("/integrations/email", email.Routes())
+	}
+	// The JSON API, served both unversioned and under /api/v1, which is
+	// where clients should move; config deprecations mark the old paths.
+	api := func(r chi.Router) {
+		r.Mount("/todo", todoRoutes) // add a group of routes that share common prefix.
+		r.Mount("/integrations", h.IntegrationRoutes())
+		r.Mount("/smartlists", h.SmartListRoutes())
+		r.Mount("/fields", h.FieldRoutes())
+		r.Mount("/shares", h.ShareRoutes())
+		r.Mount("/goals", h.GoalRoutes())
+		r.Mount("/lists", h.ListRoutes())
+		r.With(features.Require(flags.Sync)).Mount("/sync", h.SyncRoutes())
+		r.Mount("/account", exports.Routes())
+		r.Mount("/settings", settings.Routes())
+		r.With(features.Require(flags.Import)).Mount("/import", handler.NewImports(todos, mstodo.NewClient(), rnd, o.now).Routes())
+		r.Get("/feed", h.Feed)
+		r.Get("/reports/weekly", h.WeeklyReport)
+		// Requests carry no user, so "me" is the tenant.
+		r.Get("/me/streaks", h.Streaks)
+		if email != nil {
+			r.Get("/email/address", email.Address)
+		}
+		if cfg.WebPush.Enabled() {
+			r.Mount("/push", h.PushRoutes(cfg.WebPush.PublicKey))
+		}
+	}
+	// Everything a tenant reaches; the connectors below stay on the
+	// default tenant.
+	r.Group(func(r chi.Router) {
+		if cfg.Tenancy.Enabled() {
+			r.Use(middleware.Tenancy(cfg.Tenancy, rnd))
+		}
+		r.Use(settings.Localize)
+		api(r)
+		r.Route("/api/v1", api)
+		r.Get("/t/{slug}", h.OpenSlug)
+		// Native clients find CalDAV through the well-known URL. Tenancy
+		// by header rules most of them out; subdomains work.
+		dav := r.With(features.Require(flags.CalDAV))
+		dav.Handle("/.well-known/caldav", http.RedirectHandler("/caldav/", http.StatusMovedPermanently))
+		dav.Mount("/caldav", handler.NewCalDAV(caldav.New(s, todos), rnd).Routes())
+		r.Mount("/html", h.PageRoutes())
+	})
+	if cfg.Google.Enabled() {
+		sync := gcal.New(cfg.Google, s, todos, o.logger, o.now)
+		sync.Subscribe(bus, queue)
+		if err := sched.Register("gcal.pull", "@every 5m", sync.Pull); err != nil {
+			return nil, err
+		}
+		r.Mount("/integrations/google", handler.NewGoogle(sync, rnd).Routes())
+	}
+	if cfg.Twilio.Enabled() && cfg.Twilio.WebhookURL != "" {
+		r.Mount("/integrations/twilio", handler.NewTwilio(todos, integrations, prefs, rnd, cfg.Twilio.AuthToken, cfg.Twilio.WebhookURL).Routes())
+	}
+	if cfg.GitHub.WebhookSecret != "" {
+		sync := github.New(cfg.GitHub, s, todos, o.logger)
+		sync.Subscribe(bus, queue)
+		r.Mount("/integrations/github", handler.NewGitHub(sync, rnd, cfg.GitHub.WebhookSecret).Routes())
+	}
+	if cfg.Jira.Enabled() {
+		conn := jira.New(cfg.Jira, s, todos, o.logger)
+		conn.Subscribe(bus, queue)
+		if err := sched.Register("jira.pull", "@every 5m", conn.Pull); err != nil {
+			return nil, err
+		}
+	}
+	var mq *mqtt.Publisher
+	if cfg.MQTT.Enabled() {
+		var err error
+		if mq, err = mqtt.New(cfg.MQTT, o.logger); err != nil {
+			return nil, err
+		}
+		mq.Subscribe(bus)
+	}
+	var st *stream.Publisher
+	if cfg.Stream.Enabled() {
+		var err error
+		if st, err = stream.New(cfg.Stream, o.logger); err != nil {
+			return nil, err
+		}
+		st.Subscribe(bus)
+	}
+	if cfg.Voice.Token != "" {
+		r.With(features.Require(flags.Voice)).Mount("/integrations/voice", handler.NewVoice(todos, rnd, cfg.Voice.Token, cfg.Voice.AlexaSkillID).Routes())
+	}
+	if cfg.Slack.SigningSecret != "" {
+		r.Mount("/integrations/slack", handler.NewSlack(todos, rnd, cfg.Slack.SigningSecret, o.now).Routes())
+	}
+	pool, _ := s.(handler.PoolSource)
+	if months := cfg.Archive.AfterMonths; months > 0 {
+		err := sched.Register("archive", "@daily", func(ctx context.Context) error {
+			n, err := todos.Archive(ctx, months)
+			if n > 0 {
+				o.logger.Printf("archive: moved %d todos", n)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := sched.Register("digest", "0 * * * *", todos.SendDigests); err != nil {
+		return nil, err
+	}
+	if err := sched.Register("exports.expire", "@every 10m", exports.Expire); err != nil {
+		return nil, err
+	}
+	// Todos saved before fuzzy search existed lack its index; catch them up
+	// once per start, off the request path.
+	queue.Register("search.reindex", func(ctx context.Context, _ json.RawMessage) error {
+		n, err := todos.ReindexSearch(ctx)
+		if n > 0 {
+			o.logger.Printf("search: indexed %d todos", n)
+		}
+		return err
+	})
+	if _, err := queue.Enqueue("search.reindex", nil); err != nil {
+		return nil, err
+	}
+	r.Mount("/admin", handler.NewAdmin(rnd, cfg.AdminToken, maint, features, queue, sched, pool, deprecations).Routes())
+
+	srv := &Server{Handler: r, jobs: queue, sched: sched, bus: bus, mqtt: mq, stream: st, exports: exports}
+	if tg != nil {
+		srv.bot = telegram.NewBot(tg, todos, integrations, cfg.Telegram.LinkSecret, o.logger)
+		srv.bot.Start()
+	}
+	queue.Start()
+	sched.Start()
+	return srv, nil
+}